@@ -1,11 +1,14 @@
 package main
 
 import (
+	"encoding/json"
 	"log"
+	"os"
 
 	"github.com/MCantyDev/city-explorer-server/internal/config"
 	"github.com/MCantyDev/city-explorer-server/internal/database"
 	"github.com/MCantyDev/city-explorer-server/internal/routes"
+	"github.com/MCantyDev/city-explorer-server/internal/services"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
@@ -14,6 +17,9 @@ func main() {
 	// Load environment variables
 	config.Load()
 
+	// Setup External API Client (Live, Record or Replay)
+	services.SetupExternalClient()
+
 	// Connect to the Database
 
 	err := database.Connect(config.Cfg.Database.Name)
@@ -21,6 +27,12 @@ func main() {
 		log.Fatalf("Error Occured: %s", err)
 	}
 
+	// Run a Subcommand instead of the Server (e.g. 'go run ./cmd preload-countries')
+	if len(os.Args) > 1 {
+		runCommand(os.Args[1])
+		return
+	}
+
 	// Setup Gin Server Router
 
 	gin.SetMode(gin.DebugMode)
@@ -29,7 +41,8 @@ func main() {
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:5173"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Content-Type", "Authorization"},
+		AllowHeaders:     []string{"Content-Type", "Authorization", "If-Match", "X-Share-Password"},
+		ExposeHeaders:    []string{"ETag"}, // Trip versions (Sent back in If-Match)
 		AllowCredentials: true,
 	}))
 
@@ -37,3 +50,20 @@ func main() {
 	router.Run(":5050")
 
 }
+
+// runCommand - Runs a one-off maintenance command against the Database
+func runCommand(command string) {
+	switch command {
+	case "preload-countries":
+		report, err := services.PreloadCountries()
+		if err != nil {
+			log.Fatalf("Error Occured: %s", err)
+		}
+		out, _ := json.MarshalIndent(report, "", "  ")
+		log.Printf("Countries preloaded: %d inserted, %d updated, %d unchanged, %d failed\n%s",
+			len(report.Inserted), len(report.Updated), report.Unchanged, len(report.Failed), out)
+
+	default:
+		log.Fatalf("Unknown command '%s' (Available: preload-countries)", command)
+	}
+}
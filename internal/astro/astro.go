@@ -0,0 +1,141 @@
+package astro
+
+/* Astronomy
+
+- Sunrise, sunset, twilight and golden hour from coordinates and a date (NOAA sunrise equation, accurate to ~1 minute)
+- Moon phase and illumination from a date (Mean synodic month)
+- Computed locally, no external API
+*/
+
+import (
+	"math"
+	"time"
+
+	_ "time/tzdata" // Embedded IANA timezone database (The host may not have one)
+)
+
+// Sun altitudes (Degrees) that define each event
+const (
+	SunriseAltitude    = -0.833 // Upper limb on the horizon (Includes refraction)
+	CivilTwilight      = -6.0   // Dawn / Dusk
+	GoldenHourAltitude = 6.0    // Golden hour runs between sunrise and this altitude (and back again before sunset)
+
+	SynodicMonth = 29.530588853 // Days between new moons
+
+	julianUnixEpoch = 2440587.5 // Julian date of 1970-01-01 00:00 UTC
+	julianJ2000     = 2451545.0 // Julian date of 2000-01-01 12:00 UTC
+	obliquity       = 23.4397   // Tilt of the Earth's axis (Degrees)
+)
+
+var moonPhases = []string{"New Moon", "Waxing Crescent", "First Quarter", "Waxing Gibbous", "Full Moon", "Waning Gibbous", "Last Quarter", "Waning Crescent"}
+
+// Sun events for a single day (Times are nil when the sun never reaches the altitude that day)
+type SunTimes struct {
+	Dawn            *time.Time    `json:"dawn"`
+	Sunrise         *time.Time    `json:"sunrise"`
+	GoldenHourEnd   *time.Time    `json:"golden_hour_end"` // Morning golden hour is Sunrise -> GoldenHourEnd
+	SolarNoon       time.Time     `json:"solar_noon"`
+	GoldenHourStart *time.Time    `json:"golden_hour_start"` // Evening golden hour is GoldenHourStart -> Sunset
+	Sunset          *time.Time    `json:"sunset"`
+	Dusk            *time.Time    `json:"dusk"`
+	DayLength       time.Duration `json:"-"`
+	PolarDay        bool          `json:"polar_day"`   // Sun never sets
+	PolarNight      bool          `json:"polar_night"` // Sun never rises
+}
+
+type MoonPhase struct {
+	Age          float64 `json:"age"`          // Days since the last new moon
+	Fraction     float64 `json:"fraction"`     // 0 = New, 0.5 = Full
+	Illumination float64 `json:"illumination"` // Lit fraction of the disc (0 - 1)
+	Phase        string  `json:"phase"`
+}
+
+// Sun - Sun events on the calendar date of 'date' (in its location) at the given coordinates (Times are returned in the same location)
+func Sun(date time.Time, lat float64, lon float64) SunTimes {
+	loc := date.Location()
+
+	// Days since J2000 at noon UTC on the calendar date, shifted to local solar noon
+	noon := time.Date(date.Year(), date.Month(), date.Day(), 12, 0, 0, 0, time.UTC)
+	n := math.Round(julianDate(noon) - julianJ2000)
+	meanNoon := n - lon/360
+
+	anomaly := normaliseDegrees(357.5291 + 0.98560028*meanNoon)
+	m := radians(anomaly)
+	centre := 1.9148*math.Sin(m) + 0.02*math.Sin(2*m) + 0.0003*math.Sin(3*m)
+	longitude := radians(normaliseDegrees(anomaly + centre + 180 + 102.9372))
+
+	transit := julianJ2000 + meanNoon + 0.0053*math.Sin(m) - 0.0069*math.Sin(2*longitude)
+	declination := math.Asin(math.Sin(longitude) * math.Sin(radians(obliquity)))
+
+	times := SunTimes{SolarNoon: fromJulianDate(transit).In(loc)}
+
+	// eventsAt - Morning and evening times the sun passes an altitude (nil if it never does)
+	eventsAt := func(altitude float64) (*time.Time, *time.Time, float64) {
+		cosHourAngle := (math.Sin(radians(altitude)) - math.Sin(radians(lat))*math.Sin(declination)) / (math.Cos(radians(lat)) * math.Cos(declination))
+		if cosHourAngle < -1 || cosHourAngle > 1 {
+			return nil, nil, cosHourAngle
+		}
+		hourAngle := degrees(math.Acos(cosHourAngle))
+		morning := fromJulianDate(transit - hourAngle/360).In(loc)
+		evening := fromJulianDate(transit + hourAngle/360).In(loc)
+		return &morning, &evening, cosHourAngle
+	}
+
+	var cosSunrise float64
+	times.Sunrise, times.Sunset, cosSunrise = eventsAt(SunriseAltitude)
+	times.Dawn, times.Dusk, _ = eventsAt(CivilTwilight)
+	times.GoldenHourEnd, times.GoldenHourStart, _ = eventsAt(GoldenHourAltitude)
+
+	switch {
+	case times.Sunrise != nil:
+		times.DayLength = times.Sunset.Sub(*times.Sunrise)
+	case cosSunrise < -1:
+		times.PolarDay = true
+		times.DayLength = 24 * time.Hour
+	default:
+		times.PolarNight = true
+	}
+
+	return times
+}
+
+// Moon - Moon phase at a moment in time
+func Moon(t time.Time) MoonPhase {
+	// Days since a known new moon (2000-01-06 18:14 UTC)
+	age := math.Mod(julianDate(t)-2451550.26, SynodicMonth)
+	if age < 0 {
+		age += SynodicMonth
+	}
+	fraction := age / SynodicMonth
+
+	return MoonPhase{
+		Age:          math.Round(age*10) / 10,
+		Fraction:     math.Round(fraction*1000) / 1000,
+		Illumination: math.Round((1-math.Cos(2*math.Pi*fraction))/2*1000) / 1000,
+		Phase:        moonPhases[int(math.Floor(fraction*8+0.5))%8],
+	}
+}
+
+func julianDate(t time.Time) float64 {
+	return float64(t.UnixMilli())/86400000 + julianUnixEpoch
+}
+
+func fromJulianDate(jd float64) time.Time {
+	return time.UnixMilli(int64(math.Round((jd - julianUnixEpoch) * 86400000))).UTC().Truncate(time.Second)
+}
+
+func normaliseDegrees(d float64) float64 {
+	d = math.Mod(d, 360)
+	if d < 0 {
+		d += 360
+	}
+	return d
+}
+
+func radians(d float64) float64 {
+	return d * math.Pi / 180
+}
+
+func degrees(r float64) float64 {
+	return r * 180 / math.Pi
+}
@@ -18,11 +18,36 @@ type Config struct {
 	JWT JWTConfig
 
 	// External API URLs
-	PhotonAPI        ExternalAPI
-	RestCountriesAPI ExternalAPI
-	OpenWeatherAPI   SecureExternalAPI
-	OpenTripAPI      SecureExternalAPI
-	OpenTripXIDAPI   SecureExternalAPI
+	PhotonAPI              ExternalAPI
+	PhotonReverseAPI       ExternalAPI
+	RestCountriesAPI       ExternalAPI
+	RestCountriesListAPI   ExternalAPI
+	RestCountriesAllAPI    ExternalAPI
+	OpenWeatherAPI         SecureExternalAPI
+	OpenWeatherHourlyAPI   SecureExternalAPI
+	OpenWeatherMinutelyAPI SecureExternalAPI
+	OpenWeatherAlertsAPI   SecureExternalAPI
+	OpenMeteoAPI           ExternalAPI
+	OpenMeteoHourlyAPI     ExternalAPI
+	OpenMeteoMinutelyAPI   ExternalAPI
+	OpenWeatherAirAPI      SecureExternalAPI
+	OpenMeteoAirAPI        ExternalAPI
+	OpenMeteoArchiveAPI    ExternalAPI
+	OpenTripAPI            SecureExternalAPI
+	OpenTripXIDAPI         SecureExternalAPI
+	ECBRatesAPI            ExternalAPI
+
+	// Weather Provider Selection
+	Weather WeatherConfig
+
+	// Air Quality Provider Selection
+	AirQuality AirQualityConfig
+
+	// Exchange Rate Provider Selection
+	ExchangeRates ExchangeRatesConfig
+
+	// External API Fixtures (Record / Replay)
+	Fixtures FixturesConfig
 }
 
 type DatabaseConfig struct {
@@ -37,6 +62,42 @@ type JWTConfig struct {
 	SecretKey []byte
 }
 
+// Modes for External API Fixtures
+const (
+	FixtureModeLive   = "live"   // Call External APIs as normal
+	FixtureModeRecord = "record" // Call External APIs and save every response to the fixtures directory
+	FixtureModeReplay = "replay" // Never touch the network, serve responses from the fixtures directory
+)
+
+// Weather Providers
+const (
+	WeatherProviderOpenWeather = "openweather"
+	WeatherProviderOpenMeteo   = "openmeteo"
+)
+
+type WeatherConfig struct {
+	Provider string // Primary Provider (the other provider is used as a fallback)
+}
+
+type AirQualityConfig struct {
+	Provider string // Primary Provider (Uses the same provider names as Weather)
+}
+
+// Exchange Rate Providers
+const (
+	ExchangeRateProviderECB     = "ecb"     // European Central Bank daily XML feed
+	ExchangeRateProviderFixture = "fixture" // Static rates file in the fixtures directory (Offline stand-in)
+)
+
+type ExchangeRatesConfig struct {
+	Provider string
+}
+
+type FixturesConfig struct {
+	Mode string
+	Dir  string
+}
+
 type ExternalAPI struct {
 	Name string
 	URL  string
@@ -74,28 +135,133 @@ func Load() {
 
 	Cfg.JWT.SecretKey = []byte(getEnv("JWT_SECRET_KEY")) // Initialise JWT Secret Key for JWT Encoding
 
+	// Parse EXTERNAL_API_MODE (Optional - Defaults to live)
+	Cfg.Fixtures.Mode = getEnvOrDefault("EXTERNAL_API_MODE", FixtureModeLive)
+	Cfg.Fixtures.Dir = getEnvOrDefault("FIXTURES_DIR", "./internal/fixtures")
+	switch Cfg.Fixtures.Mode {
+	case FixtureModeLive, FixtureModeRecord, FixtureModeReplay:
+	default:
+		log.Fatalf("invalid EXTERNAL_API_MODE: Must be one of '%s', '%s' or '%s'", FixtureModeLive, FixtureModeRecord, FixtureModeReplay)
+	}
+
+	// API Keys are not needed when replaying fixtures (Keys are stripped from recorded URLs)
+	apiKey := getEnv
+	if Cfg.Fixtures.Mode == FixtureModeReplay {
+		apiKey = func(key string) string { return getEnvOrDefault(key, "replay") }
+	}
+
+	// OpenWeather Key is optional (Open-Meteo needs no key, so weather works without it)
+	openWeatherKey := os.Getenv("OPENWEATHER_KEY")
+	if openWeatherKey == "" && Cfg.Fixtures.Mode == FixtureModeReplay {
+		openWeatherKey = "replay"
+	}
+
+	// Parse WEATHER_PROVIDER (Optional - Defaults to OpenWeather if a key is set, otherwise Open-Meteo)
+	defaultWeatherProvider := WeatherProviderOpenMeteo
+	if openWeatherKey != "" {
+		defaultWeatherProvider = WeatherProviderOpenWeather
+	}
+	Cfg.Weather.Provider = getEnvOrDefault("WEATHER_PROVIDER", defaultWeatherProvider)
+	switch Cfg.Weather.Provider {
+	case WeatherProviderOpenWeather, WeatherProviderOpenMeteo:
+	default:
+		log.Fatalf("invalid WEATHER_PROVIDER: Must be one of '%s' or '%s'", WeatherProviderOpenWeather, WeatherProviderOpenMeteo)
+	}
+
+	// Parse AIR_QUALITY_PROVIDER (Optional - Defaults to the Weather Provider)
+	Cfg.AirQuality.Provider = getEnvOrDefault("AIR_QUALITY_PROVIDER", Cfg.Weather.Provider)
+	switch Cfg.AirQuality.Provider {
+	case WeatherProviderOpenWeather, WeatherProviderOpenMeteo:
+	default:
+		log.Fatalf("invalid AIR_QUALITY_PROVIDER: Must be one of '%s' or '%s'", WeatherProviderOpenWeather, WeatherProviderOpenMeteo)
+	}
+
+	// Parse EXCHANGE_RATE_PROVIDER (Optional - Defaults to the ECB)
+	Cfg.ExchangeRates.Provider = getEnvOrDefault("EXCHANGE_RATE_PROVIDER", ExchangeRateProviderECB)
+	switch Cfg.ExchangeRates.Provider {
+	case ExchangeRateProviderECB, ExchangeRateProviderFixture:
+	default:
+		log.Fatalf("invalid EXCHANGE_RATE_PROVIDER: Must be one of '%s' or '%s'", ExchangeRateProviderECB, ExchangeRateProviderFixture)
+	}
+
 	Cfg.PhotonAPI = ExternalAPI{
 		Name: "Photon API",
-		URL:  "https://photon.komoot.io/api/?q=%s&lang=en", // Static URL
+		URL:  "https://photon.komoot.io/api/?q=%s&lang=%s&limit=%d", // Query, Language and Limit come from the client search
+	}
+	Cfg.PhotonReverseAPI = ExternalAPI{
+		Name: "Photon Reverse API",
+		URL:  "https://photon.komoot.io/reverse?lat=%s&lon=%s&layer=city&limit=1&lang=en", // Static URL (City layer only)
 	}
 	Cfg.RestCountriesAPI = ExternalAPI{
 		Name: "Rest-Countries API",
 		URL:  "https://restcountries.com/v3.1/alpha/%s", // Static URL
 	}
+	Cfg.RestCountriesListAPI = ExternalAPI{
+		Name: "Rest-Countries List API",
+		URL:  "https://restcountries.com/v3.1/alpha?codes=%s", // Comma separated Alpha-2 or Alpha-3 codes
+	}
+	Cfg.RestCountriesAllAPI = ExternalAPI{
+		Name: "Rest-Countries All API",
+		URL:  "https://restcountries.com/v3.1/all?fields=cca2", // Static URL (Only the codes, full countries are fetched in batches with the List API)
+	}
 	Cfg.OpenWeatherAPI = SecureExternalAPI{
 		Name: "OpenWeather API",
 		URL:  "https://api.openweathermap.org/data/3.0/onecall?lat=%s&lon=%s&exclude=alerts,hourly,minutely&units=metric&appid=%s", // Static URL
-		Key:  getEnv("OPENWEATHER_KEY"),
+		Key:  openWeatherKey,
+	}
+	Cfg.OpenWeatherHourlyAPI = SecureExternalAPI{
+		Name: "OpenWeather Hourly API",
+		URL:  "https://api.openweathermap.org/data/3.0/onecall?lat=%s&lon=%s&exclude=current,minutely,daily,alerts&units=metric&appid=%s", // Static URL
+		Key:  openWeatherKey,
+	}
+	Cfg.OpenWeatherMinutelyAPI = SecureExternalAPI{
+		Name: "OpenWeather Minutely API",
+		URL:  "https://api.openweathermap.org/data/3.0/onecall?lat=%s&lon=%s&exclude=current,hourly,daily,alerts&units=metric&appid=%s", // Static URL
+		Key:  openWeatherKey,
+	}
+	Cfg.OpenWeatherAlertsAPI = SecureExternalAPI{
+		Name: "OpenWeather Alerts API",
+		URL:  "https://api.openweathermap.org/data/3.0/onecall?lat=%s&lon=%s&exclude=current,minutely,hourly,daily&units=metric&appid=%s", // Static URL
+		Key:  openWeatherKey,
+	}
+	Cfg.OpenMeteoAPI = ExternalAPI{
+		Name: "Open-Meteo API",
+		URL:  "https://api.open-meteo.com/v1/forecast?latitude=%s&longitude=%s&current=temperature_2m,relative_humidity_2m,apparent_temperature,is_day,precipitation,weather_code,cloud_cover,pressure_msl,wind_speed_10m,wind_direction_10m,wind_gusts_10m,uv_index&daily=weather_code,temperature_2m_max,temperature_2m_min,apparent_temperature_max,sunrise,sunset,uv_index_max,precipitation_sum,precipitation_probability_max,wind_speed_10m_max,wind_gusts_10m_max,wind_direction_10m_dominant&wind_speed_unit=ms&timeformat=unixtime&timezone=auto", // Static URL (No Key Required)
+	}
+	Cfg.OpenMeteoHourlyAPI = ExternalAPI{
+		Name: "Open-Meteo Hourly API",
+		URL:  "https://api.open-meteo.com/v1/forecast?latitude=%s&longitude=%s&hourly=temperature_2m,relative_humidity_2m,apparent_temperature,precipitation_probability,precipitation,weather_code,pressure_msl,cloud_cover,wind_speed_10m,wind_direction_10m,wind_gusts_10m,uv_index,is_day&forecast_hours=48&wind_speed_unit=ms&timeformat=unixtime&timezone=auto", // Static URL (No Key Required)
+	}
+	Cfg.OpenMeteoMinutelyAPI = ExternalAPI{
+		Name: "Open-Meteo Minutely API",
+		URL:  "https://api.open-meteo.com/v1/forecast?latitude=%s&longitude=%s&minutely_15=precipitation&forecast_minutely_15=8&timeformat=unixtime&timezone=auto", // Static URL (No Key Required)
+	}
+	Cfg.OpenWeatherAirAPI = SecureExternalAPI{
+		Name: "OpenWeather Air Pollution API",
+		URL:  "https://api.openweathermap.org/data/2.5/air_pollution?lat=%s&lon=%s&appid=%s", // Static URL
+		Key:  openWeatherKey,
+	}
+	Cfg.OpenMeteoAirAPI = ExternalAPI{
+		Name: "Open-Meteo Air Quality API",
+		URL:  "https://air-quality-api.open-meteo.com/v1/air-quality?latitude=%s&longitude=%s&current=european_aqi,pm10,pm2_5,carbon_monoxide,nitrogen_dioxide,sulphur_dioxide,ozone,alder_pollen,birch_pollen,grass_pollen,mugwort_pollen,olive_pollen,ragweed_pollen&timeformat=unixtime&timezone=auto", // Static URL (No Key Required)
+	}
+	Cfg.OpenMeteoArchiveAPI = ExternalAPI{
+		Name: "Open-Meteo Historical Weather API",
+		URL:  "https://archive-api.open-meteo.com/v1/archive?latitude=%s&longitude=%s&start_date=%s&end_date=%s&daily=temperature_2m_max,temperature_2m_min,precipitation_sum,sunshine_duration&timezone=auto", // Static URL (No Key Required)
+	}
+	Cfg.ECBRatesAPI = ExternalAPI{
+		Name: "ECB Exchange Rates API",
+		URL:  "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml", // Static URL (Published each working day around 16:00 CET)
 	}
 	Cfg.OpenTripAPI = SecureExternalAPI{
 		Name: "OpenTrip API",
-		URL:  "https://api.opentripmap.com/0.1/en/places/radius?lat=%s&lon=%s&radius=2000&limit=50&kinds=amusements,accomodations,tourist_facilities&rate=2&apikey=%s", // Static URL
-		Key:  getEnv("OPENTRIP_KEY"),
+		URL:  "https://api.opentripmap.com/0.1/en/places/radius?lat=%s&lon=%s&radius=%d&limit=%d&kinds=%s&rate=%s&apikey=%s", // Radius, Limit, Kinds and Rate come from the client search
+		Key:  apiKey("OPENTRIP_KEY"),
 	}
 	Cfg.OpenTripXIDAPI = SecureExternalAPI{
 		Name: "OpenTrip Singular Place API",
 		URL:  "https://api.opentripmap.com/0.1/en/places/xid/%s?apikey=%s",
-		Key:  getEnv("OPENTRIP_KEY"),
+		Key:  apiKey("OPENTRIP_KEY"),
 	}
 }
 
@@ -108,3 +274,12 @@ func getEnv(key string) string {
 	}
 	return val
 }
+
+// getEnvOrDefault returns the environment variable or the fallback if missing
+func getEnvOrDefault(key string, fallback string) string {
+	val := os.Getenv(key)
+	if val == "" {
+		return fallback
+	}
+	return val
+}
@@ -1,11 +1,13 @@
 package database
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"strings"
 
+	"github.com/go-sql-driver/mysql"
 	"gorm.io/gorm"
 )
 
@@ -57,23 +59,34 @@ func Connect(dbName string) error {
 }
 
 func Execute(model any, query string, args ...any) (any, error) {
+	return ExecuteIn(DB, model, query, args...)
+}
+
+// ExecuteIn - Execute on a specific connection (e.g. the tx inside DB.Transaction)
+func ExecuteIn(db *gorm.DB, model any, query string, args ...any) (any, error) {
 	queryUpper := strings.ToUpper(strings.TrimSpace(query))
 
 	switch {
 	case strings.HasPrefix(queryUpper, "SELECT"):
-		result := DB.Raw(query, args...).Scan(model)
+		result := db.Raw(query, args...).Scan(model)
 		return model, result.Error
 
 	case strings.HasPrefix(queryUpper, "INSERT") && model != nil:
-		err := DB.Create(model).Error
+		err := db.Create(model).Error
 		return model, err
 
 	default:
-		res := DB.Exec(query, args...)
+		res := db.Exec(query, args...)
 		return res.RowsAffected, res.Error
 	}
 }
 
+// IsDuplicateKey - Whether the error is MySQL rejecting a row that breaks a unique index
+func IsDuplicateKey(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+}
+
 func initialiseDatabase(server *gorm.DB, dbName string) {
 	queryBytes, err := os.ReadFile("./internal/database/migrations/initialisation/000_initialisation.sql")
 	if err != nil {
@@ -0,0 +1,16 @@
+-- Historical weather never changes, rows only expire when a new full year becomes available
+CREATE TABLE IF NOT EXISTS city_weather_history (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    lat DECIMAL(9, 6) NOT NULL,
+    lon DECIMAL(9, 6) NOT NULL,
+    city_id INT NOT NULL,
+    country_id INT NOT NULL,
+    start_year INT NOT NULL,
+    end_year INT NOT NULL,
+    data JSON NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP NOT NULL,
+    expiry_date TIMESTAMP NOT NULL,
+    FOREIGN KEY (city_id) REFERENCES cities(id),
+    FOREIGN KEY (country_id) REFERENCES countries(id)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE city_sights ADD COLUMN search_key VARCHAR(255) NOT NULL DEFAULT '';
+
+UPDATE city_sights SET search_key = 'kinds=accomodations,amusements,tourist_facilities&radius=2000&rate=2' WHERE search_key = '';
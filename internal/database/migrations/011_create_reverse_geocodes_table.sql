@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS reverse_geocodes (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    lat DECIMAL(9, 6) NOT NULL,
+    lon DECIMAL(9, 6) NOT NULL,
+    data JSON NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP NOT NULL,
+    expiry_date TIMESTAMP NOT NULL,
+    UNIQUE (lat, lon)
+);
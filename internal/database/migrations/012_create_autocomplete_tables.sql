@@ -0,0 +1,23 @@
+-- Cities resolved by the geocoder form the local autocomplete index (Existing cities keep NULL coordinates)
+ALTER TABLE cities
+ADD COLUMN state VARCHAR(100) NOT NULL DEFAULT '' AFTER name,
+ADD COLUMN country_code CHAR(2) NOT NULL DEFAULT '' AFTER state,
+ADD COLUMN lat DECIMAL(9, 6) NULL AFTER country_code,
+ADD COLUMN lon DECIMAL(9, 6) NULL AFTER lat,
+ADD COLUMN osm_id BIGINT NULL AFTER lon,
+ADD COLUMN population_rank INT NOT NULL DEFAULT 0 AFTER osm_id;
+
+CREATE INDEX idx_cities_name ON cities (name);
+
+CREATE UNIQUE INDEX idx_cities_osm_id ON cities (osm_id);
+
+CREATE TABLE IF NOT EXISTS autocomplete_cache (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    prefix VARCHAR(100) NOT NULL,
+    lang CHAR(2) NOT NULL,
+    data JSON NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP NOT NULL,
+    expiry_date TIMESTAMP NOT NULL,
+    UNIQUE (prefix, lang)
+);
@@ -0,0 +1,160 @@
+-- Canonical Cities
+-- A city is unique on (name, country code, coordinates rounded to 0.1 degrees), so homonyms like Paris FR and Paris TX are separate rows
+-- Legacy (name only) cities are split per country and location using the cache rows that reference them, then duplicates are merged
+
+ALTER TABLE cities
+ADD COLUMN country_id INT NULL AFTER country_code,
+ADD COLUMN population INT NULL AFTER osm_id,
+ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT '' AFTER population_rank,
+ADD COLUMN lat_key DECIMAL(4, 1) AS (ROUND(lat, 1)) STORED,
+ADD COLUMN lon_key DECIMAL(4, 1) AS (ROUND(lon, 1)) STORED;
+
+UPDATE cities
+JOIN countries ON countries.iso_code = cities.country_code
+SET cities.country_id = countries.id
+WHERE cities.country_code <> '';
+
+-- Every (city, country, location) a cache row has used
+CREATE TABLE city_merge_usages (
+    city_id INT NOT NULL,
+    country_id INT NOT NULL,
+    lat DECIMAL(9, 6) NOT NULL,
+    lon DECIMAL(9, 6) NOT NULL
+);
+
+INSERT INTO city_merge_usages (city_id, country_id, lat, lon) SELECT city_id, country_id, lat, lon FROM city_weather;
+INSERT INTO city_merge_usages (city_id, country_id, lat, lon) SELECT city_id, country_id, lat, lon FROM city_sights;
+INSERT INTO city_merge_usages (city_id, country_id, lat, lon) SELECT city_id, country_id, lat, lon FROM city_weather_hourly;
+INSERT INTO city_merge_usages (city_id, country_id, lat, lon) SELECT city_id, country_id, lat, lon FROM city_weather_minutely;
+INSERT INTO city_merge_usages (city_id, country_id, lat, lon) SELECT city_id, country_id, lat, lon FROM city_weather_alerts;
+INSERT INTO city_merge_usages (city_id, country_id, lat, lon) SELECT city_id, country_id, lat, lon FROM city_air_quality;
+INSERT INTO city_merge_usages (city_id, country_id, lat, lon) SELECT city_id, country_id, lat, lon FROM city_weather_history;
+
+INSERT INTO city_merge_usages (city_id, country_id, lat, lon)
+SELECT city_id, country_id, CAST(data->>'$.point.lat' AS DECIMAL(9, 6)), CAST(data->>'$.point.lon' AS DECIMAL(9, 6))
+FROM city_pois
+WHERE data->>'$.point.lat' IS NOT NULL AND data->>'$.point.lon' IS NOT NULL;
+
+-- One canonical city per legacy city, country and location
+INSERT INTO cities (name, country_code, country_id, lat, lon)
+SELECT legacy.name, UPPER(countries.iso_code), usages.country_id, MIN(usages.lat), MIN(usages.lon)
+FROM city_merge_usages usages
+JOIN cities legacy ON legacy.id = usages.city_id
+JOIN countries ON countries.id = usages.country_id
+WHERE legacy.lat IS NULL
+GROUP BY legacy.name, countries.iso_code, usages.country_id, ROUND(usages.lat, 1), ROUND(usages.lon, 1);
+
+-- Relink cache rows from legacy cities to their canonical city
+UPDATE city_weather
+JOIN cities legacy ON legacy.id = city_weather.city_id
+JOIN countries ON countries.id = city_weather.country_id
+JOIN cities canonical ON canonical.name = legacy.name AND canonical.country_code = countries.iso_code
+    AND canonical.lat_key = ROUND(city_weather.lat, 1) AND canonical.lon_key = ROUND(city_weather.lon, 1)
+SET city_weather.city_id = canonical.id
+WHERE legacy.lat IS NULL;
+
+UPDATE city_sights
+JOIN cities legacy ON legacy.id = city_sights.city_id
+JOIN countries ON countries.id = city_sights.country_id
+JOIN cities canonical ON canonical.name = legacy.name AND canonical.country_code = countries.iso_code
+    AND canonical.lat_key = ROUND(city_sights.lat, 1) AND canonical.lon_key = ROUND(city_sights.lon, 1)
+SET city_sights.city_id = canonical.id
+WHERE legacy.lat IS NULL;
+
+UPDATE city_weather_hourly
+JOIN cities legacy ON legacy.id = city_weather_hourly.city_id
+JOIN countries ON countries.id = city_weather_hourly.country_id
+JOIN cities canonical ON canonical.name = legacy.name AND canonical.country_code = countries.iso_code
+    AND canonical.lat_key = ROUND(city_weather_hourly.lat, 1) AND canonical.lon_key = ROUND(city_weather_hourly.lon, 1)
+SET city_weather_hourly.city_id = canonical.id
+WHERE legacy.lat IS NULL;
+
+UPDATE city_weather_minutely
+JOIN cities legacy ON legacy.id = city_weather_minutely.city_id
+JOIN countries ON countries.id = city_weather_minutely.country_id
+JOIN cities canonical ON canonical.name = legacy.name AND canonical.country_code = countries.iso_code
+    AND canonical.lat_key = ROUND(city_weather_minutely.lat, 1) AND canonical.lon_key = ROUND(city_weather_minutely.lon, 1)
+SET city_weather_minutely.city_id = canonical.id
+WHERE legacy.lat IS NULL;
+
+UPDATE city_weather_alerts
+JOIN cities legacy ON legacy.id = city_weather_alerts.city_id
+JOIN countries ON countries.id = city_weather_alerts.country_id
+JOIN cities canonical ON canonical.name = legacy.name AND canonical.country_code = countries.iso_code
+    AND canonical.lat_key = ROUND(city_weather_alerts.lat, 1) AND canonical.lon_key = ROUND(city_weather_alerts.lon, 1)
+SET city_weather_alerts.city_id = canonical.id
+WHERE legacy.lat IS NULL;
+
+UPDATE city_air_quality
+JOIN cities legacy ON legacy.id = city_air_quality.city_id
+JOIN countries ON countries.id = city_air_quality.country_id
+JOIN cities canonical ON canonical.name = legacy.name AND canonical.country_code = countries.iso_code
+    AND canonical.lat_key = ROUND(city_air_quality.lat, 1) AND canonical.lon_key = ROUND(city_air_quality.lon, 1)
+SET city_air_quality.city_id = canonical.id
+WHERE legacy.lat IS NULL;
+
+UPDATE city_weather_history
+JOIN cities legacy ON legacy.id = city_weather_history.city_id
+JOIN countries ON countries.id = city_weather_history.country_id
+JOIN cities canonical ON canonical.name = legacy.name AND canonical.country_code = countries.iso_code
+    AND canonical.lat_key = ROUND(city_weather_history.lat, 1) AND canonical.lon_key = ROUND(city_weather_history.lon, 1)
+SET city_weather_history.city_id = canonical.id
+WHERE legacy.lat IS NULL;
+
+UPDATE city_pois
+JOIN cities legacy ON legacy.id = city_pois.city_id
+JOIN countries ON countries.id = city_pois.country_id
+JOIN cities canonical ON canonical.name = legacy.name AND canonical.country_code = countries.iso_code
+    AND canonical.lat_key = ROUND(CAST(city_pois.data->>'$.point.lat' AS DECIMAL(9, 6)), 1)
+    AND canonical.lon_key = ROUND(CAST(city_pois.data->>'$.point.lon' AS DECIMAL(9, 6)), 1)
+SET city_pois.city_id = canonical.id
+WHERE legacy.lat IS NULL;
+
+-- Merge duplicate canonical cities into the oldest row
+CREATE TABLE city_merge_map (
+    old_id INT PRIMARY KEY,
+    new_id INT NOT NULL
+);
+
+INSERT INTO city_merge_map (old_id, new_id)
+SELECT cities.id, canonical.id
+FROM cities
+JOIN (
+    SELECT MIN(id) AS id, name, country_code, lat_key, lon_key
+    FROM cities
+    WHERE lat_key IS NOT NULL
+    GROUP BY name, country_code, lat_key, lon_key
+) canonical ON canonical.name = cities.name AND canonical.country_code = cities.country_code
+    AND canonical.lat_key = cities.lat_key AND canonical.lon_key = cities.lon_key
+WHERE cities.id <> canonical.id;
+
+UPDATE city_weather JOIN city_merge_map ON city_merge_map.old_id = city_weather.city_id SET city_weather.city_id = city_merge_map.new_id;
+UPDATE city_sights JOIN city_merge_map ON city_merge_map.old_id = city_sights.city_id SET city_sights.city_id = city_merge_map.new_id;
+UPDATE city_weather_hourly JOIN city_merge_map ON city_merge_map.old_id = city_weather_hourly.city_id SET city_weather_hourly.city_id = city_merge_map.new_id;
+UPDATE city_weather_minutely JOIN city_merge_map ON city_merge_map.old_id = city_weather_minutely.city_id SET city_weather_minutely.city_id = city_merge_map.new_id;
+UPDATE city_weather_alerts JOIN city_merge_map ON city_merge_map.old_id = city_weather_alerts.city_id SET city_weather_alerts.city_id = city_merge_map.new_id;
+UPDATE city_air_quality JOIN city_merge_map ON city_merge_map.old_id = city_air_quality.city_id SET city_air_quality.city_id = city_merge_map.new_id;
+UPDATE city_weather_history JOIN city_merge_map ON city_merge_map.old_id = city_weather_history.city_id SET city_weather_history.city_id = city_merge_map.new_id;
+UPDATE city_pois JOIN city_merge_map ON city_merge_map.old_id = city_pois.city_id SET city_pois.city_id = city_merge_map.new_id;
+
+DELETE cities FROM cities JOIN city_merge_map ON city_merge_map.old_id = cities.id;
+
+-- Legacy cities that are no longer referenced
+DELETE FROM cities
+WHERE lat IS NULL
+    AND id NOT IN (SELECT city_id FROM city_weather)
+    AND id NOT IN (SELECT city_id FROM city_sights)
+    AND id NOT IN (SELECT city_id FROM city_weather_hourly)
+    AND id NOT IN (SELECT city_id FROM city_weather_minutely)
+    AND id NOT IN (SELECT city_id FROM city_weather_alerts)
+    AND id NOT IN (SELECT city_id FROM city_air_quality)
+    AND id NOT IN (SELECT city_id FROM city_weather_history)
+    AND id NOT IN (SELECT city_id FROM city_pois);
+
+DROP TABLE city_merge_map;
+
+DROP TABLE city_merge_usages;
+
+ALTER TABLE cities
+ADD UNIQUE INDEX idx_cities_canonical (name, country_code, lat_key, lon_key),
+ADD FOREIGN KEY (country_id) REFERENCES countries(id);
@@ -0,0 +1,9 @@
+ALTER TABLE countries
+ADD COLUMN iso_code3 CHAR(3) NOT NULL DEFAULT '' AFTER iso_code;
+
+-- Older rows saved the whole Rest Countries array rather than the single country
+UPDATE countries SET data = JSON_EXTRACT(data, '$[0]') WHERE JSON_TYPE(data) = 'ARRAY';
+
+UPDATE countries SET iso_code3 = COALESCE(data->>'$.cca3', '') WHERE data IS NOT NULL;
+
+CREATE INDEX idx_countries_iso_code3 ON countries (iso_code3);
@@ -0,0 +1,15 @@
+-- Home Country (ISO 3166-1 Alpha-2) used as the default currency conversion target
+ALTER TABLE users
+ADD COLUMN home_country_code CHAR(2) NOT NULL DEFAULT '' AFTER email;
+
+-- One row per provider per day (Rates are relative to the base currency)
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    rate_date DATE NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    base CHAR(3) NOT NULL,
+    data JSON NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP NOT NULL,
+    UNIQUE (rate_date, provider)
+);
@@ -0,0 +1,19 @@
+-- Saved cities (city_id) and POIs (xid) per user
+CREATE TABLE IF NOT EXISTS favourites (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    kind ENUM('city', 'poi') NOT NULL,
+    city_id INT NULL,
+    xid VARCHAR(32) NULL,
+    target VARCHAR(32) AS (IF(kind = 'city', CAST(city_id AS CHAR), xid)) STORED,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    notes TEXT NULL,
+    tags JSON NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP NOT NULL,
+    UNIQUE (user_id, kind, target),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (city_id) REFERENCES cities(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_city_pois_xid ON city_pois (xid);
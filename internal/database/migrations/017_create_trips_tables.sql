@@ -0,0 +1,43 @@
+CREATE TABLE IF NOT EXISTS trips (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    notes TEXT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Cities covered by a trip (In visiting order)
+CREATE TABLE IF NOT EXISTS trip_cities (
+    trip_id INT NOT NULL,
+    city_id INT NOT NULL,
+    position INT NOT NULL,
+    PRIMARY KEY (trip_id, city_id),
+    FOREIGN KEY (trip_id) REFERENCES trips(id) ON DELETE CASCADE,
+    FOREIGN KEY (city_id) REFERENCES cities(id) ON DELETE CASCADE
+);
+
+-- POIs (xid) or custom places on a day of the trip (Day 1 is the start date)
+CREATE TABLE IF NOT EXISTS trip_items (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    trip_id INT NOT NULL,
+    day INT NOT NULL,
+    time_slot ENUM('any', 'morning', 'afternoon', 'evening', 'night') NOT NULL DEFAULT 'any',
+    position INT NOT NULL,
+    kind ENUM('poi', 'custom') NOT NULL,
+    xid VARCHAR(32) NULL,
+    city_id INT NULL,
+    name VARCHAR(255) NOT NULL,
+    lat DECIMAL(9, 6) NULL,
+    lon DECIMAL(9, 6) NULL,
+    notes TEXT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (trip_id) REFERENCES trips(id) ON DELETE CASCADE,
+    FOREIGN KEY (city_id) REFERENCES cities(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_trip_items_order ON trip_items (trip_id, day, position);
@@ -0,0 +1,9 @@
+-- Custom places (e.g. imported points with no known POI) are saved by their coordinates
+ALTER TABLE favourites
+MODIFY kind ENUM('city', 'poi', 'place') NOT NULL,
+ADD COLUMN lat DECIMAL(9, 6) NULL AFTER xid,
+ADD COLUMN lon DECIMAL(9, 6) NULL AFTER lat;
+
+-- Places are unique per user by their coordinates
+ALTER TABLE favourites
+MODIFY target VARCHAR(32) AS (CASE kind WHEN 'city' THEN CAST(city_id AS CHAR) WHEN 'poi' THEN xid ELSE CONCAT(lat, ',', lon) END) STORED;
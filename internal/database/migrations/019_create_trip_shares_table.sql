@@ -0,0 +1,13 @@
+-- Public read-only links to a trip (Only the SHA-256 of the token is stored, the link is shown once when it is created)
+CREATE TABLE IF NOT EXISTS trip_shares (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    trip_id INT NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    token_hint CHAR(6) NOT NULL,
+    password_hash VARCHAR(255) NULL,
+    expires_at TIMESTAMP NULL,
+    views INT NOT NULL DEFAULT 0,
+    last_viewed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (trip_id) REFERENCES trips(id) ON DELETE CASCADE
+);
@@ -0,0 +1,20 @@
+-- Version of the trip plan (Bumped on every change, sent as the ETag so concurrent edits can be detected)
+ALTER TABLE trips ADD COLUMN version INT NOT NULL DEFAULT 1 AFTER notes;
+
+-- Other users on a trip (The owner is trips.user_id, members start as pending invitations)
+CREATE TABLE IF NOT EXISTS trip_members (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    trip_id INT NOT NULL,
+    user_id INT NOT NULL,
+    role ENUM('editor', 'viewer') NOT NULL,
+    status ENUM('pending', 'accepted') NOT NULL DEFAULT 'pending',
+    invited_by INT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP NOT NULL,
+    UNIQUE (trip_id, user_id),
+    FOREIGN KEY (trip_id) REFERENCES trips(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (invited_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_trip_members_user ON trip_members (user_id, status);
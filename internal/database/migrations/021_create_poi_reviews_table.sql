@@ -0,0 +1,19 @@
+-- User ratings (1 - 5) and reviews of OpenTripMap POIs (One per user and POI, hidden reviews are left out of the averages)
+CREATE TABLE IF NOT EXISTS poi_reviews (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    xid VARCHAR(32) NOT NULL,
+    user_id INT NOT NULL,
+    rating TINYINT NOT NULL,
+    review TEXT NULL,
+    hidden BOOLEAN NOT NULL DEFAULT FALSE,
+    flagged BOOLEAN NOT NULL DEFAULT FALSE,
+    flag_reason VARCHAR(255) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP NOT NULL,
+    UNIQUE (xid, user_id),
+    CHECK (rating BETWEEN 1 AND 5),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_poi_reviews_xid ON poi_reviews (xid, hidden, created_at);
+CREATE INDEX idx_poi_reviews_flagged ON poi_reviews (flagged, created_at);
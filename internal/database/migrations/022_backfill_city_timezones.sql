@@ -0,0 +1,7 @@
+-- Backfill city timezones from cached weather (Cities cached before timezones were recorded)
+UPDATE cities
+JOIN city_weather ON city_weather.city_id = cities.id
+SET cities.timezone = city_weather.data->>'$.timezone'
+WHERE cities.timezone = ''
+    AND city_weather.data->>'$.timezone' IS NOT NULL
+    AND city_weather.data->>'$.timezone' <> '';
@@ -0,0 +1,3 @@
+-- Drop the unused city population (No provider supplies it, the population rank orders the cities)
+ALTER TABLE cities
+DROP COLUMN population;
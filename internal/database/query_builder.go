@@ -13,9 +13,15 @@ type QueryBuilder struct {
 
 	numValues int      // NUMBER OF VALUES (e.g. VALUES (?, ?, ?))
 	rawValues []string // For Subqueries in INSERT statements
+	rawSets   []string // For expressions in UPDATE statements (e.g. views = views + 1)
 
 	whereClauses []string
 	joinClauses  []string
+
+	groupBy string // SELECT only
+	orderBy string // SELECT only
+	limit   bool   // SELECT only (LIMIT ?)
+	offset  bool   // SELECT only (OFFSET ?, after the limit)
 }
 
 func NewQueryBuilder(operation string) *QueryBuilder {
@@ -47,11 +53,39 @@ func (qb *QueryBuilder) Join(joinClause string) *QueryBuilder {
 	return qb
 }
 
+func (qb *QueryBuilder) GroupBy(groupBy string) *QueryBuilder {
+	qb.groupBy = groupBy
+	return qb
+}
+
+func (qb *QueryBuilder) OrderBy(orderBy string) *QueryBuilder {
+	qb.orderBy = orderBy
+	return qb
+}
+
+// Limit - Adds a LIMIT placeholder (The limit is passed as the last argument)
+func (qb *QueryBuilder) Limit() *QueryBuilder {
+	qb.limit = true
+	return qb
+}
+
+// Offset - Adds an OFFSET placeholder after the limit (The offset is passed after the limit)
+func (qb *QueryBuilder) Offset() *QueryBuilder {
+	qb.offset = true
+	return qb
+}
+
 func (qb *QueryBuilder) ValuesRaw(raw ...string) *QueryBuilder {
 	qb.rawValues = raw
 	return qb
 }
 
+// SetRaw - Adds SET clauses written as SQL after the columns (UPDATE only)
+func (qb *QueryBuilder) SetRaw(raw ...string) *QueryBuilder {
+	qb.rawSets = raw
+	return qb
+}
+
 func (qb *QueryBuilder) Build() string {
 	var query string
 
@@ -68,6 +102,18 @@ func (qb *QueryBuilder) Build() string {
 		if len(qb.whereClauses) > 0 {
 			query += " WHERE " + strings.Join(qb.whereClauses, " AND ")
 		}
+		if qb.groupBy != "" {
+			query += " GROUP BY " + qb.groupBy
+		}
+		if qb.orderBy != "" {
+			query += " ORDER BY " + qb.orderBy
+		}
+		if qb.limit {
+			query += " LIMIT ?"
+		}
+		if qb.offset {
+			query += " OFFSET ?"
+		}
 
 	case "INSERT":
 		cols := strings.Join(qb.columns, ", ")
@@ -87,6 +133,7 @@ func (qb *QueryBuilder) Build() string {
 		for _, col := range qb.columns {
 			setClauses = append(setClauses, fmt.Sprintf("%s = ?", col))
 		}
+		setClauses = append(setClauses, qb.rawSets...)
 		setString := strings.Join(setClauses, ", ")
 		query = fmt.Sprintf("UPDATE %s SET %s", qb.table, setString)
 		if len(qb.whereClauses) > 0 {
@@ -0,0 +1,181 @@
+package export
+
+/* Export
+
+- Renders a list of places (a trip's items or the user's favourites) as GPX, KML, GeoJSON or iCalendar
+- Pure functions over a neutral Document, so the output can be checked against golden files
+*/
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Format string
+
+const (
+	FormatGeoJSON Format = "geojson"
+	FormatGPX     Format = "gpx"
+	FormatKML     Format = "kml"
+	FormatICS     Format = "ics"
+)
+
+// Formats in negotiation order (The first is used when any format is accepted)
+var formats = []struct {
+	Format      Format
+	ContentType string
+	Aliases     []string // Other media types accepted for the format
+}{
+	{FormatGeoJSON, "application/geo+json", []string{"application/json"}},
+	{FormatGPX, "application/gpx+xml", nil},
+	{FormatKML, "application/vnd.google-earth.kml+xml", nil},
+	{FormatICS, "text/calendar", nil},
+}
+
+var ErrUnknownFormat = errors.New("unknown export format")
+
+// Document - A named list of places in order
+type Document struct {
+	Id          string // Stable id (e.g. trip-12), used in calendar UIDs
+	Name        string
+	Description string
+	Generated   time.Time
+	Places      []Place
+}
+
+type Place struct {
+	Id       string // Unique within the document (e.g. item-3)
+	Name     string
+	Notes    string
+	Kind     string   // e.g. poi, custom, city
+	Xid      string   // OpenTripMap id (POIs only)
+	Lat      *float64 // nil for places without coordinates
+	Lon      *float64
+	Tags     []string
+	Day      int    // Trip day (0 outside a trip)
+	Date     string // YYYY-MM-DD (Empty outside a trip)
+	TimeSlot string // any, morning, afternoon, evening or night
+}
+
+// ParseFormat - Validates a 'format' query parameter
+func ParseFormat(value string) (Format, error) {
+	for _, format := range formats {
+		if strings.EqualFold(value, string(format.Format)) {
+			return format.Format, nil
+		}
+	}
+	return "", fmt.Errorf("%w '%s' (Must be one of %s)", ErrUnknownFormat, value, strings.Join(Names(), ", "))
+}
+
+// Names - Format names in negotiation order
+func Names() []string {
+	names := make([]string, 0, len(formats))
+	for _, format := range formats {
+		names = append(names, string(format.Format))
+	}
+	return names
+}
+
+// MediaTypes - Every media type that can be negotiated through 'Accept' (In negotiation order)
+func MediaTypes() []string {
+	var types []string
+	for _, format := range formats {
+		types = append(types, format.ContentType)
+		types = append(types, format.Aliases...)
+	}
+	return types
+}
+
+// FormatForMediaType - The format for a negotiated media type
+func FormatForMediaType(mediaType string) (Format, bool) {
+	for _, format := range formats {
+		if mediaType == format.ContentType {
+			return format.Format, true
+		}
+		for _, alias := range format.Aliases {
+			if mediaType == alias {
+				return format.Format, true
+			}
+		}
+	}
+	return "", false
+}
+
+// ContentType - The Content-Type header for a format
+func ContentType(format Format) string {
+	for _, f := range formats {
+		if f.Format == format {
+			if format == FormatICS {
+				return f.ContentType + "; charset=utf-8"
+			}
+			return f.ContentType
+		}
+	}
+	return "application/octet-stream"
+}
+
+// Filename - Download name for a document (e.g. trip-12.gpx)
+func Filename(document Document, format Format) string {
+	return document.Id + "." + string(format)
+}
+
+// Render - The document in the requested format
+func Render(document Document, format Format) ([]byte, error) {
+	switch format {
+	case FormatGeoJSON:
+		return GeoJSON(document)
+	case FormatGPX:
+		return GPX(document)
+	case FormatKML:
+		return KML(document)
+	case FormatICS:
+		return ICS(document), nil
+	}
+	return nil, fmt.Errorf("%w '%s'", ErrUnknownFormat, format)
+}
+
+// days - Places with coordinates grouped by trip day, in order (Places outside a trip are left out)
+func days(document Document) []day {
+	var grouped []day
+	for _, place := range document.Places {
+		if place.Day == 0 || place.Lat == nil || place.Lon == nil {
+			continue
+		}
+		if len(grouped) == 0 || grouped[len(grouped)-1].Day != place.Day {
+			grouped = append(grouped, day{Day: place.Day, Date: place.Date})
+		}
+		grouped[len(grouped)-1].Places = append(grouped[len(grouped)-1].Places, place)
+	}
+	return grouped
+}
+
+type day struct {
+	Day    int
+	Date   string
+	Places []Place
+}
+
+func (d day) Name() string {
+	if d.Date == "" {
+		return fmt.Sprintf("Day %d", d.Day)
+	}
+	return fmt.Sprintf("Day %d (%s)", d.Day, d.Date)
+}
+
+// description - Notes followed by the tags
+func (p Place) description() string {
+	if len(p.Tags) == 0 {
+		return p.Notes
+	}
+	tags := "Tags: " + strings.Join(p.Tags, ", ")
+	if p.Notes == "" {
+		return tags
+	}
+	return p.Notes + "\n\n" + tags
+}
+
+func (p Place) located() bool {
+	return p.Lat != nil && p.Lon != nil
+}
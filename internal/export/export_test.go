@@ -0,0 +1,121 @@
+package export
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Regenerate the golden files with: go test ./internal/export -update
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func coordinate(value float64) *float64 {
+	return &value
+}
+
+func tripDocument() Document {
+	return Document{
+		Id:          "trip-7",
+		Name:        "Paris & London",
+		Description: "Autumn city break",
+		Generated:   time.Date(2026, 10, 19, 12, 30, 0, 0, time.UTC),
+		Places: []Place{
+			{Id: "item-1", Name: "Louvre Museum", Notes: "Book tickets, skip the queue; entrance by the pyramid\nBring water", Kind: "poi", Xid: "N123", Lat: coordinate(48.8606), Lon: coordinate(2.3376), Day: 1, Date: "2026-10-20", TimeSlot: "morning"},
+			{Id: "item-2", Name: "Lunch with Claire", Kind: "custom", Day: 1, Date: "2026-10-20", TimeSlot: "any"},
+			{Id: "item-3", Name: "Eiffel Tower", Kind: "poi", Xid: "W456", Lat: coordinate(48.8584), Lon: coordinate(2.2945), Day: 1, Date: "2026-10-20", TimeSlot: "afternoon"},
+			{Id: "item-4", Name: "Musée d'Orsay – Impressionist and Post-Impressionist masterpieces gallery tour", Kind: "poi", Lat: coordinate(48.86), Lon: coordinate(2.3266), Day: 2, Date: "2026-10-21", TimeSlot: "evening"},
+		},
+	}
+}
+
+func favouritesDocument() Document {
+	return Document{
+		Id:        "favourites",
+		Name:      "Favourites",
+		Generated: time.Date(2026, 10, 19, 12, 30, 0, 0, time.UTC),
+		Places: []Place{
+			{Id: "favourite-2", Name: "London", Notes: "Home", Kind: "city", Lat: coordinate(51.5073), Lon: coordinate(-0.1276), Tags: []string{"home", "uk"}},
+			{Id: "favourite-5", Name: "Sagrada Família", Kind: "poi", Xid: "R789", Tags: []string{"to visit"}},
+		},
+	}
+}
+
+func TestRenderGolden(t *testing.T) {
+	// Favourites have no dates, so they are not served as iCalendar
+	documents := []struct {
+		name     string
+		document Document
+		formats  []Format
+	}{
+		{name: "trip", document: tripDocument(), formats: []Format{FormatGeoJSON, FormatGPX, FormatKML, FormatICS}},
+		{name: "favourites", document: favouritesDocument(), formats: []Format{FormatGeoJSON, FormatGPX, FormatKML}},
+	}
+
+	for _, test := range documents {
+		document := test.document
+		for _, format := range test.formats {
+			golden := filepath.Join("testdata", test.name+"."+string(format))
+			t.Run(filepath.Base(golden), func(t *testing.T) {
+				got, err := Render(document, format)
+				if err != nil {
+					t.Fatalf("Render() error = %v", err)
+				}
+
+				if *update {
+					if err := os.WriteFile(golden, got, 0644); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				want, err := os.ReadFile(golden)
+				if err != nil {
+					t.Fatalf("missing golden file (run with -update): %v", err)
+				}
+				if !bytes.Equal(got, want) {
+					t.Errorf("output does not match %s\n--- got ---\n%s\n--- want ---\n%s", golden, got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestICSLinesAreFolded(t *testing.T) {
+	for _, line := range bytes.Split(ICS(tripDocument()), []byte("\r\n")) {
+		if len(line) > icsLineLength {
+			t.Errorf("line longer than %d octets: %q", icsLineLength, line)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for value, want := range map[string]Format{"gpx": FormatGPX, "KML": FormatKML, "geojson": FormatGeoJSON, "ics": FormatICS} {
+		got, err := ParseFormat(value)
+		if err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v, want %q", value, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("csv"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("ParseFormat(\"csv\") error = %v, want ErrUnknownFormat", err)
+	}
+}
+
+func TestFormatForMediaType(t *testing.T) {
+	for mediaType, want := range map[string]Format{
+		"application/geo+json":                 FormatGeoJSON,
+		"application/json":                     FormatGeoJSON,
+		"application/gpx+xml":                  FormatGPX,
+		"application/vnd.google-earth.kml+xml": FormatKML,
+		"text/calendar":                        FormatICS,
+	} {
+		if got, ok := FormatForMediaType(mediaType); !ok || got != want {
+			t.Errorf("FormatForMediaType(%q) = %q, %v, want %q", mediaType, got, ok, want)
+		}
+	}
+	if _, ok := FormatForMediaType("text/html"); ok {
+		t.Error("FormatForMediaType(\"text/html\") matched a format")
+	}
+}
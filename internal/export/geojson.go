@@ -0,0 +1,86 @@
+package export
+
+import "encoding/json"
+
+// GeoJSON (RFC 7946)
+type geoJSONCollection struct {
+	Type        string           `json:"type"`
+	Name        string           `json:"name,omitempty"` // Foreign members
+	Description string           `json:"description,omitempty"`
+	Features    []geoJSONFeature `json:"features"`
+}
+
+type geoJSONFeature struct {
+	Type       string            `json:"type"`
+	Id         string            `json:"id,omitempty"`
+	Geometry   *geoJSONGeometry  `json:"geometry"` // null for places without coordinates
+	Properties geoJSONProperties `json:"properties"`
+}
+
+type geoJSONGeometry struct {
+	Type        string `json:"type"`
+	Coordinates any    `json:"coordinates"` // [lon, lat] or [[lon, lat], ...]
+}
+
+type geoJSONProperties struct {
+	Name     string   `json:"name"`
+	Notes    string   `json:"notes,omitempty"`
+	Kind     string   `json:"kind,omitempty"`
+	Xid      string   `json:"xid,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Day      int      `json:"day,omitempty"`
+	Date     string   `json:"date,omitempty"`
+	TimeSlot string   `json:"time_slot,omitempty"`
+}
+
+// GeoJSON - A FeatureCollection with a Point per place and a LineString per trip day
+func GeoJSON(document Document) ([]byte, error) {
+	collection := geoJSONCollection{
+		Type:        "FeatureCollection",
+		Name:        document.Name,
+		Description: document.Description,
+		Features:    []geoJSONFeature{},
+	}
+
+	for _, place := range document.Places {
+		feature := geoJSONFeature{
+			Type: "Feature",
+			Id:   place.Id,
+			Properties: geoJSONProperties{
+				Name:     place.Name,
+				Notes:    place.Notes,
+				Kind:     place.Kind,
+				Xid:      place.Xid,
+				Tags:     place.Tags,
+				Day:      place.Day,
+				Date:     place.Date,
+				TimeSlot: place.TimeSlot,
+			},
+		}
+		if place.located() {
+			feature.Geometry = &geoJSONGeometry{Type: "Point", Coordinates: []float64{*place.Lon, *place.Lat}}
+		}
+		collection.Features = append(collection.Features, feature)
+	}
+
+	for _, day := range days(document) {
+		if len(day.Places) < 2 {
+			continue
+		}
+		line := make([][]float64, 0, len(day.Places))
+		for _, place := range day.Places {
+			line = append(line, []float64{*place.Lon, *place.Lat})
+		}
+		collection.Features = append(collection.Features, geoJSONFeature{
+			Type:       "Feature",
+			Geometry:   &geoJSONGeometry{Type: "LineString", Coordinates: line},
+			Properties: geoJSONProperties{Name: day.Name() + " route", Kind: "route", Day: day.Day, Date: day.Date},
+		})
+	}
+
+	data, err := json.MarshalIndent(collection, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,82 @@
+package export
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// GPX 1.1 (https://www.topografix.com/GPX/1/1/)
+type gpxFile struct {
+	XMLName  xml.Name    `xml:"gpx"`
+	Version  string      `xml:"version,attr"`
+	Creator  string      `xml:"creator,attr"`
+	Xmlns    string      `xml:"xmlns,attr"`
+	Metadata gpxMetadata `xml:"metadata"`
+	Points   []gpxPoint  `xml:"wpt"`
+	Routes   []gpxRoute  `xml:"rte"`
+}
+
+type gpxMetadata struct {
+	Name        string `xml:"name,omitempty"`
+	Description string `xml:"desc,omitempty"`
+	Time        string `xml:"time"`
+}
+
+type gpxPoint struct {
+	Lat         float64 `xml:"lat,attr"`
+	Lon         float64 `xml:"lon,attr"`
+	Name        string  `xml:"name,omitempty"`
+	Description string  `xml:"desc,omitempty"`
+	Type        string  `xml:"type,omitempty"`
+}
+
+type gpxRoute struct {
+	Name   string     `xml:"name"`
+	Number int        `xml:"number"`
+	Points []gpxPoint `xml:"rtept"`
+}
+
+// GPX - Every place with coordinates as a waypoint, plus one route per trip day
+func GPX(document Document) ([]byte, error) {
+	file := gpxFile{
+		Version: "1.1",
+		Creator: "City Explorer",
+		Xmlns:   "http://www.topografix.com/GPX/1/1",
+		Metadata: gpxMetadata{
+			Name:        document.Name,
+			Description: document.Description,
+			Time:        document.Generated.UTC().Format(time.RFC3339),
+		},
+	}
+
+	for _, place := range document.Places {
+		if !place.located() {
+			continue
+		}
+		file.Points = append(file.Points, gpxPoint{
+			Lat:         *place.Lat,
+			Lon:         *place.Lon,
+			Name:        place.Name,
+			Description: place.description(),
+			Type:        place.Kind,
+		})
+	}
+
+	for _, day := range days(document) {
+		route := gpxRoute{Name: day.Name(), Number: day.Day}
+		for _, place := range day.Places {
+			route.Points = append(route.Points, gpxPoint{Lat: *place.Lat, Lon: *place.Lon, Name: place.Name})
+		}
+		file.Routes = append(file.Routes, route)
+	}
+
+	return marshalXML(file)
+}
+
+func marshalXML(value any) ([]byte, error) {
+	data, err := xml.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
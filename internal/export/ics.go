@@ -0,0 +1,97 @@
+package export
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// iCalendar (RFC 5545)
+const (
+	icsLineLength = 75 // Octets before a line is folded
+	icsDate       = "20060102"
+	icsDateTime   = "20060102T150405"
+)
+
+// Hours covered by each time slot (Items without a slot are all day events)
+var timeSlotHours = map[string][2]int{
+	"morning":   {9, 12},
+	"afternoon": {13, 17},
+	"evening":   {18, 21},
+	"night":     {21, 23},
+}
+
+// ICS - One event per place with a date (Times are floating, i.e. local to wherever the traveller is)
+func ICS(document Document) []byte {
+	var b strings.Builder
+	line := func(name string, value string) {
+		writeICSLine(&b, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//City Explorer//Export//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if document.Name != "" {
+		line("X-WR-CALNAME", icsText(document.Name))
+	}
+
+	stamp := document.Generated.UTC().Format(icsDateTime) + "Z"
+	for _, place := range document.Places {
+		date, err := time.Parse("2006-01-02", place.Date)
+		if err != nil {
+			continue
+		}
+
+		line("BEGIN", "VEVENT")
+		line("UID", fmt.Sprintf("%s-%s@city-explorer", document.Id, place.Id))
+		line("DTSTAMP", stamp)
+		if hours, ok := timeSlotHours[place.TimeSlot]; ok {
+			line("DTSTART", date.Add(time.Duration(hours[0])*time.Hour).Format(icsDateTime))
+			line("DTEND", date.Add(time.Duration(hours[1])*time.Hour).Format(icsDateTime))
+		} else {
+			line("DTSTART;VALUE=DATE", date.Format(icsDate))
+			line("DTEND;VALUE=DATE", date.AddDate(0, 0, 1).Format(icsDate))
+		}
+		line("SUMMARY", icsText(place.Name))
+		if description := place.description(); description != "" {
+			line("DESCRIPTION", icsText(description))
+		}
+		if place.located() {
+			line("GEO", strconv.FormatFloat(*place.Lat, 'f', 6, 64)+";"+strconv.FormatFloat(*place.Lon, 'f', 6, 64))
+		}
+		if place.Kind != "" {
+			line("CATEGORIES", icsText(place.Kind))
+		}
+		line("TRANSP", "TRANSPARENT")
+		line("END", "VEVENT")
+	}
+
+	line("END", "VCALENDAR")
+	return []byte(b.String())
+}
+
+// icsText - Escapes a TEXT value
+func icsText(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(value)
+}
+
+// writeICSLine - Writes a content line folded at 75 octets (Never inside a UTF-8 character) and ended with CRLF
+func writeICSLine(b *strings.Builder, content string) {
+	limit := icsLineLength
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		b.WriteString(content[:cut])
+		b.WriteString("\r\n ")
+		content = content[cut:]
+		limit = icsLineLength - 1 // Continuation lines start with a space
+	}
+	b.WriteString(content)
+	b.WriteString("\r\n")
+}
@@ -0,0 +1,86 @@
+package export
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+)
+
+// KML 2.2 (https://developers.google.com/kml/documentation/kmlreference)
+type kmlFile struct {
+	XMLName  xml.Name    `xml:"kml"`
+	Xmlns    string      `xml:"xmlns,attr"`
+	Document kmlDocument `xml:"Document"`
+}
+
+type kmlDocument struct {
+	Name        string         `xml:"name"`
+	Description string         `xml:"description,omitempty"`
+	Placemarks  []kmlPlacemark `xml:"Placemark"`
+	Folders     []kmlFolder    `xml:"Folder"`
+}
+
+type kmlFolder struct {
+	Name       string         `xml:"name"`
+	Placemarks []kmlPlacemark `xml:"Placemark"`
+}
+
+type kmlPlacemark struct {
+	Name        string       `xml:"name"`
+	Description string       `xml:"description,omitempty"`
+	Point       *kmlGeometry `xml:"Point"`
+	LineString  *kmlGeometry `xml:"LineString"`
+}
+
+type kmlGeometry struct {
+	Coordinates string `xml:"coordinates"` // lon,lat pairs separated by spaces
+}
+
+// KML - Places outside a trip as placemarks, trip places in one folder per day with a route line
+func KML(document Document) ([]byte, error) {
+	file := kmlFile{
+		Xmlns:    "http://www.opengis.net/kml/2.2",
+		Document: kmlDocument{Name: document.Name, Description: document.Description},
+	}
+
+	folders := map[int]int{} // Day -> Folder index
+	for _, place := range document.Places {
+		placemark := kmlPlacemark{Name: place.Name, Description: place.description()}
+		if place.located() {
+			placemark.Point = &kmlGeometry{Coordinates: kmlCoordinates(place)}
+		}
+
+		if place.Day == 0 {
+			file.Document.Placemarks = append(file.Document.Placemarks, placemark)
+			continue
+		}
+		index, ok := folders[place.Day]
+		if !ok {
+			index = len(file.Document.Folders)
+			folders[place.Day] = index
+			file.Document.Folders = append(file.Document.Folders, kmlFolder{Name: day{Day: place.Day, Date: place.Date}.Name()})
+		}
+		file.Document.Folders[index].Placemarks = append(file.Document.Folders[index].Placemarks, placemark)
+	}
+
+	for _, day := range days(document) {
+		if len(day.Places) < 2 {
+			continue
+		}
+		coordinates := make([]string, 0, len(day.Places))
+		for _, place := range day.Places {
+			coordinates = append(coordinates, kmlCoordinates(place))
+		}
+		folder := &file.Document.Folders[folders[day.Day]]
+		folder.Placemarks = append(folder.Placemarks, kmlPlacemark{
+			Name:       day.Name() + " route",
+			LineString: &kmlGeometry{Coordinates: strings.Join(coordinates, " ")},
+		})
+	}
+
+	return marshalXML(file)
+}
+
+func kmlCoordinates(place Place) string {
+	return strconv.FormatFloat(*place.Lon, 'f', -1, 64) + "," + strconv.FormatFloat(*place.Lat, 'f', -1, 64)
+}
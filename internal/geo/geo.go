@@ -0,0 +1,106 @@
+package geo
+
+/* Geo Utilities
+
+- Great-circle distance (Haversine) and initial bearing between two points
+- Compass directions and estimated walking times
+*/
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+const (
+	EarthRadius  = 6371008.8 // Mean radius in metres
+	WalkingSpeed = 1.4       // Metres per second (~5 km/h)
+	DetourFactor = 1.3       // Streets are rarely a straight line
+)
+
+var compassDirections = []string{"N", "NE", "E", "SE", "S", "SW", "W", "NW"}
+
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// Distance, Direction and Walking Time from one point to another
+type Leg struct {
+	Distance       float64 `json:"distance"` // Metres
+	Bearing        float64 `json:"bearing"`  // Degrees
+	Direction      string  `json:"direction"`
+	WalkingMinutes int     `json:"walking_minutes"`
+}
+
+// NewLeg - Leg from one point to another (Distance rounded to the metre, Bearing to 1 decimal place)
+func NewLeg(from Point, to Point) Leg {
+	distance := Distance(from, to)
+	bearing := Bearing(from, to)
+	return Leg{
+		Distance:       math.Round(distance),
+		Bearing:        math.Round(bearing*10) / 10,
+		Direction:      Direction(bearing),
+		WalkingMinutes: WalkingMinutes(distance),
+	}
+}
+
+// ParsePoint - Parses and validates a latitude and longitude
+func ParsePoint(lat string, lon string) (Point, error) {
+	latitude, err := strconv.ParseFloat(lat, 64)
+	if err != nil || latitude < -90 || latitude > 90 {
+		return Point{}, fmt.Errorf("latitude must be between -90 and 90")
+	}
+	longitude, err := strconv.ParseFloat(lon, 64)
+	if err != nil || longitude < -180 || longitude > 180 {
+		return Point{}, fmt.Errorf("longitude must be between -180 and 180")
+	}
+	return Point{Lat: latitude, Lon: longitude}, nil
+}
+
+// Distance - Great-circle distance in metres
+func Distance(from Point, to Point) float64 {
+	lat1, lat2 := radians(from.Lat), radians(to.Lat)
+	dLat := lat2 - lat1
+	dLon := radians(to.Lon - from.Lon)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// Bearing - Initial bearing in degrees (0 - 360, clockwise from North)
+func Bearing(from Point, to Point) float64 {
+	lat1, lat2 := radians(from.Lat), radians(to.Lat)
+	dLon := radians(to.Lon - from.Lon)
+
+	y := math.Sin(dLon) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLon)
+	return math.Mod(degrees(math.Atan2(y, x))+360, 360)
+}
+
+// Direction - 8 point compass direction for a bearing (e.g. "NE")
+func Direction(bearing float64) string {
+	return compassDirections[int(math.Round(bearing/45))%len(compassDirections)]
+}
+
+// WalkingMinutes - Estimated walking time for a straight line distance (Rounded up to the next minute)
+func WalkingMinutes(distance float64) int {
+	return int(math.Ceil(distance * DetourFactor / WalkingSpeed / 60))
+}
+
+// Snap - Rounds a point to a number of decimal places (2 decimal places is ~1km), so nearby points share a cache row
+func Snap(point Point, decimals int) Point {
+	scale := math.Pow(10, float64(decimals))
+	return Point{
+		Lat: math.Round(point.Lat*scale) / scale,
+		Lon: math.Round(point.Lon*scale) / scale,
+	}
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+func degrees(radians float64) float64 {
+	return radians * 180 / math.Pi
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/MCantyDev/city-explorer-server/internal/database"
 	"github.com/MCantyDev/city-explorer-server/internal/models"
@@ -202,7 +203,7 @@ func GetProfile(c *gin.Context) {
 	}
 
 	var user models.User
-	query := database.NewQueryBuilder("SELECT").Table("users").Columns("first_name", "last_name", "username", "email", "is_admin").Where("id = ?").Build()
+	query := database.NewQueryBuilder("SELECT").Table("users").Columns("first_name", "last_name", "username", "email", "home_country_code", "is_admin").Where("id = ?").Build()
 
 	_, err := database.Execute(&user, query, userID)
 	if err != nil {
@@ -212,10 +213,51 @@ func GetProfile(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"firstName": user.FirstName,
-		"lastName":  user.LastName,
-		"username":  user.Username,
-		"email":     user.Email,
-		"isAdmin":   user.IsAdmin,
+		"firstName":       user.FirstName,
+		"lastName":        user.LastName,
+		"username":        user.Username,
+		"email":           user.Email,
+		"homeCountryCode": user.HomeCountryCode,
+		"isAdmin":         user.IsAdmin,
+	})
+}
+
+// SetHomeCountry - Sets the user's home country (Used as the default currency conversion target)
+func SetHomeCountry(c *gin.Context) {
+	var req models.HomeCountryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Must submit ISO 3166-1 Alpha-2 'country_code'",
+		})
+		return
+	}
+	countryCode := strings.ToUpper(req.CountryCode)
+	if !checkCountryCode(c, countryCode) {
+		return
+	}
+
+	userID, _ := c.Get("userId")
+	query := database.NewQueryBuilder("UPDATE").Table("users").Columns("home_country_code").Where("id = ?").Build()
+	_, err := database.Execute(nil, query, countryCode, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to update User data on Server",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"homeCountryCode": countryCode,
 	})
 }
+
+// checkCountryCode - Ensures the country exists and caches it (Responds with 400 otherwise)
+func checkCountryCode(c *gin.Context, countryCode string) bool {
+	if _, err := getCountryData(countryCode, ""); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Unknown country '%s'", countryCode),
+		})
+		return false
+	}
+	return true
+}
@@ -64,12 +64,17 @@ func AddUser(c *gin.Context) {
 	var req models.AddUser
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(http.StatusBadRequest, gin.H{
 			"error": err.Error(),
 		})
 		return
 	}
 
+	countryCode := strings.ToUpper(req.HomeCountryCode)
+	if countryCode != "" && !checkCountryCode(c, countryCode) {
+		return
+	}
+
 	hashedPassword, err := services.HashPassword(req.Password)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -78,8 +83,8 @@ func AddUser(c *gin.Context) {
 		return
 	}
 
-	query := database.NewQueryBuilder("INSERT").Table("users").Columns("first_name", "last_name", "username", "email", "password", "is_admin").Values(6).Build()
-	_, err = database.Execute(nil, query, req.FirstName, req.LastName, req.Username, req.Email, hashedPassword, req.IsAdmin)
+	query := database.NewQueryBuilder("INSERT").Table("users").Columns("first_name", "last_name", "username", "email", "home_country_code", "password", "is_admin").Values(7).Build()
+	_, err = database.Execute(nil, query, req.FirstName, req.LastName, req.Username, req.Email, countryCode, hashedPassword, req.IsAdmin)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
@@ -96,12 +101,25 @@ func EditUser(c *gin.Context) {
 	var req models.EditUser
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(http.StatusBadRequest, gin.H{
 			"error": err.Error(),
 		})
 		return
 	}
 
+	columns := []string{"first_name", "last_name", "username", "email", "is_admin"}
+	values := []any{req.FirstName, req.LastName, req.Username, req.Email, req.IsAdmin}
+
+	// Omitted home country keeps the current one
+	if req.HomeCountryCode != nil {
+		countryCode := strings.ToUpper(*req.HomeCountryCode)
+		if !checkCountryCode(c, countryCode) {
+			return
+		}
+		columns = append(columns, "home_country_code")
+		values = append(values, countryCode)
+	}
+
 	if req.Password != "" {
 		// Hash Password
 		hashedPassword, err := services.HashPassword(req.Password)
@@ -111,24 +129,17 @@ func EditUser(c *gin.Context) {
 			})
 			return
 		}
+		columns = append(columns, "password")
+		values = append(values, hashedPassword)
+	}
 
-		query := database.NewQueryBuilder("UPDATE").Table("users").Columns("first_name", "last_name", "username", "email", "password", "is_admin").Where("id = ?").Build()
-		_, err = database.Execute(nil, query, req.FirstName, req.LastName, req.Username, req.Email, hashedPassword, req.IsAdmin, req.Id)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": err.Error(),
-			})
-			return
-		}
-	} else {
-		query := database.NewQueryBuilder("UPDATE").Table("users").Columns("first_name", "last_name", "username", "email", "is_admin").Where("id = ?").Build()
-		_, err := database.Execute(nil, query, req.FirstName, req.LastName, req.Username, req.Email, req.IsAdmin, req.Id)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": err.Error(),
-			})
-			return
-		}
+	query := database.NewQueryBuilder("UPDATE").Table("users").Columns(columns...).Where("id = ?").Build()
+	_, err := database.Execute(nil, query, append(values, req.Id)...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -161,10 +172,18 @@ func DeleteUser(c *gin.Context) {
 }
 
 func GetCountries(c *gin.Context) {
+	schemaVersion, err := countrySchemaVersion(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
 	var countries []models.Country
 
 	query := database.NewQueryBuilder("SELECT").Table("countries").Build()
-	_, err := database.Execute(&countries, query)
+	_, err = database.Execute(&countries, query)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Error occured querying database",
@@ -172,6 +191,13 @@ func GetCountries(c *gin.Context) {
 		return
 	}
 
+	// Typed profile alongside the raw data when requested (nil if the data cannot be parsed)
+	if schemaVersion == models.CountrySchemaProfile {
+		for i := range countries {
+			countries[i].Profile, _ = services.ParseCountryProfile(countries[i].Data)
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"result": countries,
 	})
@@ -181,7 +207,7 @@ func GetCityWeatherTable(c *gin.Context) {
 	var weatherReports []models.CityWeather
 
 	query := database.NewQueryBuilder("SELECT").Table("city_weather").
-		Columns("city_weather.id", "lat", "lon", "cities.name AS city_name", "countries.name AS country_name", "city_weather.data", "city_weather.created_at", "city_weather.updated_at", "city_weather.expiry_date").
+		Columns("city_weather.id", "city_weather.city_id", "city_weather.country_id", "city_weather.lat", "city_weather.lon", "cities.name AS city_name", "countries.name AS country_name", "city_weather.data", "city_weather.created_at", "city_weather.updated_at", "city_weather.expiry_date").
 		Join("JOIN cities ON cities.id=city_weather.city_id").Join("JOIN countries ON countries.id=city_weather.country_id").Build()
 	_, err := database.Execute(&weatherReports, query)
 	if err != nil {
@@ -200,7 +226,7 @@ func GetCitySightsTable(c *gin.Context) {
 	var sights []models.CitySights
 
 	query := database.NewQueryBuilder("SELECT").Table("city_sights").
-		Columns("city_sights.id", "lat", "lon", "cities.name AS city_name", "countries.name AS country_name", "city_sights.data", "city_sights.created_at", "city_sights.updated_at", "city_sights.expiry_date").
+		Columns("city_sights.id", "city_sights.city_id", "city_sights.country_id", "city_sights.lat", "city_sights.lon", "cities.name AS city_name", "countries.name AS country_name", "city_sights.search_key", "city_sights.data", "city_sights.created_at", "city_sights.updated_at", "city_sights.expiry_date").
 		Join("JOIN cities ON cities.id=city_sights.city_id").Join("JOIN countries ON countries.id=city_sights.country_id").Build()
 	_, err := database.Execute(&sights, query)
 	if err != nil {
@@ -219,7 +245,7 @@ func GetCityPoisTable(c *gin.Context) {
 	var pois []models.CityPoi
 
 	query := database.NewQueryBuilder("SELECT").Table("city_pois").
-		Columns("city_pois.id", "cities.name AS city_name", "countries.name AS country_name", "xid", "city_pois.data", "city_pois.created_at", "city_pois.updated_at", "city_pois.expiry_date").
+		Columns("city_pois.id", "city_pois.city_id", "city_pois.country_id", "cities.name AS city_name", "countries.name AS country_name", "xid", "city_pois.data", "city_pois.created_at", "city_pois.updated_at", "city_pois.expiry_date").
 		Join("JOIN cities ON cities.id=city_pois.city_id").Join("JOIN countries ON countries.id=city_pois.country_id").Build()
 	_, err := database.Execute(&pois, query)
 	if err != nil {
@@ -234,6 +260,34 @@ func GetCityPoisTable(c *gin.Context) {
 	})
 }
 
+// GetPoiReviewsTable - Every POI review (Optional 'flagged=true', 'hidden=true' and 'xid' filters)
+func GetPoiReviewsTable(c *gin.Context) {
+	reviews, err := services.ListModerationReviews(c.Query("flagged") == "true", c.Query("hidden") == "true", c.Query("xid"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error occured querying database",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"result": reviews,
+	})
+}
+
+// PreloadCountries - Fetches and caches every country in one batch (Returns what changed)
+func PreloadCountries(c *gin.Context) {
+	report, err := services.PreloadCountries()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
 func RefreshCountry(c *gin.Context) {
 	countryCode := c.Query("country-code")
 	if countryCode == "" {
@@ -244,14 +298,7 @@ func RefreshCountry(c *gin.Context) {
 	}
 
 	// Retreive Refreshed Data
-	url := fmt.Sprintf(config.Cfg.RestCountriesAPI.URL, countryCode)
-	if url == "" || !strings.HasPrefix(url, "https://") {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "'" + config.Cfg.RestCountriesAPI.Name + "' URL is not properly setup",
-		})
-		return
-	}
-	data, err := services.FetchExternalAPI(url)
+	data, err := services.FetchCountry(countryCode)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
@@ -259,20 +306,8 @@ func RefreshCountry(c *gin.Context) {
 		return
 	}
 
-	var validate models.RestCountriesRequest
-	if err := json.Unmarshal(data, &validate); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid JSON from RestCountries: " + err.Error()})
-		return
-	}
-
-	// UPDATE
-	expiry := time.Now().AddDate(1, 0, 0)
-
-	// This was strange...basically Rest Countries returns a json array [{}, {}...]
-	// but im only being returned a single result (as im using Country codes)
-	// So I am now just taking the FIRST element of each result and using that for Data...so the application doesnt get stuck on 'GetCountries'
-	query := database.NewQueryBuilder("UPDATE").Table("countries").Columns("data", "expiry_date").Where("iso_code = ?").Build()
-	_, err = database.Execute(nil, query, validate[0], expiry, countryCode)
+	// UPDATE (Name is kept if the country already exists)
+	err = services.SaveCountry("", data)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
@@ -296,14 +331,7 @@ func RefreshCityWeather(c *gin.Context) {
 	}
 
 	// Retreive Refreshed Data
-	url := fmt.Sprintf(config.Cfg.OpenWeatherAPI.URL, lat, lon, config.Cfg.OpenWeatherAPI.Key)
-	if url == "" || !strings.HasPrefix(url, "https://") {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "'" + config.Cfg.OpenWeatherAPI.Name + "' URL is not set up properly",
-		})
-		return
-	}
-	data, err := services.FetchExternalAPI(url)
+	data, err := services.FetchWeather(lat, lon)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
@@ -338,15 +366,17 @@ func RefreshCitySights(c *gin.Context) {
 		return
 	}
 
-	// Retreive Refreshed Data
-	url := fmt.Sprintf(config.Cfg.OpenTripAPI.URL, lat, lon, config.Cfg.OpenTripAPI.Key)
-	if url == "" || !strings.HasPrefix(url, "https://") {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "'" + config.Cfg.OpenTripAPI.Name + "' URL is not setup properly",
+	// Same optional search parameters as the client endpoint (Defaults refresh the default search)
+	search, err := services.ParseSightsSearch(c.Query("radius"), c.Query("kinds"), c.Query("rate"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
 		})
 		return
 	}
-	data, err := services.FetchExternalAPI(url)
+
+	// Retreive Refreshed Data
+	data, err := services.FetchSights(lat, lon, search)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
@@ -357,8 +387,8 @@ func RefreshCitySights(c *gin.Context) {
 	// UPDATE
 	expiry := time.Now().AddDate(0, 0, 1)
 
-	query := database.NewQueryBuilder("UPDATE").Table("city_sights").Columns("data", "expiry_date").Where("ABS(lat - ?) < 0.0001 AND ABS(lon - ?) < 0.0001").Build()
-	_, err = database.Execute(nil, query, data, expiry, lat, lon)
+	query := database.NewQueryBuilder("UPDATE").Table("city_sights").Columns("data", "expiry_date").Where("ABS(lat - ?) < 0.0001 AND ABS(lon - ?) < 0.0001 AND search_key = ?").Build()
+	_, err = database.Execute(nil, query, data, expiry, lat, lon, services.SightsSearchKey(search))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
@@ -508,3 +538,440 @@ func DeleteCityPoi(c *gin.Context) {
 		"error": nil,
 	})
 }
+
+// HidePoiReview - Hides a review from everyone but its author, or shows it again ('hidden': false)
+func HidePoiReview(c *gin.Context) {
+	var req models.HidePoiReview
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Could not bind data to model in server",
+		})
+		return
+	}
+
+	if err := services.HidePoiReview(req.Id, req.Hidden); err != nil {
+		respondReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error": nil,
+	})
+}
+
+// FlagPoiReview - Flags a review for a closer look, or clears the flag ('flagged': false)
+func FlagPoiReview(c *gin.Context) {
+	var req models.FlagPoiReview
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Could not bind data to model in server",
+		})
+		return
+	}
+	if err := services.SetPoiReviewFlag(req.Id, req.Flagged, req.Reason); err != nil {
+		respondReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error": nil,
+	})
+}
+
+// RemovePoiReview - Deletes any user's review
+func RemovePoiReview(c *gin.Context) {
+	var req models.Delete
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Could not bind data to model in server",
+		})
+		return
+	}
+
+	if err := services.RemovePoiReview(req.Id); err != nil {
+		respondReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error": nil,
+	})
+}
+
+// Short lived Forecast Tables (Hourly, Minutely, Alerts)
+
+func GetCityWeatherHourlyTable(c *gin.Context) {
+	getForecastTable(c, hourlyForecast)
+}
+
+func GetCityWeatherMinutelyTable(c *gin.Context) {
+	getForecastTable(c, minutelyForecast)
+}
+
+func GetCityWeatherAlertsTable(c *gin.Context) {
+	getForecastTable(c, alertsForecast)
+}
+
+func RefreshCityWeatherHourly(c *gin.Context) {
+	refreshForecast(c, hourlyForecast)
+}
+
+func RefreshCityWeatherMinutely(c *gin.Context) {
+	refreshForecast(c, minutelyForecast)
+}
+
+func RefreshCityWeatherAlerts(c *gin.Context) {
+	refreshForecast(c, alertsForecast)
+}
+
+func DeleteCityWeatherHourly(c *gin.Context) {
+	deleteForecast(c, hourlyForecast)
+}
+
+func DeleteCityWeatherMinutely(c *gin.Context) {
+	deleteForecast(c, minutelyForecast)
+}
+
+func DeleteCityWeatherAlerts(c *gin.Context) {
+	deleteForecast(c, alertsForecast)
+}
+
+func getForecastTable(c *gin.Context, forecast weatherForecast) {
+	var reports []models.CityWeather
+
+	table := forecast.Table
+	query := database.NewQueryBuilder("SELECT").Table(table).
+		Columns(table+".id", table+".city_id", table+".country_id", table+".lat", table+".lon", "cities.name AS city_name", "countries.name AS country_name", table+".data", table+".created_at", table+".updated_at", table+".expiry_date").
+		Join("JOIN cities ON cities.id=" + table + ".city_id").Join("JOIN countries ON countries.id=" + table + ".country_id").Build()
+	_, err := database.Execute(&reports, query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error occured querying database",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"result": reports,
+	})
+}
+
+func refreshForecast(c *gin.Context, forecast weatherForecast) {
+	lat := c.Query("lat")
+	lon := c.Query("lon")
+	if lat == "" || lon == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Missing 'lat' or 'lon' query parameter",
+		})
+		return
+	}
+
+	// Retreive Refreshed Data
+	data, err := services.FetchForecast(forecast.Kind, lat, lon)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// UPDATE
+	expiry := time.Now().Add(forecast.TTL)
+
+	query := database.NewQueryBuilder("UPDATE").Table(forecast.Table).Columns("data", "expiry_date").Where("ABS(lat - ?) < 0.0001 AND ABS(lon - ?) < 0.0001").Build()
+	_, err = database.Execute(nil, query, data, expiry, lat, lon)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error": nil,
+	})
+}
+
+func deleteForecast(c *gin.Context, forecast weatherForecast) {
+	var req models.Delete
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	query := database.NewQueryBuilder("DELETE").Table(forecast.Table).Where("id = ?").Build()
+	_, err := database.Execute(nil, query, req.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error": nil,
+	})
+}
+
+func GetCityAirQualityTable(c *gin.Context) {
+	var airQualityReports []models.CityAirQuality
+
+	query := database.NewQueryBuilder("SELECT").Table("city_air_quality").
+		Columns("city_air_quality.id", "city_air_quality.city_id", "city_air_quality.country_id", "city_air_quality.lat", "city_air_quality.lon", "cities.name AS city_name", "countries.name AS country_name", "city_air_quality.data", "city_air_quality.created_at", "city_air_quality.updated_at", "city_air_quality.expiry_date").
+		Join("JOIN cities ON cities.id=city_air_quality.city_id").Join("JOIN countries ON countries.id=city_air_quality.country_id").Build()
+	_, err := database.Execute(&airQualityReports, query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error occured querying database",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"result": airQualityReports,
+	})
+}
+
+func RefreshCityAirQuality(c *gin.Context) {
+	lat := c.Query("lat")
+	lon := c.Query("lon")
+	if lat == "" || lon == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Missing 'lat' or 'lon' query parameter",
+		})
+		return
+	}
+
+	// Retreive Refreshed Data
+	airQuality, err := services.FetchAirQuality(lat, lon)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	data, err := json.Marshal(airQuality)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// UPDATE
+	expiry := time.Now().Add(time.Hour)
+
+	query := database.NewQueryBuilder("UPDATE").Table("city_air_quality").Columns("data", "expiry_date").Where("ABS(lat - ?) < 0.0001 AND ABS(lon - ?) < 0.0001").Build()
+	_, err = database.Execute(nil, query, data, expiry, lat, lon)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error": nil,
+	})
+}
+
+func DeleteCityAirQuality(c *gin.Context) {
+	var req models.Delete
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	query := database.NewQueryBuilder("DELETE").Table("city_air_quality").Where("id = ?").Build()
+	_, err := database.Execute(nil, query, req.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error": nil,
+	})
+}
+
+func GetCityWeatherHistoryTable(c *gin.Context) {
+	var histories []models.CityWeatherHistory
+
+	// Data is left out (10 years of daily history per row)
+	query := database.NewQueryBuilder("SELECT").Table("city_weather_history").
+		Columns("city_weather_history.id", "city_weather_history.city_id", "city_weather_history.country_id", "city_weather_history.lat", "city_weather_history.lon", "cities.name AS city_name", "countries.name AS country_name", "start_year", "end_year", "city_weather_history.created_at", "city_weather_history.updated_at", "city_weather_history.expiry_date").
+		Join("JOIN cities ON cities.id=city_weather_history.city_id").Join("JOIN countries ON countries.id=city_weather_history.country_id").Build()
+	_, err := database.Execute(&histories, query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error occured querying database",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"result": histories,
+	})
+}
+
+func DeleteCityWeatherHistory(c *gin.Context) {
+	var req models.Delete
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	query := database.NewQueryBuilder("DELETE").Table("city_weather_history").Where("id = ?").Build()
+	_, err := database.Execute(nil, query, req.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error": nil,
+	})
+}
+
+func GetReverseGeocodesTable(c *gin.Context) {
+	var geocodes []models.ReverseGeocode
+
+	query := database.NewQueryBuilder("SELECT").Table("reverse_geocodes").Build()
+	_, err := database.Execute(&geocodes, query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error occured querying database",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"result": geocodes,
+	})
+}
+
+func DeleteReverseGeocode(c *gin.Context) {
+	var req models.Delete
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	query := database.NewQueryBuilder("DELETE").Table("reverse_geocodes").Where("id = ?").Build()
+	_, err := database.Execute(nil, query, req.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error": nil,
+	})
+}
+
+func GetAutocompleteCacheTable(c *gin.Context) {
+	var prefixes []models.AutocompleteCache
+
+	query := database.NewQueryBuilder("SELECT").Table("autocomplete_cache").Build()
+	_, err := database.Execute(&prefixes, query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error occured querying database",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"result": prefixes,
+	})
+}
+
+func DeleteAutocompleteCache(c *gin.Context) {
+	var req models.Delete
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	query := database.NewQueryBuilder("DELETE").Table("autocomplete_cache").Where("id = ?").Build()
+	_, err := database.Execute(nil, query, req.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// The memory cache may still hold the deleted prefix
+	services.ClearAutocompleteMemory()
+
+	c.JSON(http.StatusOK, gin.H{
+		"error": nil,
+	})
+}
+
+func GetExchangeRatesTable(c *gin.Context) {
+	var rates []models.ExchangeRate
+
+	query := database.NewQueryBuilder("SELECT").Table("exchange_rates").Build()
+	_, err := database.Execute(&rates, query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error occured querying database",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"result": rates,
+	})
+}
+
+func DeleteExchangeRates(c *gin.Context) {
+	var req models.Delete
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	query := database.NewQueryBuilder("DELETE").Table("exchange_rates").Where("id = ?").Build()
+	_, err := database.Execute(nil, query, req.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error": nil,
+	})
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/MCantyDev/city-explorer-server/internal/database"
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+	"github.com/MCantyDev/city-explorer-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+func GetAirQuality(c *gin.Context) {
+	lat := c.Query("lat")
+	long := c.Query("long")
+	if lat == "" || long == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Missing 'lat' or 'long' query parameter",
+		})
+		return
+	}
+
+	city := c.Query("city")
+	countryCode := c.Query("country-code")
+	if city == "" || countryCode == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Missing 'city' or 'country-code' query parameter",
+		})
+		return
+	}
+
+	// Try to retrieve data from the database
+	var cityAirQuality models.CityAirQuality
+	query := database.NewQueryBuilder("SELECT").Table("city_air_quality").Where("ABS(lat - ?) < 0.0001 AND ABS(lon - ?) < 0.0001").Build()
+	_, err := database.Execute(&cityAirQuality, query, lat, long)
+	if err == nil && cityAirQuality.Id > 0 && cityAirQuality.ExpiryDate.After(time.Now()) {
+		c.JSON(http.StatusOK, cityAirQuality.Data)
+		return
+	}
+
+	// Fetch Data from the Air Quality Provider (Falls back to the other provider on failure)
+	airQuality, err := services.FetchAirQuality(lat, long)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	data, err := json.Marshal(airQuality)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// Air Quality is updated hourly by both providers
+	expiry := time.Now().Add(time.Hour)
+
+	if cityAirQuality.Id > 0 {
+		query = database.NewQueryBuilder("UPDATE").Table("city_air_quality").Columns("data", "expiry_date").Where("id = ?").Build()
+		_, err = database.Execute(nil, query, data, expiry, cityAirQuality.Id)
+	} else {
+		city, country, ok := resolveCity(c, city, countryCode, lat, long)
+		if !ok {
+			return
+		}
+		query = database.NewQueryBuilder("INSERT").Table("city_air_quality").Columns("lat", "lon", "city_id", "country_id", "data", "expiry_date").Values(6).Build()
+		_, err = database.Execute(nil, query, lat, long, city.Id, country.Id, data, expiry)
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Database error while saving air quality data",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, airQuality)
+}
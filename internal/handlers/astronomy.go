@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/MCantyDev/city-explorer-server/internal/geo"
+	"github.com/MCantyDev/city-explorer-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// GetCityTime - Local time, sunrise/sunset, golden hour, day length and moon phase for a city (Optional 'date')
+func GetCityTime(c *gin.Context) {
+	lat := c.Query("lat")
+	long := c.Query("long")
+	if lat == "" || long == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Missing 'lat' or 'long' query parameter",
+		})
+		return
+	}
+
+	name := c.Query("city")
+	countryCode := c.Query("country-code")
+	if name == "" || countryCode == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Missing 'city' or 'country-code' query parameter",
+		})
+		return
+	}
+
+	point, err := geo.ParsePoint(lat, long)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// Read only lookups, unknown cities and countries fall back to the longitude
+	country, _ := services.GetCountry(countryCode)
+	city, err := services.FindCity(name, countryCode, point)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error occured querying database",
+		})
+		return
+	}
+
+	loc, source := services.CityTimezone(city, country, point.Lon)
+	cityTime, err := services.GetCityTime(loc, source, point.Lat, point.Lon, c.Query("date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, cityTime)
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/MCantyDev/city-explorer-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+func AutocompleteCities(c *gin.Context) {
+	search, err := services.ParseAutocomplete(c.Query("q"), c.Query("limit"), c.Query("lang"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	result, err := services.AutocompleteCities(search)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// Suggestions rarely change, so browsers can reuse them while the user edits the query
+	c.Header("Cache-Control", "private, max-age=300")
+	c.JSON(http.StatusOK, result)
+}
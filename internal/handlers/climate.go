@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/MCantyDev/city-explorer-server/internal/database"
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+	"github.com/MCantyDev/city-explorer-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// Longest date range that can be summarised for past years
+const maxClimateRangeDays = 92
+
+func GetClimate(c *gin.Context) {
+	lat := c.Query("lat")
+	long := c.Query("long")
+	if lat == "" || long == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Missing 'lat' or 'long' query parameter",
+		})
+		return
+	}
+
+	city := c.Query("city")
+	countryCode := c.Query("country-code")
+	if city == "" || countryCode == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Missing 'city' or 'country-code' query parameter",
+		})
+		return
+	}
+
+	options, err := climateOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	startYear, endYear := services.ClimateYearRange(time.Now())
+
+	// Try to retrieve data from the database (History never changes, so only the year range matters)
+	var cityHistory models.CityWeatherHistory
+	query := database.NewQueryBuilder("SELECT").Table("city_weather_history").Where("ABS(lat - ?) < 0.0001 AND ABS(lon - ?) < 0.0001").Build()
+	_, err = database.Execute(&cityHistory, query, lat, long)
+	if err == nil && cityHistory.Id > 0 && cityHistory.EndYear == endYear && cityHistory.ExpiryDate.After(time.Now()) {
+		var history models.WeatherHistory
+		if err := json.Unmarshal(cityHistory.Data, &history); err == nil {
+			c.JSON(http.StatusOK, services.SummariseClimate(&history, cityHistory.StartYear, cityHistory.EndYear, options))
+			return
+		}
+	}
+
+	// Fetch Data from the Historical Weather Provider
+	history, err := services.FetchWeatherHistory(lat, long, startYear, endYear)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	data, err := json.Marshal(history)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	expiry := services.ClimateExpiry(time.Now())
+
+	if cityHistory.Id > 0 {
+		query = database.NewQueryBuilder("UPDATE").Table("city_weather_history").Columns("start_year", "end_year", "data", "expiry_date").Where("id = ?").Build()
+		_, err = database.Execute(nil, query, startYear, endYear, data, expiry, cityHistory.Id)
+	} else {
+		city, country, ok := resolveCity(c, city, countryCode, lat, long)
+		if !ok {
+			return
+		}
+		query = database.NewQueryBuilder("INSERT").Table("city_weather_history").Columns("lat", "lon", "city_id", "country_id", "start_year", "end_year", "data", "expiry_date").Values(8).Build()
+		_, err = database.Execute(nil, query, lat, long, city.Id, country.Id, startYear, endYear, data, expiry)
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Database error while saving weather history",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, services.SummariseClimate(history, startYear, endYear, options))
+}
+
+// climateOptions - Reads the 'units', 'start' and 'end' query parameters (Dates are YYYY-MM-DD, only the month and day are used)
+func climateOptions(c *gin.Context) (models.ClimateOptions, error) {
+	options := models.ClimateOptions{
+		Units: c.DefaultQuery("units", models.UnitsMetric),
+	}
+
+	if options.Units != models.UnitsMetric && options.Units != models.UnitsImperial {
+		return options, fmt.Errorf("'units' must be '%s' or '%s'", models.UnitsMetric, models.UnitsImperial)
+	}
+
+	start := c.Query("start")
+	end := c.Query("end")
+	if start == "" && end == "" {
+		return options, nil
+	}
+	if start == "" || end == "" {
+		return options, fmt.Errorf("'start' and 'end' must both be set for a date range")
+	}
+
+	rangeStart, err := time.Parse("2006-01-02", start)
+	if err != nil {
+		return options, fmt.Errorf("'start' must be a date (YYYY-MM-DD)")
+	}
+	rangeEnd, err := time.Parse("2006-01-02", end)
+	if err != nil {
+		return options, fmt.Errorf("'end' must be a date (YYYY-MM-DD)")
+	}
+	if rangeEnd.Before(rangeStart) {
+		return options, fmt.Errorf("'end' must not be before 'start'")
+	}
+	if rangeEnd.Sub(rangeStart) > maxClimateRangeDays*24*time.Hour {
+		return options, fmt.Errorf("date range must be %d days or less", maxClimateRangeDays)
+	}
+
+	options.HasRange = true
+	options.RangeStart = rangeStart
+	options.RangeEnd = rangeEnd
+	return options, nil
+}
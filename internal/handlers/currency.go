@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/MCantyDev/city-explorer-server/internal/database"
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+	"github.com/MCantyDev/city-explorer-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ConvertCurrency - Converts 'amount' from the explored country's currency (or 'from') to the user's home currency (or 'to')
+func ConvertCurrency(c *gin.Context) {
+	amount := 1.0
+	if value := c.Query("amount"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed <= 0 || math.IsInf(parsed, 0) || math.IsNaN(parsed) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "'amount' must be a positive number",
+			})
+			return
+		}
+		amount = parsed
+	}
+
+	from, err := currencyParam(c.Query("from"), c.Query("country-code"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if from == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Missing 'from' or 'country-code' query parameter",
+		})
+		return
+	}
+
+	// Target defaults to the currency of the user's home country
+	userId, _ := c.Get("userId")
+	var user models.User
+	query := database.NewQueryBuilder("SELECT").Table("users").Columns("id", "home_country_code").Where("id = ?").Build()
+	_, err = database.Execute(&user, query, userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retreive User data from Server",
+		})
+		return
+	}
+
+	to, err := currencyParam(c.Query("to"), user.HomeCountryCode)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if to == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Missing 'to' query parameter (No home country is set for this user)",
+		})
+		return
+	}
+
+	conversion, err := services.ConvertCurrency(amount, from, to)
+	if errors.Is(err, services.ErrUnknownCurrency) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, conversion)
+}
+
+// currencyParam - The currency code if given, otherwise the first currency of the country with a rate (Empty if neither is set)
+func currencyParam(currency string, countryCode string) (string, error) {
+	if currency != "" {
+		return services.ParseCurrencyCode(currency)
+	}
+	if countryCode == "" {
+		return "", nil
+	}
+	if len(countryCode) != 2 {
+		return "", fmt.Errorf("must submit ISO 3166-1 Alpha-2 country code")
+	}
+
+	data, err := getCountryData(strings.ToUpper(countryCode), "")
+	if err != nil {
+		return "", err
+	}
+	profile, err := services.ParseCountryProfile(data)
+	if err != nil {
+		return "", err
+	}
+	if len(profile.Currencies) == 0 {
+		return "", fmt.Errorf("%s has no currency", profile.Name)
+	}
+
+	return services.CountryCurrency(profile.Currencies)
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/MCantyDev/city-explorer-server/internal/export"
+	"github.com/MCantyDev/city-explorer-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ExportTrip - The trip as GPX, KML, GeoJSON or iCalendar ('format' query parameter or 'Accept' header)
+func ExportTrip(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+
+	trip, err := services.GetTrip(userId, id)
+	if err != nil {
+		respondTripError(c, err)
+		return
+	}
+
+	respondExport(c, services.TripDocument(trip, time.Now()), format)
+}
+
+// ExportFavourites - The user's favourites as GPX, KML or GeoJSON (Optional 'kind' and 'tag' filters)
+func ExportFavourites(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+
+	kind := c.Query("kind")
+	if kind != "" && !services.IsFavouriteKind(kind) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "'kind' must be 'city', 'poi' or 'place'",
+		})
+		return
+	}
+
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+	if format == export.FormatICS {
+		c.JSON(http.StatusNotAcceptable, gin.H{
+			"error": "Favourites have no dates, iCalendar export is only available for trips",
+		})
+		return
+	}
+
+	favourites, err := services.ListFavourites(userId, kind, c.Query("tag"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error occured querying database",
+		})
+		return
+	}
+
+	respondExport(c, services.FavouritesDocument(favourites, time.Now()), format)
+}
+
+// exportFormat - The 'format' query parameter, otherwise the best match for the 'Accept' header (GeoJSON when anything is accepted)
+func exportFormat(c *gin.Context) (export.Format, bool) {
+	if value := c.Query("format"); value != "" {
+		format, err := export.ParseFormat(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return "", false
+		}
+		return format, true
+	}
+
+	if format, ok := export.FormatForMediaType(c.NegotiateFormat(export.MediaTypes()...)); ok {
+		return format, true
+	}
+
+	c.JSON(http.StatusNotAcceptable, gin.H{
+		"error": fmt.Sprintf("Cannot export as '%s', accepted types are %s", c.GetHeader("Accept"), strings.Join(export.MediaTypes(), ", ")),
+	})
+	return "", false
+}
+
+// respondExport - Sends the rendered document as a download
+func respondExport(c *gin.Context, document export.Document, format export.Format) {
+	data, err := export.Render(document, format)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error occured rendering export",
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, export.Filename(document, format)))
+	c.Header("Vary", "Accept")
+	c.Data(http.StatusOK, export.ContentType(format), data)
+}
@@ -4,12 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/MCantyDev/city-explorer-server/internal/config"
 	"github.com/MCantyDev/city-explorer-server/internal/database"
+	"github.com/MCantyDev/city-explorer-server/internal/geo"
 	"github.com/MCantyDev/city-explorer-server/internal/models"
 	"github.com/MCantyDev/city-explorer-server/internal/services"
 	"github.com/gin-gonic/gin"
@@ -26,20 +27,16 @@ func GetCities(c *gin.Context) {
 		return
 	}
 
-	// Encoding the Input for spaces and such
-	encodedCity := url.QueryEscape(city)
-
-	// Get the URL Template for Photon API
-	url := fmt.Sprintf(config.Cfg.PhotonAPI.URL, encodedCity)
-	if url == "" || !strings.HasPrefix(url, "https://") {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "'" + config.Cfg.PhotonAPI.Name + "' URL is not properly setup",
+	search, err := services.ParsePlaceSearch(city, c.Query("limit"), c.Query("lang"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
 		})
 		return
 	}
 
-	// Use City to Call External API
-	data, err := services.FetchExternalAPI(url)
+	// Only populated places are returned (Streets, shops etc. are filtered out)
+	cities, err := services.SearchCities(search)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": err.Error(),
@@ -47,17 +44,30 @@ func GetCities(c *gin.Context) {
 		return
 	}
 
-	// Map City Data
-	var externalData models.PhotonRequest
-	err = json.Unmarshal(data, &externalData)
+	c.JSON(http.StatusOK, gin.H{
+		"results": cities,
+	})
+}
+
+// resolveCity - Finds (or creates) the canonical city for a cache row, responding with an error if it cannot
+func resolveCity(c *gin.Context, name string, countryCode string, lat string, lon string) (*models.City, *models.Country, bool) {
+	country, _ := services.GetCountry(countryCode)
+	if country == nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Unknown city or country (Fetch the country first)",
+		})
+		return nil, nil, false
+	}
+
+	city, err := services.GetOrCreateCity(name, country, lat, lon)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Unknown city or country (" + err.Error() + ")",
 		})
-		return
+		return nil, nil, false
 	}
 
-	c.JSON(http.StatusOK, externalData)
+	return city, country, true
 }
 
 func GetCountry(c *gin.Context) {
@@ -86,27 +96,35 @@ func GetCountry(c *gin.Context) {
 		return
 	}
 
-	// Try to Retrieve Data from Server (IF nothing is returned retrieve using External API)
-	var countryData models.Country
-	query := database.NewQueryBuilder("SELECT").Table("countries").Where("iso_code = ?").Build()
-	_, err := database.Execute(&countryData, query, countryCode)
-	if err == nil && countryData.Id > 0 && countryData.ExpiryDate.After(time.Now()) {
-		c.JSON(http.StatusOK, countryData.Data)
+	schemaVersion, err := countrySchemaVersion(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
 		return
 	}
 
-	// Get the URL Template for Rest Countries API
-	url := fmt.Sprintf(config.Cfg.RestCountriesAPI.URL, countryCode)
-	if url == "" || !strings.HasPrefix(url, "https://") {
+	data, err := getCountryData(countryCode, name)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "'" + config.Cfg.RestCountriesAPI.Name + "' URL is not properly setup",
+			"error": err.Error(),
 		})
 		return
 	}
 
-	// Use Country Code to Call External API
-	var externalData models.RestCountriesRequest
-	data, err := services.FetchExternalAPI(url)
+	respondCountry(c, data, schemaVersion)
+}
+
+func GetCountryNeighbours(c *gin.Context) {
+	countryCode := c.Query("country-code")
+	if len(countryCode) != 2 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Must submit ISO 3166-1 Alpha-2 'country-code'",
+		})
+		return
+	}
+
+	data, err := getCountryData(countryCode, "")
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
@@ -114,8 +132,15 @@ func GetCountry(c *gin.Context) {
 		return
 	}
 
-	// Map Country Data
-	err = json.Unmarshal(data, &externalData)
+	profile, err := services.ParseCountryProfile(data)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	neighbours, err := services.GetNeighbours(profile)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
@@ -123,23 +148,67 @@ func GetCountry(c *gin.Context) {
 		return
 	}
 
-	expiry := time.Now().AddDate(1, 0, 0)
+	c.JSON(http.StatusOK, gin.H{
+		"country":    services.CountrySummary(profile),
+		"neighbours": neighbours,
+	})
+}
 
-	if countryData.Id > 0 && countryData.ExpiryDate.Before(time.Now()) {
-		query = database.NewQueryBuilder("UPDATE").Table("countries").Columns("data", "expiry_date").Where("id = ?").Build()
-		_, err = database.Execute(nil, query, externalData, expiry, countryData.Id)
-	} else {
-		query = database.NewQueryBuilder("INSERT").Table("countries").Columns("name", "iso_code", "data", "expiry_date").Values(4).Build()
-		_, err = database.Execute(nil, query, name, countryCode, externalData, time.Now().AddDate(1, 0, 0))
+// getCountryData - Cached Rest Countries data for a country (Fetched and cached if missing or expired)
+func getCountryData(countryCode string, name string) ([]byte, error) {
+	// Try to Retrieve Data from Server (IF nothing is returned retrieve using External API)
+	var countryData models.Country
+	query := database.NewQueryBuilder("SELECT").Table("countries").Where("iso_code = ?").Build()
+	_, err := database.Execute(&countryData, query, countryCode)
+	if err == nil && countryData.Id > 0 && countryData.ExpiryDate.After(time.Now()) {
+		return countryData.Data, nil
 	}
 
+	data, err := services.FetchCountry(countryCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := services.SaveCountry(name, data); err != nil {
+		return nil, fmt.Errorf("database error while saving country data")
+	}
+
+	return data, nil
+}
+
+// countrySchemaVersion - Reads the 'schema-version' query parameter (Defaults to the legacy schema, the profile is opt-in)
+func countrySchemaVersion(c *gin.Context) (int, error) {
+	version := c.Query("schema-version")
+	if version == "" {
+		return models.CountrySchemaLegacy, nil
+	}
+
+	parsed, err := strconv.Atoi(version)
+	if err != nil || parsed < models.CountrySchemaLegacy || parsed > models.CountrySchemaVersion {
+		return 0, fmt.Errorf("'schema-version' must be between %d and %d", models.CountrySchemaLegacy, models.CountrySchemaVersion)
+	}
+	return parsed, nil
+}
+
+// respondCountry - Sends country data in the requested schema version
+func respondCountry(c *gin.Context, data []byte, schemaVersion int) {
+	c.Header("X-Country-Schema-Version", strconv.Itoa(schemaVersion))
+
+	// Legacy clients receive the Rest Countries data untouched
+	if schemaVersion == models.CountrySchemaLegacy {
+		c.JSON(http.StatusOK, json.RawMessage(data))
+		return
+	}
+
+	profile, err := services.ParseCountryProfile(data)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Database error while saving weather data",
+			"error": err.Error(),
 		})
 		return
 	}
-	c.JSON(http.StatusOK, externalData[0])
+
+	c.JSON(http.StatusOK, profile)
 }
 
 func GetWeather(c *gin.Context) {
@@ -161,26 +230,26 @@ func GetWeather(c *gin.Context) {
 		return
 	}
 
+	// Existing clients keep the legacy shape, the normalised schema is opt-in (schema-version=2)
+	options, err := weatherOptions(c, models.WeatherSchemaLegacy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
 	// Try to retrieve data from the database
 	var cityWeather models.CityWeather
 	query := database.NewQueryBuilder("SELECT").Table("city_weather").Where("ABS(lat - ?) < 0.0001 AND ABS(lon - ?) < 0.0001").Build()
-	_, err := database.Execute(&cityWeather, query, lat, long)
+	_, err = database.Execute(&cityWeather, query, lat, long)
 	if err == nil && cityWeather.Id > 0 && cityWeather.ExpiryDate.After(time.Now()) {
-		c.JSON(http.StatusOK, cityWeather.Data)
-		return
-	}
-
-	// Build URL String for OpenWeatherAPI
-	url := fmt.Sprintf(config.Cfg.OpenWeatherAPI.URL, lat, long, config.Cfg.OpenWeatherAPI.Key)
-	if url == "" || !strings.HasPrefix(url, "https://") {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "'" + config.Cfg.OpenWeatherAPI.Name + "' URL is not set up properly",
-		})
+		respondWeather(c, cityWeather.Data, options)
 		return
 	}
 
-	// Fetch Data from API
-	data, err := services.FetchExternalAPI(url)
+	// Fetch Data from the Weather Provider (Falls back to the other provider on failure)
+	data, err := services.FetchWeather(lat, long)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
@@ -201,11 +270,15 @@ func GetWeather(c *gin.Context) {
 	expiry := time.Now().AddDate(0, 0, 1)
 
 	if cityWeather.Id > 0 {
+		services.SetCityTimezone(cityWeather.CityId, externalData.Timezone)
 		query = database.NewQueryBuilder("UPDATE").Table("city_weather").Columns("data", "expiry_date").Where("id = ?").Build()
 		_, err = database.Execute(nil, query, data, expiry, cityWeather.Id)
 	} else {
-		city, _ := services.GetOrCreateCity(city)
-		country, _ := services.GetCountry(country)
+		city, country, ok := resolveCity(c, city, country, lat, long)
+		if !ok {
+			return
+		}
+		services.SetCityTimezone(city.Id, externalData.Timezone)
 		query = database.NewQueryBuilder("INSERT").Table("city_weather").Columns("lat", "lon", "city_id", "country_id", "data", "expiry_date").Values(6).Build()
 		_, err = database.Execute(nil, query, lat, long, city.Id, country.Id, data, expiry)
 	}
@@ -217,7 +290,7 @@ func GetWeather(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, externalData)
+	respondWeather(c, data, options)
 }
 
 func GetTravelDestinations(c *gin.Context) {
@@ -239,27 +312,69 @@ func GetTravelDestinations(c *gin.Context) {
 		return
 	}
 
-	// Try to retrieve from DB
-	var citySights models.CitySights
-	query := database.NewQueryBuilder("SELECT").Table("city_sights").Where("ABS(lat - ?) < 0.0001 AND ABS(lon - ?) < 0.0001").Build()
+	// Optional Category Filter (Also chooses the OpenTripMap kinds when 'kinds' is not given)
+	categories, err := services.ParsePoiCategories(c.Query("category"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	kinds := c.Query("kinds")
+	if kinds == "" && len(categories) > 0 {
+		kinds = services.PoiCategoryKinds(categories)
+	}
 
-	_, err := database.Execute(&citySights, query, lat, long)
-	if err == nil && citySights.Id > 0 && !citySights.ExpiryDate.IsZero() && citySights.ExpiryDate.After(time.Now()) {
-		c.JSON(http.StatusOK, citySights.Data)
+	// Optional Search and Pagination Parameters
+	search, err := services.ParseSightsSearch(c.Query("radius"), kinds, c.Query("rate"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	page, err := services.ParseSightsPage(c.Query("sort"), c.Query("page"), c.Query("page-size"), c.Query("cursor"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
 		return
 	}
+	page.Categories = categories
 
-	// Build URL String for OpenTripAPI
-	url := fmt.Sprintf(config.Cfg.OpenTripAPI.URL, lat, long, config.Cfg.OpenTripAPI.Key)
-	if url == "" || !strings.HasPrefix(url, "https://") {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "'" + config.Cfg.OpenTripAPI.Name + "' URL is not setup properly",
+	// Distances are measured from the user's location (or a chosen anchor), defaulting to the search centre
+	centre, err := geo.ParsePoint(lat, long)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	anchor, err := anchorPoint(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
 		})
 		return
 	}
+	page.Anchor = centre
+	if anchor != nil {
+		page.Anchor = *anchor
+	}
+	searchKey := services.SightsSearchKey(search)
+
+	// Try to retrieve from DB (Each search combination has its own row)
+	var citySights models.CitySights
+	query := database.NewQueryBuilder("SELECT").Table("city_sights").Where("ABS(lat - ?) < 0.0001 AND ABS(lon - ?) < 0.0001 AND search_key = ?").Build()
+
+	_, err = database.Execute(&citySights, query, lat, long, searchKey)
+	if err == nil && citySights.Id > 0 && !citySights.ExpiryDate.IsZero() && citySights.ExpiryDate.After(time.Now()) {
+		respondSights(c, citySights.Data, page)
+		return
+	}
 
 	// Fetch Data from API
-	data, err := services.FetchExternalAPI(url)
+	data, err := services.FetchSights(lat, long, search)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
@@ -284,10 +399,12 @@ func GetTravelDestinations(c *gin.Context) {
 		query = database.NewQueryBuilder("UPDATE").Table("city_sights").Columns("data", "expiry_date").Where("id = ?").Build()
 		_, err = database.Execute(nil, query, data, expiry, citySights.Id)
 	} else {
-		city, _ := services.GetOrCreateCity(city)
-		country, _ := services.GetCountry(countryCode)
-		query = database.NewQueryBuilder("INSERT").Table("city_sights").Columns("lat", "lon", "city_id", "country_id", "data", "expiry_date").Values(6).Build()
-		_, err = database.Execute(nil, query, lat, long, city.Id, country.Id, data, expiry)
+		city, country, ok := resolveCity(c, city, countryCode, lat, long)
+		if !ok {
+			return
+		}
+		query = database.NewQueryBuilder("INSERT").Table("city_sights").Columns("lat", "lon", "city_id", "country_id", "search_key", "data", "expiry_date").Values(7).Build()
+		_, err = database.Execute(nil, query, lat, long, city.Id, country.Id, searchKey, data, expiry)
 	}
 
 	if err != nil {
@@ -297,7 +414,46 @@ func GetTravelDestinations(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, externalData)
+	respondSights(c, data, page)
+}
+
+// respondSights - Sends a single page of the cached sights
+func respondSights(c *gin.Context, data []byte, options models.SightsPageOptions) {
+	page, err := services.PaginateSights(data, options)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// Averages of our users' reviews next to OpenTripMap's rate
+	if err := services.AddSightRatings(page); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error occured querying database",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// anchorPoint - Reads the optional 'from-lat' and 'from-lon' query parameters (nil when not given)
+func anchorPoint(c *gin.Context) (*geo.Point, error) {
+	fromLat := c.Query("from-lat")
+	fromLon := c.Query("from-lon")
+	if fromLat == "" && fromLon == "" {
+		return nil, nil
+	}
+	if fromLat == "" || fromLon == "" {
+		return nil, fmt.Errorf("'from-lat' and 'from-lon' must be given together")
+	}
+
+	anchor, err := geo.ParsePoint(fromLat, fromLon)
+	if err != nil {
+		return nil, fmt.Errorf("invalid 'from-lat' or 'from-lon': %s", err)
+	}
+	return &anchor, nil
 }
 
 func GetTravelDestination(c *gin.Context) {
@@ -317,10 +473,18 @@ func GetTravelDestination(c *gin.Context) {
 		})
 	}
 
+	anchor, err := anchorPoint(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
 	// Try to retrieve from DB first
 	var poiData models.CityPoi
 	query := database.NewQueryBuilder("SELECT").Table("city_pois").Where("xid = ?").Build()
-	_, err := database.Execute(&poiData, query, xid)
+	_, err = database.Execute(&poiData, query, xid)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Internal Server Error (Try again later)",
@@ -330,7 +494,7 @@ func GetTravelDestination(c *gin.Context) {
 
 	// If data found and not expired, return it
 	if poiData.Id > 0 && poiData.ExpiryDate.After(time.Now()) {
-		c.JSON(http.StatusOK, poiData.Data)
+		respondPoi(c, poiData.Data, anchor)
 		return
 	}
 
@@ -368,8 +532,12 @@ func GetTravelDestination(c *gin.Context) {
 		query = database.NewQueryBuilder("UPDATE").Table("city_pois").Columns("data", "expiry_date").Where("id = ?").Build()
 		_, err = database.Execute(nil, query, data, expiry, poiData.Id)
 	} else {
-		city, _ := services.GetOrCreateCity(city)
-		country, _ := services.GetCountry(country)
+		lat := strconv.FormatFloat(externalData.Point.Lat, 'f', -1, 64)
+		lon := strconv.FormatFloat(externalData.Point.Lon, 'f', -1, 64)
+		city, country, ok := resolveCity(c, city, country, lat, lon)
+		if !ok {
+			return
+		}
 		query = database.NewQueryBuilder("INSERT").Table("city_pois").Columns("city_id", "country_id", "xid", "data", "expiry_date").Values(5).Build()
 		_, err = database.Execute(nil, query, city.Id, country.Id, xid, data, expiry)
 	}
@@ -381,5 +549,34 @@ func GetTravelDestination(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, externalData)
+	respondPoi(c, data, anchor)
+}
+
+// respondPoi - Sends a POI with our category ids (And the leg from the anchor point if given)
+func respondPoi(c *gin.Context, data []byte, anchor *geo.Point) {
+	var poi models.PoiDetails
+	if err := json.Unmarshal(data, &poi.OpenTripPlaceRequest); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	poi.Categories = services.CategoriesForKinds(poi.Kinds)
+	if anchor != nil {
+		leg := geo.NewLeg(*anchor, geo.Point{Lat: poi.Point.Lat, Lon: poi.Point.Lon})
+		poi.Leg = &leg
+	}
+
+	ratings, err := services.PoiRatings([]string{poi.XID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error occured querying database",
+		})
+		return
+	}
+	if rating, ok := ratings[poi.XID]; ok {
+		poi.UserRating = &rating
+	}
+
+	c.JSON(http.StatusOK, poi)
 }
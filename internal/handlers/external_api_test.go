@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MCantyDev/city-explorer-server/internal/config"
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+	"github.com/MCantyDev/city-explorer-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// replayRouter - Serves the handler with External APIs replayed from the committed fixtures (No network access)
+func replayRouter(t *testing.T, path string, handler gin.HandlerFunc) *gin.Engine {
+	t.Helper()
+
+	previous := config.Cfg
+	config.Cfg = &config.Config{
+		Fixtures: config.FixturesConfig{
+			Mode: config.FixtureModeReplay,
+			Dir:  "../fixtures",
+		},
+		PhotonAPI: config.ExternalAPI{
+			Name: "Photon API",
+			URL:  "https://photon.komoot.io/api/?q=%s&lang=%s&limit=%d",
+		},
+	}
+	restore := services.SetupExternalClient()
+	t.Cleanup(func() {
+		restore()
+		config.Cfg = previous
+	})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET(path, handler)
+	return router
+}
+
+func TestGetCitiesReplay(t *testing.T) {
+	router := replayRouter(t, "/get-cities", GetCities)
+
+	tests := []struct {
+		name      string
+		query     string
+		status    int
+		countries []string
+		error     string
+	}{
+		{name: "populated places only", query: "city=Paris&limit=2", status: http.StatusOK, countries: []string{"FR", "US"}},
+		{name: "invalid limit", query: "city=Paris&limit=0", status: http.StatusBadRequest, error: "'limit' must be between 1 and 50"},
+		{name: "no fixture recorded", query: "city=Nowhere", status: http.StatusBadRequest, error: "no fixture recorded"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/get-cities?"+test.query, nil))
+
+			if recorder.Code != test.status {
+				t.Fatalf("status = %d, want %d (%s)", recorder.Code, test.status, recorder.Body.String())
+			}
+
+			var body struct {
+				Results []models.Place `json:"results"`
+				Error   string         `json:"error"`
+			}
+			if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			if !strings.Contains(body.Error, test.error) {
+				t.Errorf("error = %q, want %q", body.Error, test.error)
+			}
+
+			if len(body.Results) != len(test.countries) {
+				t.Fatalf("results = %d, want %d", len(body.Results), len(test.countries))
+			}
+			for i, place := range body.Results {
+				if place.Name != "Paris" || place.CountryCode != test.countries[i] {
+					t.Errorf("results[%d] = %s, %s, want Paris, %s", i, place.Name, place.CountryCode, test.countries[i])
+				}
+			}
+		})
+	}
+}
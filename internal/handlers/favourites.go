@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/MCantyDev/city-explorer-server/internal/geo"
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+	"github.com/MCantyDev/city-explorer-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+func GetFavourites(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+
+	kind := c.Query("kind")
+	if kind != "" && !services.IsFavouriteKind(kind) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "'kind' must be 'city', 'poi' or 'place'",
+		})
+		return
+	}
+
+	favourites, err := services.ListFavourites(userId, kind, c.Query("tag"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error occured querying database",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"results": favourites,
+	})
+}
+
+func GetFavourite(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	favourite, err := services.GetFavourite(userId, id)
+	if err != nil {
+		respondFavouriteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, favourite)
+}
+
+func AddFavourite(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+
+	var req models.FavouriteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "'kind' must be 'city', 'poi' or 'place'",
+		})
+		return
+	}
+
+	tags, err := services.NormaliseTags(req.Tags)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	notes := strings.TrimSpace(req.Notes)
+
+	favourite := models.Favourite{Kind: req.Kind}
+
+	switch req.Kind {
+	case models.FavouriteCity:
+		if req.City == "" || len(req.CountryCode) != 2 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Cities require 'city' and an ISO 3166-1 Alpha-2 'country_code'",
+			})
+			return
+		}
+		if _, err := geo.ParsePoint(req.Lat, req.Lon); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		city, _, ok := resolveCity(c, req.City, strings.ToUpper(req.CountryCode), req.Lat, req.Lon)
+		if !ok {
+			return
+		}
+		favourite.CityId = &city.Id
+		if name == "" {
+			name = city.Name
+		}
+
+	case models.FavouritePoi:
+		if req.Xid == "" || len(req.Xid) > 32 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "POIs require an 'xid'",
+			})
+			return
+		}
+		favourite.Xid = &req.Xid
+
+		// The POI's city and name come from the cache when it has been viewed
+		if poi := services.GetCachedPoi(req.Xid); poi != nil {
+			favourite.CityId = &poi.CityId
+			if name == "" {
+				var place models.OpenTripPlaceRequest
+				if err := json.Unmarshal(poi.Data, &place); err == nil {
+					name = place.Name
+				}
+			}
+		}
+
+	case models.FavouritePlace:
+		point, err := geo.ParsePoint(req.Lat, req.Lon)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		if name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Places require a 'name', 'lat' and 'lon'",
+			})
+			return
+		}
+		favourite.Lat, favourite.Lon = &point.Lat, &point.Lon
+	}
+
+	if err := services.ValidateFavouriteText(name, notes); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	favourite.Name = name
+	if notes != "" {
+		favourite.Notes = &notes
+	}
+
+	view, err := services.CreateFavourite(userId, favourite, tags)
+	if err != nil {
+		respondFavouriteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, view)
+}
+
+func EditFavourite(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.FavouriteUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Could not bind data to model in server",
+		})
+		return
+	}
+
+	favourite, err := services.UpdateFavourite(userId, id, req)
+	if err != nil {
+		respondFavouriteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, favourite)
+}
+
+func DeleteFavourite(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := services.DeleteFavourite(userId, id); err != nil {
+		respondFavouriteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error": nil,
+	})
+}
+
+func respondFavouriteError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrFavouriteNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, services.ErrFavouriteExists):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, services.ErrInvalidFavourite):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Database error while saving favourite",
+		})
+	}
+}
+
+// currentUserId - The authenticated user's ID (Set by SessionAuthMiddleware)
+func currentUserId(c *gin.Context) (uint, bool) {
+	value, exists := c.Get("userId")
+	if exists {
+		if id, err := strconv.ParseUint(value.(string), 10, 64); err == nil {
+			return uint(id), true
+		}
+	}
+
+	c.JSON(http.StatusUnauthorized, gin.H{
+		"error": "User ID missing in context",
+	})
+	return 0, false
+}
+
+// idParam - Parses an id path parameter (e.g. ':id')
+func idParam(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Invalid '%s'", name),
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/MCantyDev/city-explorer-server/internal/database"
+	"github.com/MCantyDev/city-explorer-server/internal/geo"
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+	"github.com/MCantyDev/city-explorer-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+func ReverseGeocode(c *gin.Context) {
+	lat := c.Query("lat")
+	lon := c.Query("lon")
+	if lat == "" || lon == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Missing 'lat' or 'lon' query parameter",
+		})
+		return
+	}
+
+	point, err := geo.ParsePoint(lat, lon)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	snapped := geo.Snap(point, services.ReverseGeocodeSnapDecimals)
+
+	// Try to retrieve data from the database
+	var cached models.ReverseGeocode
+	query := database.NewQueryBuilder("SELECT").Table("reverse_geocodes").Where("ABS(lat - ?) < 0.0001 AND ABS(lon - ?) < 0.0001").Build()
+	_, err = database.Execute(&cached, query, snapped.Lat, snapped.Lon)
+	if err == nil && cached.Id > 0 && cached.ExpiryDate.After(time.Now()) {
+		c.JSON(http.StatusOK, cached.Data)
+		return
+	}
+
+	// Fetch Data from the Geocoder
+	place, err := services.ReverseGeocode(snapped)
+	if errors.Is(err, services.ErrPlaceNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	data, err := json.Marshal(place)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// City boundaries rarely change
+	expiry := time.Now().AddDate(0, 1, 0)
+
+	if cached.Id > 0 {
+		query = database.NewQueryBuilder("UPDATE").Table("reverse_geocodes").Columns("data", "expiry_date").Where("id = ?").Build()
+		_, err = database.Execute(nil, query, data, expiry, cached.Id)
+	} else {
+		query = database.NewQueryBuilder("INSERT").Table("reverse_geocodes").Columns("lat", "lon", "data", "expiry_date").Values(4).Build()
+		_, err = database.Execute(nil, query, snapped.Lat, snapped.Lon, data, expiry)
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Database error while saving reverse geocode",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, place)
+}
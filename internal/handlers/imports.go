@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/MCantyDev/city-explorer-server/internal/importer"
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+	"github.com/MCantyDev/city-explorer-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ImportPlaces - Reads a GPX, KML, KMZ or GeoJSON upload ('file') and matches each place to a cached POI
+// (Optional 'target' of 'favourites' with 'tags', or 'trip' with 'trip_id', 'day' and 'time_slot' saves the results)
+func ImportPlaces(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+
+	// Leave room for the other form fields
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, importer.MaxFileSize+64<<10)
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondImportTooLarge(c)
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Missing 'file' upload (multipart/form-data)",
+		})
+		return
+	}
+	defer file.Close()
+
+	if header.Size > importer.MaxFileSize {
+		respondImportTooLarge(c)
+		return
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Could not read the uploaded file",
+		})
+		return
+	}
+
+	target := c.PostForm("target")
+	if target != "" && target != models.ImportTargetFavourites && target != models.ImportTargetTrip {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "'target' must be 'favourites' or 'trip' (Leave it empty to preview the matches)",
+		})
+		return
+	}
+
+	radius, err := services.ParseImportRadius(c.PostForm("radius"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	result, err := importer.Parse(header.Filename, data)
+	if errors.Is(err, importer.ErrTooManyPlaces) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	report, err := services.MatchImportedPlaces(result, radius)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error occured querying database",
+		})
+		return
+	}
+
+	switch target {
+	case models.ImportTargetFavourites:
+		var tags []string
+		if value := c.PostForm("tags"); value != "" {
+			tags = strings.Split(value, ",")
+		}
+		normalised, err := services.NormaliseTags(tags)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		services.SaveImportToFavourites(userId, report, normalised)
+
+	case models.ImportTargetTrip:
+		tripId, err := strconv.ParseUint(c.PostForm("trip_id"), 10, 64)
+		if err != nil || tripId == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Importing to a trip requires a 'trip_id'",
+			})
+			return
+		}
+		day := 1
+		if value := c.PostForm("day"); value != "" {
+			if day, err = strconv.Atoi(value); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": "Invalid 'day'",
+				})
+				return
+			}
+		}
+
+		version, ok := ifMatchVersion(c)
+		if !ok {
+			return
+		}
+
+		if err := services.SaveImportToTrip(userId, uint(tripId), version, day, c.PostForm("time_slot"), report); err != nil {
+			respondTripError(c, err)
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func respondImportTooLarge(c *gin.Context) {
+	c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+		"error": fmt.Sprintf("Files must be at most %d MB", importer.MaxFileSize>>20),
+	})
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/MCantyDev/city-explorer-server/internal/geo"
+	"github.com/MCantyDev/city-explorer-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// SuggestItinerary - Day by day plan from the city's cached sights (Optional 'days', 'per-day', 'category' and 'start-date')
+func SuggestItinerary(c *gin.Context) {
+	lat := c.Query("lat")
+	long := c.Query("long")
+	if lat == "" || long == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Missing 'lat' or 'long' query parameter",
+		})
+		return
+	}
+
+	name := c.Query("city")
+	countryCode := c.Query("country-code")
+	if name == "" || countryCode == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Missing 'city' or 'country-code' query parameter",
+		})
+		return
+	}
+
+	centre, err := geo.ParsePoint(lat, long)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	options, err := services.ParseItineraryOptions(c.Query("days"), c.Query("per-day"), c.Query("category"), c.Query("start-date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	options.Centre = centre
+
+	city, _, ok := resolveCity(c, name, countryCode, lat, long)
+	if !ok {
+		return
+	}
+
+	itinerary, err := services.SuggestItinerary(city, options)
+	if errors.Is(err, services.ErrNoCachedSights) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error occured querying database",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, itinerary)
+}
@@ -0,0 +1,15 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/MCantyDev/city-explorer-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// GetPoiCategories - Sends the POI category tree (Ids are used by the 'category' filter on the sights endpoint)
+func GetPoiCategories(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"categories": services.PoiCategories(),
+	})
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+	"github.com/MCantyDev/city-explorer-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+func GetPoiReviews(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+
+	xid := c.Query("xid")
+	if xid == "" || len(xid) > 32 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Missing 'xid' query parameter",
+		})
+		return
+	}
+	page, pageSize, err := services.ParseReviewsPage(c.Query("page"), c.Query("page-size"))
+	if err != nil {
+		respondReviewError(c, err)
+		return
+	}
+
+	reviews, err := services.ListPoiReviews(userId, xid, page, pageSize)
+	if err != nil {
+		respondReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+func AddPoiReview(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+
+	var req models.PoiReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Reviews require an 'xid' and a 'rating' between 1 and 5 (optional 'review' text)",
+		})
+		return
+	}
+
+	review, err := services.CreatePoiReview(userId, req.Xid, req.Rating, req.Review)
+	if err != nil {
+		respondReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, review)
+}
+
+func EditPoiReview(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.PoiReviewUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Optional 'rating' must be between 1 and 5 and 'review' a string",
+		})
+		return
+	}
+
+	review, err := services.UpdatePoiReview(userId, id, req.Rating, req.Review)
+	if err != nil {
+		respondReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+func DeletePoiReview(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := services.DeletePoiReview(userId, id); err != nil {
+		respondReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error": nil,
+	})
+}
+
+func ReportPoiReview(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	// The reason is optional, so an empty body is allowed
+	var req models.PoiReviewReport
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Optional 'reason' must be a string",
+			})
+			return
+		}
+	}
+
+	if err := services.ReportPoiReview(userId, id, req.Reason); err != nil {
+		respondReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error": nil,
+	})
+}
+
+func respondReviewError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrReviewNotFound), errors.Is(err, services.ErrReviewPoiNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, services.ErrReviewExists):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, services.ErrReviewLimit):
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, services.ErrInvalidReview):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Database error while saving review",
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+	"github.com/MCantyDev/city-explorer-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+func GetTripShares(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	shares, err := services.ListTripShares(userId, id)
+	if err != nil {
+		respondShareError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"results": shares,
+	})
+}
+
+func AddTripShare(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	// An empty body shares the trip with no expiry or password
+	var req models.TripShareRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Optional 'expires_at' must be an RFC 3339 time and 'password' a string",
+			})
+			return
+		}
+	}
+
+	share, err := services.CreateTripShare(userId, id, req.ExpiresAt, req.Password)
+	if err != nil {
+		respondShareError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, share)
+}
+
+func DeleteTripShare(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+	shareId, ok := idParam(c, "shareId")
+	if !ok {
+		return
+	}
+
+	if err := services.RevokeTripShare(userId, id, shareId); err != nil {
+		respondShareError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error": nil,
+	})
+}
+
+// GetSharedTrip - Public read-only trip behind a share link (Password in the 'X-Share-Password' header)
+func GetSharedTrip(c *gin.Context) {
+	// Links must not be cached by proxies or indexed
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("X-Robots-Tag", "noindex, nofollow")
+
+	trip, err := services.GetSharedTrip(c.Param("token"), c.GetHeader("X-Share-Password"))
+	if err != nil {
+		respondShareError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, trip)
+}
+
+func respondShareError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrShareNotFound), errors.Is(err, services.ErrTripNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, services.ErrTripForbidden):
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, services.ErrShareExpired):
+		c.JSON(http.StatusGone, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, services.ErrSharePasswordRequired), errors.Is(err, services.ErrSharePasswordWrong):
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":             err.Error(),
+			"password_required": true,
+		})
+	case errors.Is(err, services.ErrInvalidShare):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Database error while loading share link",
+		})
+	}
+}
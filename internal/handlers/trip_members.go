@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+	"github.com/MCantyDev/city-explorer-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+func GetTripMembers(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	members, err := services.ListTripMembers(userId, id)
+	if err != nil {
+		respondTripError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"results": members,
+	})
+}
+
+func AddTripMember(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.TripInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invitations require a 'user' (username or email) and a 'role' ('editor' or 'viewer')",
+		})
+		return
+	}
+
+	member, err := services.InviteTripMember(userId, id, req.User, req.Role)
+	if err != nil {
+		respondTripError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, member)
+}
+
+func EditTripMember(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+	memberId, ok := idParam(c, "userId")
+	if !ok {
+		return
+	}
+
+	var req models.TripMemberUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Must submit a 'role' ('editor' or 'viewer')",
+		})
+		return
+	}
+
+	member, err := services.UpdateTripMember(userId, id, memberId, req.Role)
+	if err != nil {
+		respondTripError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+func DeleteTripMember(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+	memberId, ok := idParam(c, "userId")
+	if !ok {
+		return
+	}
+
+	if err := services.RemoveTripMember(userId, id, memberId); err != nil {
+		respondTripError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error": nil,
+	})
+}
+
+func GetTripInvitations(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+
+	invitations, err := services.ListTripInvitations(userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error occured querying database",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"results": invitations,
+	})
+}
+
+func AcceptTripInvitation(c *gin.Context) {
+	respondTripInvitation(c, true)
+}
+
+func DeclineTripInvitation(c *gin.Context) {
+	respondTripInvitation(c, false)
+}
+
+// respondTripInvitation - Accepts or declines the invitation to the trip in the path
+func respondTripInvitation(c *gin.Context, accept bool) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := services.RespondTripInvitation(userId, id, accept); err != nil {
+		respondTripError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error": nil,
+	})
+}
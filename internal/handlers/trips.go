@@ -0,0 +1,380 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+	"github.com/MCantyDev/city-explorer-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+func GetTrips(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+
+	trips, err := services.ListTrips(userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error occured querying database",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"results": trips,
+	})
+}
+
+func GetTrip(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	trip, err := services.GetTrip(userId, id)
+	if err != nil {
+		respondTripError(c, err)
+		return
+	}
+
+	respondTrip(c, http.StatusOK, trip)
+}
+
+func AddTrip(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+
+	var req models.TripRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Trips require 'name', 'start_date' and 'end_date' (Cities require 'city', 'country_code', 'lat' and 'lon')",
+		})
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	notes := strings.TrimSpace(req.Notes)
+	if err := services.ValidateTripText(name, notes); err != nil {
+		respondTripError(c, err)
+		return
+	}
+
+	start, end, err := services.ParseTripDates(req.StartDate, req.EndDate)
+	if err != nil {
+		respondTripError(c, err)
+		return
+	}
+
+	cityIds, ok := resolveTripCities(c, req.Cities)
+	if !ok {
+		return
+	}
+
+	trip, err := services.CreateTrip(userId, name, start, end, notes, cityIds)
+	if err != nil {
+		respondTripError(c, err)
+		return
+	}
+
+	respondTrip(c, http.StatusCreated, trip)
+}
+
+func EditTrip(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req models.TripUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Could not bind data to model in server",
+		})
+		return
+	}
+
+	var cityIds *[]uint
+	if req.Cities != nil {
+		ids, ok := resolveTripCities(c, *req.Cities)
+		if !ok {
+			return
+		}
+		cityIds = &ids
+	}
+
+	trip, err := services.UpdateTrip(userId, id, version, req.Name, req.StartDate, req.EndDate, req.Notes, cityIds)
+	if err != nil {
+		respondTripError(c, err)
+		return
+	}
+
+	respondTrip(c, http.StatusOK, trip)
+}
+
+func DeleteTrip(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	if err := services.DeleteTrip(userId, id, version); err != nil {
+		respondTripError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error": nil,
+	})
+}
+
+func AddTripItem(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req models.TripItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Items require 'kind' ('poi' or 'custom') and 'day' (1 is the start date)",
+		})
+		return
+	}
+
+	item := models.TripItem{
+		Day:      req.Day,
+		TimeSlot: req.TimeSlot,
+		Kind:     req.Kind,
+		CityId:   req.CityId,
+		Name:     strings.TrimSpace(req.Name),
+		Lat:      req.Lat,
+		Lon:      req.Lon,
+	}
+	if notes := strings.TrimSpace(req.Notes); notes != "" {
+		item.Notes = &notes
+	}
+
+	if req.Kind == models.TripItemPoi {
+		if req.Xid == "" || len(req.Xid) > 32 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "POIs require an 'xid'",
+			})
+			return
+		}
+		item.Xid = &req.Xid
+	}
+
+	trip, err := services.AddTripItem(userId, id, version, item)
+	if err != nil {
+		respondTripError(c, err)
+		return
+	}
+
+	respondTrip(c, http.StatusCreated, trip)
+}
+
+func EditTripItem(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+	itemId, ok := idParam(c, "itemId")
+	if !ok {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req models.TripItemUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Could not bind data to model in server",
+		})
+		return
+	}
+
+	trip, err := services.UpdateTripItem(userId, id, version, itemId, req)
+	if err != nil {
+		respondTripError(c, err)
+		return
+	}
+
+	respondTrip(c, http.StatusOK, trip)
+}
+
+func DeleteTripItem(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+	itemId, ok := idParam(c, "itemId")
+	if !ok {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	trip, err := services.DeleteTripItem(userId, id, version, itemId)
+	if err != nil {
+		respondTripError(c, err)
+		return
+	}
+
+	respondTrip(c, http.StatusOK, trip)
+}
+
+func ReorderTripItems(c *gin.Context) {
+	userId, ok := currentUserId(c)
+	if !ok {
+		return
+	}
+	id, ok := idParam(c, "id")
+	if !ok {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req models.TripOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Must submit 'items' with the 'id' and 'day' of every item in order",
+		})
+		return
+	}
+
+	trip, err := services.ReorderTripItems(userId, id, version, req.Items)
+	if err != nil {
+		respondTripError(c, err)
+		return
+	}
+
+	respondTrip(c, http.StatusOK, trip)
+}
+
+// resolveTripCities - Canonical city ids for the requested cities (In the same order)
+func resolveTripCities(c *gin.Context, cities []models.TripCityRequest) ([]uint, bool) {
+	ids := make([]uint, 0, len(cities))
+	for _, request := range cities {
+		city, _, ok := resolveCity(c, request.City, strings.ToUpper(request.CountryCode), request.Lat, request.Lon)
+		if !ok {
+			return nil, false
+		}
+		ids = append(ids, city.Id)
+	}
+	return ids, true
+}
+
+// ifMatchVersion - The trip version from the 'If-Match' header (nil when it is missing or '*')
+func ifMatchVersion(c *gin.Context) (*int, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return nil, true
+	}
+
+	version, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(header, "W/"), `"`))
+	if err != nil || version < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "'If-Match' must be the ETag (or version) of the trip",
+		})
+		return nil, false
+	}
+	return &version, true
+}
+
+// respondTrip - Sends a trip with its version as the ETag (Weak, the forecasts change without a new version)
+func respondTrip(c *gin.Context, status int, trip *models.TripView) {
+	c.Header("ETag", fmt.Sprintf(`W/"%d"`, trip.Version))
+	c.JSON(status, trip)
+}
+
+func respondTripError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrTripNotFound), errors.Is(err, services.ErrTripItemNotFound),
+		errors.Is(err, services.ErrTripMemberNotFound), errors.Is(err, services.ErrTripUserNotFound),
+		errors.Is(err, services.ErrTripInvitationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, services.ErrInvalidTrip), errors.Is(err, services.ErrInvalidTripMember):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, services.ErrTripForbidden):
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, services.ErrTripMemberExists):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, services.ErrTripConflict):
+		c.JSON(http.StatusPreconditionFailed, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, services.ErrTripVersionRequired):
+		c.JSON(http.StatusPreconditionRequired, gin.H{
+			"error": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Database error while saving trip",
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MCantyDev/city-explorer-server/internal/database"
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+	"github.com/MCantyDev/city-explorer-server/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// Short lived Forecasts (Hourly, Minutely, Alerts) - Each has its own cache table and TTL
+type weatherForecast struct {
+	Kind      string        // services.Forecast*
+	Table     string        // Cache Table
+	TTL       time.Duration // How long a cached row is valid for
+	Normalise func(data []byte, options models.WeatherOptions) (any, error)
+}
+
+var hourlyForecast = weatherForecast{
+	Kind:  services.ForecastHourly,
+	Table: "city_weather_hourly",
+	TTL:   60 * time.Minute,
+	Normalise: func(data []byte, options models.WeatherOptions) (any, error) {
+		return services.NormaliseHourly(data, options)
+	},
+}
+
+var minutelyForecast = weatherForecast{
+	Kind:  services.ForecastMinutely,
+	Table: "city_weather_minutely",
+	TTL:   10 * time.Minute,
+	Normalise: func(data []byte, options models.WeatherOptions) (any, error) {
+		return services.NormaliseMinutely(data, options)
+	},
+}
+
+var alertsForecast = weatherForecast{
+	Kind:  services.ForecastAlerts,
+	Table: "city_weather_alerts",
+	TTL:   15 * time.Minute,
+	Normalise: func(data []byte, options models.WeatherOptions) (any, error) {
+		return services.NormaliseAlerts(data, options)
+	},
+}
+
+func GetHourlyWeather(c *gin.Context) {
+	getForecast(c, hourlyForecast)
+}
+
+func GetMinutelyWeather(c *gin.Context) {
+	getForecast(c, minutelyForecast)
+}
+
+func GetWeatherAlerts(c *gin.Context) {
+	getForecast(c, alertsForecast)
+}
+
+// getForecast - Same flow as GetWeather (Check cache -> Fetch from Provider -> Save to cache) using the forecast's table and TTL
+func getForecast(c *gin.Context, forecast weatherForecast) {
+	lat := c.Query("lat")
+	long := c.Query("long")
+	if lat == "" || long == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Missing 'lat' or 'long' query parameter",
+		})
+		return
+	}
+
+	city := c.Query("city")
+	countryCode := c.Query("country-code")
+	if city == "" || countryCode == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Missing 'city' or 'country-code' query parameter",
+		})
+		return
+	}
+
+	// Forecast endpoints were added with the normalised schema, so it is their default
+	options, err := weatherOptions(c, models.WeatherSchemaVersion)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// Try to retrieve data from the database
+	var cached models.CityWeather
+	query := database.NewQueryBuilder("SELECT").Table(forecast.Table).Where("ABS(lat - ?) < 0.0001 AND ABS(lon - ?) < 0.0001").Build()
+	_, err = database.Execute(&cached, query, lat, long)
+	if err == nil && cached.Id > 0 && cached.ExpiryDate.After(time.Now()) {
+		respondForecast(c, cached.Data, options, forecast.Normalise)
+		return
+	}
+
+	// Fetch Data from the Weather Provider (Falls back to the other provider on failure)
+	data, err := services.FetchForecast(forecast.Kind, lat, long)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// Validate before saving
+	if _, err := forecast.Normalise(data, options); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	expiry := time.Now().Add(forecast.TTL)
+
+	if cached.Id > 0 {
+		query = database.NewQueryBuilder("UPDATE").Table(forecast.Table).Columns("data", "expiry_date").Where("id = ?").Build()
+		_, err = database.Execute(nil, query, data, expiry, cached.Id)
+	} else {
+		city, country, ok := resolveCity(c, city, countryCode, lat, long)
+		if !ok {
+			return
+		}
+		query = database.NewQueryBuilder("INSERT").Table(forecast.Table).Columns("lat", "lon", "city_id", "country_id", "data", "expiry_date").Values(6).Build()
+		_, err = database.Execute(nil, query, lat, long, city.Id, country.Id, data, expiry)
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Database error while saving " + forecast.Kind + " weather data",
+		})
+		return
+	}
+
+	respondForecast(c, data, options, forecast.Normalise)
+}
+
+// weatherOptions - Reads the 'units', 'lang' and 'schema-version' query parameters (Defaults to metric, en and the endpoint's default schema)
+func weatherOptions(c *gin.Context, schemaVersion int) (models.WeatherOptions, error) {
+	options := models.WeatherOptions{
+		SchemaVersion: schemaVersion,
+		Units:         c.DefaultQuery("units", models.UnitsMetric),
+		Lang:          strings.ToLower(c.DefaultQuery("lang", "en")),
+	}
+
+	if version := c.Query("schema-version"); version != "" {
+		parsed, err := strconv.Atoi(version)
+		if err != nil || parsed < models.WeatherSchemaLegacy || parsed > models.WeatherSchemaVersion {
+			return options, fmt.Errorf("'schema-version' must be between %d and %d", models.WeatherSchemaLegacy, models.WeatherSchemaVersion)
+		}
+		options.SchemaVersion = parsed
+	}
+
+	if options.Units != models.UnitsMetric && options.Units != models.UnitsImperial {
+		return options, fmt.Errorf("'units' must be '%s' or '%s'", models.UnitsMetric, models.UnitsImperial)
+	}
+
+	if !services.IsWeatherLanguage(options.Lang) {
+		return options, fmt.Errorf("'lang' must be one of: %s", strings.Join(services.WeatherLanguages, ", "))
+	}
+
+	return options, nil
+}
+
+// respondWeather - Sends current/daily weather data in the requested schema version
+func respondWeather(c *gin.Context, data []byte, options models.WeatherOptions) {
+	respondForecast(c, data, options, func(data []byte, options models.WeatherOptions) (any, error) {
+		return services.NormaliseWeather(data, options)
+	})
+}
+
+// respondForecast - Sends weather data in the requested schema version
+func respondForecast(c *gin.Context, data []byte, options models.WeatherOptions, normalise func([]byte, models.WeatherOptions) (any, error)) {
+	c.Header("X-Weather-Schema-Version", strconv.Itoa(options.SchemaVersion))
+
+	// Legacy clients receive the provider data untouched
+	if options.SchemaVersion == models.WeatherSchemaLegacy {
+		c.JSON(http.StatusOK, json.RawMessage(data))
+		return
+	}
+
+	weather, err := normalise(data, options)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, weather)
+}
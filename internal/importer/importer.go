@@ -0,0 +1,354 @@
+package importer
+
+/* Importer
+
+- Reads the places in GPX (waypoints and route points), KML / KMZ (Point placemarks) and GeoJSON (Point features) files
+- Pure functions, matching the places to POIs and saving them is done by the services
+- Each place that cannot be read is reported as a problem instead of failing the whole file
+*/
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	MaxFileSize   = 2 << 20 // Bytes uploaded
+	MaxKMLSize    = 8 << 20 // Bytes of KML inside a KMZ archive
+	MaxPlaces     = 500     // Places (and problems) in one file
+	MaxNameLength = 255
+	MaxNotes      = 2000
+)
+
+const (
+	FormatGPX     = "gpx"
+	FormatKML     = "kml"
+	FormatKMZ     = "kmz"
+	FormatGeoJSON = "geojson"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unsupported file, upload a .gpx, .kml, .kmz or .geojson file")
+	ErrTooManyPlaces = fmt.Errorf("files can contain at most %d places", MaxPlaces)
+)
+
+// Place - A point read from the file
+type Place struct {
+	Index       int // Position in the file (0 based, problems included)
+	Name        string
+	Description string
+	Lat         float64
+	Lon         float64
+}
+
+// Problem - An entry of the file that could not be read
+type Problem struct {
+	Index int
+	Name  string
+	Error string
+}
+
+type Result struct {
+	Format   string
+	Places   []Place
+	Problems []Problem
+
+	seen map[Place]bool // Exact repeats (e.g. GPX route points of a waypoint) are read once
+}
+
+// Parse - Reads the places from an uploaded file (The format comes from the extension, otherwise the content)
+func Parse(filename string, data []byte) (*Result, error) {
+	format := detectFormat(filename, data)
+	result := &Result{Format: format, seen: map[Place]bool{}}
+
+	var err error
+	switch format {
+	case FormatGPX:
+		err = parseGPX(data, result)
+	case FormatKML:
+		err = parseKML(data, result)
+	case FormatKMZ:
+		err = parseKMZ(data, result)
+	case FormatGeoJSON:
+		err = parseGeoJSON(data, result)
+	default:
+		return nil, ErrUnknownFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func detectFormat(filename string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".gpx":
+		return FormatGPX
+	case ".kml":
+		return FormatKML
+	case ".kmz":
+		return FormatKMZ
+	case ".geojson", ".json":
+		return FormatGeoJSON
+	}
+
+	content := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(content, []byte("PK")):
+		return FormatKMZ
+	case bytes.HasPrefix(content, []byte("{")):
+		return FormatGeoJSON
+	case bytes.Contains(content[:min(len(content), 1024)], []byte("<gpx")):
+		return FormatGPX
+	case bytes.Contains(content[:min(len(content), 1024)], []byte("<kml")):
+		return FormatKML
+	}
+	return ""
+}
+
+// add - Validates and appends a place (Invalid places become problems)
+func (r *Result) add(name string, description string, lat float64, lon float64, err error) error {
+	name = truncate(strings.Join(strings.Fields(name), " "), MaxNameLength)
+	if err == nil {
+		key := Place{Name: name, Lat: lat, Lon: lon}
+		if r.seen[key] {
+			return nil
+		}
+		r.seen[key] = true
+	}
+
+	index := len(r.Places) + len(r.Problems)
+	if index >= MaxPlaces {
+		return ErrTooManyPlaces
+	}
+
+	if err == nil && (lat < -90 || lat > 90 || lon < -180 || lon > 180) {
+		err = fmt.Errorf("coordinates out of range")
+	}
+	if err != nil {
+		r.Problems = append(r.Problems, Problem{Index: index, Name: name, Error: err.Error()})
+		return nil
+	}
+
+	if name == "" {
+		name = fmt.Sprintf("Place %d", index+1)
+	}
+	r.Places = append(r.Places, Place{
+		Index:       index,
+		Name:        name,
+		Description: truncate(strings.TrimSpace(description), MaxNotes),
+		Lat:         lat,
+		Lon:         lon,
+	})
+	return nil
+}
+
+// GPX - <wpt> and <rtept> elements (Tracks are recordings rather than places, so they are ignored)
+func parseGPX(data []byte, result *Result) error {
+	type point struct {
+		Lat         string `xml:"lat,attr"`
+		Lon         string `xml:"lon,attr"`
+		Name        string `xml:"name"`
+		Description string `xml:"desc"`
+		Comment     string `xml:"cmt"`
+	}
+
+	return decodeElements(data, []string{"wpt", "rtept"}, func(decoder *xml.Decoder, start xml.StartElement) error {
+		var p point
+		if err := decoder.DecodeElement(&p, &start); err != nil {
+			return fmt.Errorf("invalid GPX: %s", err)
+		}
+		description := p.Description
+		if description == "" {
+			description = p.Comment
+		}
+		lat, lon, err := parseCoordinates(p.Lat, p.Lon)
+		return result.add(p.Name, description, lat, lon, err)
+	})
+}
+
+// KML - <Placemark> elements with a <Point> (Anywhere in the document, folders included)
+func parseKML(data []byte, result *Result) error {
+	type placemark struct {
+		Name        string `xml:"name"`
+		Description string `xml:"description"`
+		Point       *struct {
+			Coordinates string `xml:"coordinates"`
+		} `xml:"Point"`
+	}
+
+	return decodeElements(data, []string{"Placemark"}, func(decoder *xml.Decoder, start xml.StartElement) error {
+		var p placemark
+		if err := decoder.DecodeElement(&p, &start); err != nil {
+			return fmt.Errorf("invalid KML: %s", err)
+		}
+		if p.Point == nil {
+			return result.add(p.Name, "", 0, 0, fmt.Errorf("only Point placemarks can be imported"))
+		}
+
+		// lon,lat[,altitude]
+		parts := strings.Split(strings.TrimSpace(p.Point.Coordinates), ",")
+		if len(parts) < 2 {
+			return result.add(p.Name, "", 0, 0, fmt.Errorf("invalid coordinates '%s'", p.Point.Coordinates))
+		}
+		lat, lon, err := parseCoordinates(parts[1], parts[0])
+		return result.add(p.Name, p.Description, lat, lon, err)
+	})
+}
+
+// KMZ - A zip archive with a KML document (doc.kml, otherwise the first .kml file)
+func parseKMZ(data []byte, result *Result) error {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("invalid KMZ archive: %s", err)
+	}
+
+	var document *zip.File
+	for _, file := range archive.File {
+		if strings.EqualFold(filepath.Ext(file.Name), ".kml") && (document == nil || strings.EqualFold(file.Name, "doc.kml")) {
+			document = file
+		}
+	}
+	if document == nil {
+		return fmt.Errorf("invalid KMZ archive: no KML document")
+	}
+
+	reader, err := document.Open()
+	if err != nil {
+		return fmt.Errorf("invalid KMZ archive: %s", err)
+	}
+	defer reader.Close()
+
+	kml, err := io.ReadAll(io.LimitReader(reader, MaxKMLSize+1))
+	if err != nil {
+		return fmt.Errorf("invalid KMZ archive: %s", err)
+	}
+	if len(kml) > MaxKMLSize {
+		return fmt.Errorf("the KML document must be at most %d MB", MaxKMLSize>>20)
+	}
+	return parseKML(kml, result)
+}
+
+// GeoJSON - Point features of a FeatureCollection (or a single Feature)
+func parseGeoJSON(data []byte, result *Result) error {
+	type feature struct {
+		Type     string `json:"type"`
+		Geometry *struct {
+			Type        string          `json:"type"`
+			Coordinates json.RawMessage `json:"coordinates"`
+		} `json:"geometry"`
+		Properties map[string]any `json:"properties"`
+	}
+	var document struct {
+		feature
+		Features []feature `json:"features"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return fmt.Errorf("invalid GeoJSON: %s", err)
+	}
+
+	features := document.Features
+	switch document.Type {
+	case "FeatureCollection":
+	case "Feature":
+		features = []feature{document.feature}
+	default:
+		return fmt.Errorf("invalid GeoJSON: expected a FeatureCollection or Feature")
+	}
+
+	for _, f := range features {
+		name := stringProperty(f.Properties, "name", "title", "Name")
+		description := stringProperty(f.Properties, "description", "notes", "desc")
+
+		if f.Geometry == nil || f.Geometry.Type != "Point" {
+			if err := result.add(name, "", 0, 0, fmt.Errorf("only Point features can be imported")); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// [lon, lat(, altitude)]
+		var coordinates []float64
+		err := json.Unmarshal(f.Geometry.Coordinates, &coordinates)
+		if err == nil && len(coordinates) < 2 {
+			err = fmt.Errorf("a Point needs [lon, lat] coordinates")
+		}
+		if err != nil {
+			if err := result.add(name, "", 0, 0, fmt.Errorf("invalid coordinates")); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := result.add(name, description, coordinates[1], coordinates[0], nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeElements - Calls decode for every element with one of the names (Namespaces are ignored)
+func decodeElements(data []byte, names []string, decode func(*xml.Decoder, xml.StartElement) error) error {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("invalid XML: %s", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		for _, name := range names {
+			if start.Name.Local == name {
+				if err := decode(decoder, start); err != nil {
+					return err
+				}
+				break
+			}
+		}
+	}
+}
+
+func parseCoordinates(lat string, lon string) (float64, float64, error) {
+	latitude, errLat := strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	longitude, errLon := strconv.ParseFloat(strings.TrimSpace(lon), 64)
+	if errLat != nil || errLon != nil || !isFinite(latitude) || !isFinite(longitude) {
+		return 0, 0, fmt.Errorf("invalid coordinates")
+	}
+	return latitude, longitude, nil
+}
+
+// isFinite - ParseFloat accepts "NaN" and "Inf", which are not coordinates
+func isFinite(value float64) bool {
+	return !math.IsNaN(value) && !math.IsInf(value, 0)
+}
+
+func stringProperty(properties map[string]any, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := properties[key].(string); ok && strings.TrimSpace(value) != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func truncate(value string, length int) string {
+	if utf8.RuneCountInString(value) <= length {
+		return value
+	}
+	return string([]rune(value)[:length])
+}
@@ -0,0 +1,278 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// kmz - Zips the files in order (name, content pairs)
+func kmz(t *testing.T, files ...string) []byte {
+	t.Helper()
+
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+	for i := 0; i < len(files); i += 2 {
+		writer, err := archive.Create(files[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := writer.Write([]byte(files[i+1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+func kml(placemarks string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?><kml xmlns="http://www.opengis.net/kml/2.2"><Document>` + placemarks + `</Document></kml>`
+}
+
+func gpx(points string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?><gpx version="1.1" xmlns="http://www.topografix.com/GPX/1/1">` + points + `</gpx>`
+}
+
+// waypoints - A GPX file with count distinct waypoints
+func waypoints(count int) string {
+	var points strings.Builder
+	for i := 0; i < count; i++ {
+		fmt.Fprintf(&points, `<wpt lat="%f" lon="0"><name>Point %d</name></wpt>`, float64(i)/1000, i)
+	}
+	return gpx(points.String())
+}
+
+func TestParse(t *testing.T) {
+	louvre := `<Placemark><name>Louvre</name><description>Museum</description><Point><coordinates>2.3376,48.8606,35</coordinates></Point></Placemark>`
+
+	tests := []struct {
+		name     string
+		filename string
+		data     string
+		format   string
+		places   []Place
+		problems []Problem
+		err      error  // Sentinel error (errors.Is)
+		errText  string // Other errors (Contains)
+	}{
+		{
+			name:     "gpx waypoints and route points",
+			filename: "trip.GPX",
+			data: gpx(`<wpt lat="48.8606" lon="2.3376"><name>Louvre</name><desc>Museum</desc></wpt>` +
+				`<rte><rtept lat="48.8584" lon="2.2945"><name>Eiffel Tower</name><cmt>Tower</cmt></rtept><rtept lat="48.86" lon="2.3266"></rtept></rte>` +
+				`<trk><trkseg><trkpt lat="1" lon="1"></trkpt></trkseg></trk>`),
+			format: FormatGPX,
+			places: []Place{
+				{Index: 0, Name: "Louvre", Description: "Museum", Lat: 48.8606, Lon: 2.3376},
+				{Index: 1, Name: "Eiffel Tower", Description: "Tower", Lat: 48.8584, Lon: 2.2945},
+				{Index: 2, Name: "Place 3", Lat: 48.86, Lon: 2.3266},
+			},
+		},
+		{
+			name:     "gpx route point repeating a waypoint",
+			filename: "trip.gpx",
+			data: gpx(`<wpt lat="48.8606" lon="2.3376"><name>Louvre</name></wpt>` +
+				`<rte><rtept lat="48.8606" lon="2.3376"><name>Louvre</name></rtept><rtept lat="48.8606" lon="2.3376"><name>Louvre entrance</name></rtept></rte>`),
+			format: FormatGPX,
+			places: []Place{
+				{Index: 0, Name: "Louvre", Lat: 48.8606, Lon: 2.3376},
+				{Index: 1, Name: "Louvre entrance", Lat: 48.8606, Lon: 2.3376},
+			},
+		},
+		{
+			name:     "gpx invalid coordinates",
+			filename: "trip.gpx",
+			data: gpx(`<wpt lat="91" lon="0"><name>North</name></wpt><wpt lat="0" lon="-180.5"><name>West</name></wpt>` +
+				`<wpt lat="NaN" lon="0"><name>Nan</name></wpt><wpt lat="0" lon="Inf"><name>Infinite</name></wpt><wpt lat="-Inf" lon="0"></wpt>` +
+				`<wpt lat="abc" lon="0"><name>Text</name></wpt><wpt lat="-90" lon="180"><name>Corner</name></wpt>`),
+			format: FormatGPX,
+			places: []Place{{Index: 6, Name: "Corner", Lat: -90, Lon: 180}},
+			problems: []Problem{
+				{Index: 0, Name: "North", Error: "coordinates out of range"},
+				{Index: 1, Name: "West", Error: "coordinates out of range"},
+				{Index: 2, Name: "Nan", Error: "invalid coordinates"},
+				{Index: 3, Name: "Infinite", Error: "invalid coordinates"},
+				{Index: 4, Error: "invalid coordinates"},
+				{Index: 5, Name: "Text", Error: "invalid coordinates"},
+			},
+		},
+		{
+			name:     "kml placemarks in folders",
+			filename: "map.kml",
+			data: kml(`<Folder><name>Day 1</name>` + louvre + `</Folder>` +
+				`<Placemark><name>  Walk   along the Seine </name><LineString><coordinates>2.33,48.86 2.29,48.85</coordinates></LineString></Placemark>` +
+				`<Placemark><name>Broken</name><Point><coordinates>2.29</coordinates></Point></Placemark>` +
+				`<Placemark><name>Far north</name><Point><coordinates>2.29,95</coordinates></Point></Placemark>`),
+			format: FormatKML,
+			places: []Place{{Index: 0, Name: "Louvre", Description: "Museum", Lat: 48.8606, Lon: 2.3376}},
+			problems: []Problem{
+				{Index: 1, Name: "Walk along the Seine", Error: "only Point placemarks can be imported"},
+				{Index: 2, Name: "Broken", Error: "invalid coordinates '2.29'"},
+				{Index: 3, Name: "Far north", Error: "coordinates out of range"},
+			},
+		},
+		{
+			name:     "kmz reads doc.kml",
+			filename: "map.kmz",
+			data: string(kmz(t,
+				"files/other.kml", kml(`<Placemark><name>Other</name><Point><coordinates>1,1</coordinates></Point></Placemark>`),
+				"doc.kml", kml(louvre),
+				"images/icon.png", "PNG",
+			)),
+			format: FormatKMZ,
+			places: []Place{{Index: 0, Name: "Louvre", Description: "Museum", Lat: 48.8606, Lon: 2.3376}},
+		},
+		{
+			name:     "kmz without doc.kml reads the first kml file",
+			filename: "map.kmz",
+			data:     string(kmz(t, "images/icon.png", "PNG", "places.KML", kml(louvre), "later.kml", kml(""))),
+			format:   FormatKMZ,
+			places:   []Place{{Index: 0, Name: "Louvre", Description: "Museum", Lat: 48.8606, Lon: 2.3376}},
+		},
+		{
+			name:     "kmz without a kml document",
+			filename: "map.kmz",
+			data:     string(kmz(t, "images/icon.png", "PNG")),
+			errText:  "no KML document",
+		},
+		{
+			name:     "kmz over the kml size limit",
+			filename: "map.kmz",
+			data:     string(kmz(t, "doc.kml", kml(louvre+strings.Repeat(" ", MaxKMLSize)))),
+			errText:  "the KML document must be at most 8 MB",
+		},
+		{
+			name:     "kmz that is not a zip archive",
+			filename: "map.kmz",
+			data:     kml(louvre),
+			errText:  "invalid KMZ archive",
+		},
+		{
+			name:     "geojson feature collection",
+			filename: "places.geojson",
+			data: `{"type": "FeatureCollection", "features": [
+				{"type": "Feature", "geometry": {"type": "Point", "coordinates": [2.3376, 48.8606, 35]}, "properties": {"title": "Louvre", "notes": "Museum"}},
+				{"type": "Feature", "geometry": {"type": "LineString", "coordinates": [[2.33, 48.86], [2.29, 48.85]]}, "properties": {"name": "Walk"}},
+				{"type": "Feature", "geometry": null, "properties": {"name": "Nowhere"}},
+				{"type": "Feature", "geometry": {"type": "Point", "coordinates": [2.29]}, "properties": {"name": "Broken"}},
+				{"type": "Feature", "geometry": {"type": "Point", "coordinates": [2.29, 1e400]}, "properties": {"name": "Overflow"}},
+				{"type": "Feature", "geometry": {"type": "Point", "coordinates": [200, 48]}, "properties": {"name": "Off the map"}}
+			]}`,
+			format: FormatGeoJSON,
+			places: []Place{{Index: 0, Name: "Louvre", Description: "Museum", Lat: 48.8606, Lon: 2.3376}},
+			problems: []Problem{
+				{Index: 1, Name: "Walk", Error: "only Point features can be imported"},
+				{Index: 2, Name: "Nowhere", Error: "only Point features can be imported"},
+				{Index: 3, Name: "Broken", Error: "invalid coordinates"},
+				{Index: 4, Name: "Overflow", Error: "invalid coordinates"},
+				{Index: 5, Name: "Off the map", Error: "coordinates out of range"},
+			},
+		},
+		{
+			name:     "geojson single feature",
+			filename: "place.json",
+			data:     `{"type": "Feature", "geometry": {"type": "Point", "coordinates": [2.3376, 48.8606]}, "properties": {"name": "Louvre"}}`,
+			format:   FormatGeoJSON,
+			places:   []Place{{Index: 0, Name: "Louvre", Lat: 48.8606, Lon: 2.3376}},
+		},
+		{
+			name:     "geojson geometry without a feature",
+			filename: "place.geojson",
+			data:     `{"type": "Point", "coordinates": [2.3376, 48.8606]}`,
+			errText:  "expected a FeatureCollection or Feature",
+		},
+		{
+			name:     "gpx detected from the content",
+			filename: "upload",
+			data:     "\n  " + gpx(`<wpt lat="48.8606" lon="2.3376"><name>Louvre</name></wpt>`),
+			format:   FormatGPX,
+			places:   []Place{{Index: 0, Name: "Louvre", Lat: 48.8606, Lon: 2.3376}},
+		},
+		{
+			name:     "kml detected from the content",
+			filename: "upload",
+			data:     kml(louvre),
+			format:   FormatKML,
+			places:   []Place{{Index: 0, Name: "Louvre", Description: "Museum", Lat: 48.8606, Lon: 2.3376}},
+		},
+		{
+			name:     "kmz detected from the content",
+			filename: "upload.bin",
+			data:     string(kmz(t, "doc.kml", kml(louvre))),
+			format:   FormatKMZ,
+			places:   []Place{{Index: 0, Name: "Louvre", Description: "Museum", Lat: 48.8606, Lon: 2.3376}},
+		},
+		{
+			name:     "geojson detected from the content",
+			filename: "",
+			data:     `{"type": "Feature", "geometry": {"type": "Point", "coordinates": [2.3376, 48.8606]}, "properties": {"name": "Louvre"}}`,
+			format:   FormatGeoJSON,
+			places:   []Place{{Index: 0, Name: "Louvre", Lat: 48.8606, Lon: 2.3376}},
+		},
+		{
+			name:     "unknown format",
+			filename: "places.csv",
+			data:     "name,lat,lon\nLouvre,48.8606,2.3376",
+			err:      ErrUnknownFormat,
+		},
+		{
+			name:     "invalid xml",
+			filename: "trip.gpx",
+			data:     `<gpx><wpt lat="1" lon="1"><name>Louvre</wpt></gpx>`,
+			errText:  "invalid",
+		},
+		{
+			name:     "too many places",
+			filename: "trip.gpx",
+			data:     waypoints(MaxPlaces + 1),
+			err:      ErrTooManyPlaces,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := Parse(test.filename, []byte(test.data))
+			if test.err != nil || test.errText != "" {
+				if err == nil {
+					t.Fatalf("Parse() error = nil, want an error")
+				}
+				if test.err != nil && !errors.Is(err, test.err) {
+					t.Errorf("Parse() error = %v, want %v", err, test.err)
+				}
+				if !strings.Contains(err.Error(), test.errText) {
+					t.Errorf("Parse() error = %v, want %q", err, test.errText)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if result.Format != test.format {
+				t.Errorf("Format = %q, want %q", result.Format, test.format)
+			}
+			if !reflect.DeepEqual(result.Places, test.places) {
+				t.Errorf("Places = %+v, want %+v", result.Places, test.places)
+			}
+			if !reflect.DeepEqual(result.Problems, test.problems) {
+				t.Errorf("Problems = %+v, want %+v", result.Problems, test.problems)
+			}
+		})
+	}
+}
+
+func TestParseMaxPlaces(t *testing.T) {
+	result, err := Parse("trip.gpx", []byte(waypoints(MaxPlaces)))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(result.Places) != MaxPlaces {
+		t.Errorf("Places = %d, want %d", len(result.Places), MaxPlaces)
+	}
+}
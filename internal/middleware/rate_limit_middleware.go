@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Requests made by a user (or client IP) within the current window
+type rateWindow struct {
+	start    time.Time
+	requests int
+}
+
+// RateLimitMiddleware - Limits each user to a number of requests per window (Must run after SessionAuthMiddleware)
+func RateLimitMiddleware(requests int, window time.Duration) gin.HandlerFunc {
+	return rateLimit(requests, window, func(c *gin.Context) (string, bool) {
+		userId, exists := c.Get("userId")
+		if !exists {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "User ID not found in context",
+			})
+			return "", false
+		}
+		return fmt.Sprint(userId), true
+	})
+}
+
+// IPRateLimitMiddleware - Limits each client IP to a number of requests per window (For public routes)
+func IPRateLimitMiddleware(requests int, window time.Duration) gin.HandlerFunc {
+	return rateLimit(requests, window, func(c *gin.Context) (string, bool) {
+		return c.ClientIP(), true
+	})
+}
+
+// rateLimit - Counts requests per key (The key function aborts the request itself when it returns false)
+func rateLimit(requests int, window time.Duration, requestKey func(*gin.Context) (string, bool)) gin.HandlerFunc {
+	var mutex sync.Mutex
+	windows := map[string]*rateWindow{}
+
+	return func(c *gin.Context) {
+		key, ok := requestKey(c)
+		if !ok {
+			return
+		}
+		now := time.Now()
+
+		mutex.Lock()
+		current, ok := windows[key]
+		if !ok || now.Sub(current.start) >= window {
+			// Forget keys whose window has ended (Stops the map growing forever)
+			for k, w := range windows {
+				if now.Sub(w.start) >= window {
+					delete(windows, k)
+				}
+			}
+			current = &rateWindow{start: now}
+			windows[key] = current
+		}
+		current.requests++
+		allowed := current.requests <= requests
+		retryAfter := current.start.Add(window).Sub(now)
+		mutex.Unlock()
+
+		if !allowed {
+			seconds := int(math.Ceil(retryAfter.Seconds()))
+			c.Header("Retry-After", strconv.Itoa(seconds))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error": fmt.Sprintf("Too many requests (Try again in %d seconds)", seconds),
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -5,20 +5,33 @@ type Delete struct {
 }
 
 type EditUser struct {
-	Id        uint   `json:"id" binding:"required"`
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-	Username  string `json:"username"`
-	Email     string `json:"email"`
-	Password  string `json:"password"`
-	IsAdmin   bool   `json:"is_admin"`
+	Id              uint    `json:"id" binding:"required"`
+	FirstName       string  `json:"first_name"`
+	LastName        string  `json:"last_name"`
+	Username        string  `json:"username"`
+	Email           string  `json:"email"`
+	Password        string  `json:"password"`
+	HomeCountryCode *string `json:"home_country_code" binding:"omitempty,len=2"` // nil keeps the current country
+	IsAdmin         bool    `json:"is_admin"`
 }
 
 type AddUser struct {
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-	Username  string `json:"username" binding:"required"`
-	Email     string `json:"email" binding:"required,email"`
-	Password  string `json:"password" binding:"required"`
-	IsAdmin   bool   `json:"is_admin"`
+	FirstName       string `json:"first_name"`
+	LastName        string `json:"last_name"`
+	Username        string `json:"username" binding:"required"`
+	Email           string `json:"email" binding:"required,email"`
+	Password        string `json:"password" binding:"required"`
+	HomeCountryCode string `json:"home_country_code" binding:"omitempty,len=2"`
+	IsAdmin         bool   `json:"is_admin"`
+}
+
+type HidePoiReview struct {
+	Id     uint `json:"id" binding:"required"`
+	Hidden bool `json:"hidden"` // false shows the review again
+}
+
+type FlagPoiReview struct {
+	Id      uint   `json:"id" binding:"required"`
+	Flagged bool   `json:"flagged"` // false clears the flag
+	Reason  string `json:"reason"`
 }
@@ -0,0 +1,36 @@
+package models
+
+// Normalised Air Quality returned to the client (Independent of the provider)
+
+// Air Quality Levels (Shared 1 - 5 scale, OpenWeather uses this natively, European AQI is bucketed into it)
+var AirQualityCategories = []string{"good", "fair", "moderate", "poor", "very_poor"}
+
+type AirQualityPollutants struct {
+	Pm25 float64 `json:"pm2_5"`
+	Pm10 float64 `json:"pm10"`
+	O3   float64 `json:"o3"`
+	No2  float64 `json:"no2"`
+	So2  float64 `json:"so2"`
+	Co   float64 `json:"co"`
+}
+
+// Pollen in grains/m³ (null when the provider has no data for the location)
+type AirQualityPollen struct {
+	Alder   *float64 `json:"alder"`
+	Birch   *float64 `json:"birch"`
+	Grass   *float64 `json:"grass"`
+	Mugwort *float64 `json:"mugwort"`
+	Olive   *float64 `json:"olive"`
+	Ragweed *float64 `json:"ragweed"`
+}
+
+type AirQuality struct {
+	Provider   string               `json:"provider"`
+	Lat        float64              `json:"lat"`
+	Lon        float64              `json:"lon"`
+	Time       int64                `json:"time"`
+	Aqi        int                  `json:"aqi"` // 1 (Good) - 5 (Very Poor), 0 when unknown
+	Category   string               `json:"category"`
+	Pollutants AirQualityPollutants `json:"pollutants"` // µg/m³
+	Pollen     *AirQualityPollen    `json:"pollen"`     // null when pollen is unavailable
+}
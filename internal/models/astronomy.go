@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/MCantyDev/city-explorer-server/internal/astro"
+)
+
+// Where a city's timezone came from
+const (
+	TimezoneSourceCity    = "city"    // IANA timezone saved from the weather provider
+	TimezoneSourceCountry = "country" // The country's only UTC offset (No daylight saving)
+	TimezoneSourceSolar   = "solar"   // Estimated from the longitude (15 degrees per hour)
+)
+
+type CitySun struct {
+	astro.SunTimes
+	DayLength        string `json:"day_length"` // e.g. 16h38m
+	DayLengthMinutes int    `json:"day_length_minutes"`
+}
+
+// Local Time, Sun and Moon for a city on a date
+type CityTime struct {
+	Timezone       string          `json:"timezone"`
+	TimezoneSource string          `json:"timezone_source"`
+	LocalTime      time.Time       `json:"local_time"`
+	UTCOffset      string          `json:"utc_offset"` // e.g. +01:00
+	Date           string          `json:"date"`       // YYYY-MM-DD
+	Sun            CitySun         `json:"sun"`
+	Moon           astro.MoonPhase `json:"moon"` // At local noon
+}
@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+// Query options for the Climate endpoint
+type ClimateOptions struct {
+	Units      string
+	HasRange   bool
+	RangeStart time.Time // Only Month and Day are used
+	RangeEnd   time.Time // Only Month and Day are used
+}
+
+// Daily Weather History (Cached in city_weather_history, always metric)
+type WeatherHistory struct {
+	Provider       string     `json:"provider"`
+	Lat            float64    `json:"lat"`
+	Lon            float64    `json:"lon"`
+	Timezone       string     `json:"timezone"`
+	Dates          []string   `json:"dates"` // YYYY-MM-DD
+	TemperatureMax []*float64 `json:"temperature_max"`
+	TemperatureMin []*float64 `json:"temperature_min"`
+	Precipitation  []*float64 `json:"precipitation"`
+	SunshineHours  []*float64 `json:"sunshine_hours"`
+}
+
+// Climate returned to the client (Normals and Past Years for a date range)
+
+type ClimateNormal struct {
+	Month             int     `json:"month"` // 1 - 12
+	TemperatureMax    float64 `json:"temperature_max"`
+	TemperatureMin    float64 `json:"temperature_min"`
+	Precipitation     float64 `json:"precipitation"`      // Average monthly total
+	PrecipitationDays float64 `json:"precipitation_days"` // Average days with >= 1mm
+	SunshineHours     float64 `json:"sunshine_hours"`     // Average per day
+}
+
+type ClimateSummary struct {
+	TemperatureMax    float64 `json:"temperature_max"` // Average daily maximum
+	TemperatureMin    float64 `json:"temperature_min"` // Average daily minimum
+	HighestMax        float64 `json:"highest_max"`
+	LowestMin         float64 `json:"lowest_min"`
+	Precipitation     float64 `json:"precipitation"` // Total
+	PrecipitationDays float64 `json:"precipitation_days"`
+	SunshineHours     float64 `json:"sunshine_hours"` // Average per day
+}
+
+type ClimateYear struct {
+	Year  int    `json:"year"`
+	Start string `json:"start"` // YYYY-MM-DD
+	End   string `json:"end"`   // YYYY-MM-DD
+	ClimateSummary
+}
+
+type ClimateRange struct {
+	Start   string         `json:"start"` // MM-DD
+	End     string         `json:"end"`   // MM-DD
+	Average ClimateSummary `json:"average"`
+	Years   []ClimateYear  `json:"years"`
+}
+
+type Climate struct {
+	Provider   string          `json:"provider"`
+	Units      string          `json:"units"`
+	UnitLabels WeatherUnits    `json:"unit_labels"`
+	Lat        float64         `json:"lat"`
+	Lon        float64         `json:"lon"`
+	Timezone   string          `json:"timezone"`
+	StartYear  int             `json:"start_year"`
+	EndYear    int             `json:"end_year"`
+	Normals    []ClimateNormal `json:"normals"`
+	Range      *ClimateRange   `json:"range"` // null when no date range was requested
+}
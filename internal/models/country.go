@@ -0,0 +1,74 @@
+package models
+
+// Country Schema Versions (Selected with the 'schema-version' query parameter)
+const (
+	CountrySchemaLegacy  = 1 // Raw Rest Countries data
+	CountrySchemaProfile = 2 // CountryProfile
+	CountrySchemaVersion = CountrySchemaProfile
+)
+
+type CountryCurrency struct {
+	Code   string `json:"code"` // ISO 4217
+	Name   string `json:"name"`
+	Symbol string `json:"symbol"`
+}
+
+type CountryLanguage struct {
+	Code string `json:"code"` // ISO 639-3
+	Name string `json:"name"`
+}
+
+type CountryFlag struct {
+	Png string `json:"png"`
+	Svg string `json:"svg"`
+	Alt string `json:"alt"`
+}
+
+// Typed Country Profile (Stable schema returned to the client)
+type CountryProfile struct {
+	SchemaVersion   int               `json:"schema_version"`
+	Code            string            `json:"code"`  // ISO 3166-1 Alpha-2
+	Code3           string            `json:"code3"` // ISO 3166-1 Alpha-3
+	Name            string            `json:"name"`
+	OfficialName    string            `json:"official_name"`
+	Capital         []string          `json:"capital"`
+	Region          string            `json:"region"`
+	Subregion       string            `json:"subregion"`
+	Population      int64             `json:"population"`
+	Area            float64           `json:"area"` // km²
+	Lat             float64           `json:"lat"`
+	Lon             float64           `json:"lon"`
+	Currencies      []CountryCurrency `json:"currencies"`
+	Languages       []CountryLanguage `json:"languages"`
+	CallingCodes    []string          `json:"calling_codes"` // e.g. +33
+	Timezones       []string          `json:"timezones"`     // UTC offsets (e.g. UTC+01:00)
+	DrivingSide     string            `json:"driving_side"`  // left or right
+	Borders         []string          `json:"borders"`       // ISO 3166-1 Alpha-3 codes
+	TopLevelDomains []string          `json:"top_level_domains"`
+	Flag            CountryFlag       `json:"flag"`
+}
+
+// Short Country Summary (Neighbours)
+type CountrySummary struct {
+	Code       string      `json:"code"`
+	Code3      string      `json:"code3"`
+	Name       string      `json:"name"`
+	Capital    []string    `json:"capital"`
+	Region     string      `json:"region"`
+	Population int64       `json:"population"`
+	Flag       CountryFlag `json:"flag"`
+}
+
+// Result of a bulk country preload (Codes are ISO 3166-1 Alpha-2)
+type CountryPreloadReport struct {
+	Fetched   int                     `json:"fetched"`
+	Inserted  []string                `json:"inserted"`
+	Updated   []string                `json:"updated"`
+	Unchanged int                     `json:"unchanged"`
+	Failed    []CountryPreloadFailure `json:"failed"`
+}
+
+type CountryPreloadFailure struct {
+	Code  string `json:"code"`
+	Error string `json:"error"`
+}
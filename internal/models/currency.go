@@ -0,0 +1,23 @@
+package models
+
+// Exchange Rates from a single provider (Rates are units of each currency per 1 Base)
+type ExchangeRates struct {
+	Provider string             `json:"provider"`
+	Base     string             `json:"base"`
+	Date     string             `json:"date"` // Date the provider published the rates (YYYY-MM-DD)
+	Rates    map[string]float64 `json:"rates"`
+}
+
+type CurrencyConversion struct {
+	Amount    float64 `json:"amount"`
+	From      string  `json:"from"`
+	To        string  `json:"to"`
+	Rate      float64 `json:"rate"`
+	Result    float64 `json:"result"`
+	RatesDate string  `json:"rates_date"`
+	Provider  string  `json:"provider"`
+}
+
+type HomeCountryRequest struct {
+	CountryCode string `json:"country_code" binding:"required,len=2"`
+}
@@ -6,38 +6,52 @@ import (
 )
 
 type User struct {
-	Id        uint      `gorm:"primaryKey;autoIncrement"`
-	FirstName string    `gorm:"not null"`
-	LastName  string    `gorm:"not null"`
-	Username  string    `gorm:"unique;not null"`
-	Email     string    `gorm:"unique; not null"`
-	Password  string    `gorm:"not null"`
-	IsAdmin   bool      `gorm:"type:boolean"`
-	CreatedAt time.Time `gorm:"autoCreateTime"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+	Id              uint      `gorm:"primaryKey;autoIncrement"`
+	FirstName       string    `gorm:"not null"`
+	LastName        string    `gorm:"not null"`
+	Username        string    `gorm:"unique;not null"`
+	Email           string    `gorm:"unique; not null"`
+	HomeCountryCode string    `gorm:"not null"` // ISO 3166-1 Alpha-2 (Empty if not set)
+	Password        string    `gorm:"not null"`
+	IsAdmin         bool      `gorm:"type:boolean"`
+	CreatedAt       time.Time `gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime"`
 }
 
 type Country struct {
 	Id         uint            `gorm:"primaryKey;autoIncrement"`
 	Name       string          `gorm:"not null"`
 	IsoCode    string          `gorm:"not null"`
-	Data       json.RawMessage `gorm:"not null"`
+	IsoCode3   string          // ISO 3166-1 Alpha-3 (Used to resolve neighbours)
+	Data       json.RawMessage `gorm:"not null"`            // Single Rest Countries object
+	Profile    *CountryProfile `gorm:"-" json:",omitempty"` // Admin views only (Parsed from Data with schema-version=2)
 	CreatedAt  time.Time       `gorm:"autoCreateTime"`
 	UpdatedAt  time.Time       `gorm:"autoUpdateTime"`
 	ExpiryDate time.Time       `gorm:"type:timestamp"`
 }
 
+// Canonical City (Unique on Name, Country Code and Coordinates rounded to 0.1 degrees)
 type City struct {
-	Id        uint      `gorm:"primaryKey;autoIncrement"`
-	Name      string    `gorm:"not null"`
-	CreatedAt time.Time `gorm:"autoCreateTime"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+	Id             uint      `gorm:"primaryKey;autoIncrement"`
+	Name           string    `gorm:"not null"`
+	State          string    `gorm:"not null"`
+	CountryCode    string    `gorm:"not null"`
+	CountryId      *uint     // nil until the country has been fetched
+	Lat            *float64  `gorm:"type:decimal(9,6)"` // nil for legacy (name only) cities
+	Lon            *float64  `gorm:"type:decimal(9,6)"` // nil for legacy (name only) cities
+	OsmId          *int64    `gorm:"uniqueIndex"`
+	PopulationRank int       `gorm:"not null"`
+	Timezone       string    `gorm:"not null"` // IANA name (e.g. Europe/Paris)
+	CreatedAt      time.Time `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime"`
 }
 
 type CityWeather struct {
 	Id          uint            `gorm:"primaryKey;autoIncrement"`
-	CityName    string          `gorm:"not null"`
-	CountryName string          `gorm:"not null"`
+	CityId      uint            `gorm:"not null"`
+	CountryId   uint            `gorm:"not null"`
+	CityName    string          `gorm:"->"` // Admin views only (Joined from cities)
+	CountryName string          `gorm:"->"` // Admin views only (Joined from countries)
 	Lat         float64         `gorm:"type:decimal(9,6);not null"`
 	Lon         float64         `gorm:"type:decimal(9,6);not null"`
 	Data        json.RawMessage `gorm:"type:json;not null"`
@@ -48,10 +62,13 @@ type CityWeather struct {
 
 type CitySights struct {
 	Id          uint            `gorm:"primaryKey;autoIncrement"`
-	CityName    string          `gorm:"not null"`
-	CountryName string          `gorm:"not null"`
+	CityId      uint            `gorm:"not null"`
+	CountryId   uint            `gorm:"not null"`
+	CityName    string          `gorm:"->"` // Admin views only (Joined from cities)
+	CountryName string          `gorm:"->"` // Admin views only (Joined from countries)
 	Lat         float64         `gorm:"not null"`
 	Lon         float64         `gorm:"not null"`
+	SearchKey   string          `gorm:"not null"` // services.SightsSearchKey
 	Data        json.RawMessage `gorm:"not null"`
 	CreatedAt   time.Time       `gorm:"autoCreateTime"`
 	UpdatedAt   time.Time       `gorm:"autoUpdateTime"`
@@ -60,11 +77,189 @@ type CitySights struct {
 
 type CityPoi struct {
 	Id          uint            `gorm:"primaryKey;autoIncrement"`
-	CityName    string          `gorm:"not null"`
-	CountryName string          `gorm:"not null"`
+	CityId      uint            `gorm:"not null"`
+	CountryId   uint            `gorm:"not null"`
+	CityName    string          `gorm:"->"` // Admin views only (Joined from cities)
+	CountryName string          `gorm:"->"` // Admin views only (Joined from countries)
 	Xid         string          `gorm:"not null"`
 	Data        json.RawMessage `gorm:"not null"`
 	CreatedAt   time.Time       `gorm:"autoCreateTime"`
 	UpdatedAt   time.Time       `gorm:"autoUpdateTime"`
 	ExpiryDate  time.Time       `gorm:"type:timestamp"`
 }
+
+type CityAirQuality struct {
+	Id          uint            `gorm:"primaryKey;autoIncrement"`
+	CityId      uint            `gorm:"not null"`
+	CountryId   uint            `gorm:"not null"`
+	CityName    string          `gorm:"->"` // Admin views only (Joined from cities)
+	CountryName string          `gorm:"->"` // Admin views only (Joined from countries)
+	Lat         float64         `gorm:"type:decimal(9,6);not null"`
+	Lon         float64         `gorm:"type:decimal(9,6);not null"`
+	Data        json.RawMessage `gorm:"type:json;not null"`
+	CreatedAt   time.Time       `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time       `gorm:"autoUpdateTime"`
+	ExpiryDate  time.Time       `gorm:"type:timestamp"`
+}
+
+type CityWeatherHistory struct {
+	Id          uint            `gorm:"primaryKey;autoIncrement"`
+	CityId      uint            `gorm:"not null"`
+	CountryId   uint            `gorm:"not null"`
+	CityName    string          `gorm:"->"` // Admin views only (Joined from cities)
+	CountryName string          `gorm:"->"` // Admin views only (Joined from countries)
+	Lat         float64         `gorm:"type:decimal(9,6);not null"`
+	Lon         float64         `gorm:"type:decimal(9,6);not null"`
+	StartYear   int             `gorm:"not null"`
+	EndYear     int             `gorm:"not null"`
+	Data        json.RawMessage `gorm:"type:json;not null"`
+	CreatedAt   time.Time       `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time       `gorm:"autoUpdateTime"`
+	ExpiryDate  time.Time       `gorm:"type:timestamp"`
+}
+
+type ReverseGeocode struct {
+	Id         uint            `gorm:"primaryKey;autoIncrement"`
+	Lat        float64         `gorm:"type:decimal(9,6);not null"` // Snapped
+	Lon        float64         `gorm:"type:decimal(9,6);not null"` // Snapped
+	Data       json.RawMessage `gorm:"type:json;not null"`
+	CreatedAt  time.Time       `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time       `gorm:"autoUpdateTime"`
+	ExpiryDate time.Time       `gorm:"type:timestamp"`
+}
+
+type AutocompleteCache struct {
+	Id         uint            `gorm:"primaryKey;autoIncrement"`
+	Prefix     string          `gorm:"not null"`
+	Lang       string          `gorm:"not null"`
+	Data       json.RawMessage `gorm:"type:json;not null"`
+	CreatedAt  time.Time       `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time       `gorm:"autoUpdateTime"`
+	ExpiryDate time.Time       `gorm:"type:timestamp"`
+}
+
+type ExchangeRate struct {
+	Id        uint            `gorm:"primaryKey;autoIncrement"`
+	RateDate  time.Time       `gorm:"type:date;not null"` // Day the rates were published (ECB 'time' attribute)
+	Provider  string          `gorm:"not null"`
+	Base      string          `gorm:"not null"`
+	Data      json.RawMessage `gorm:"type:json;not null"`
+	CreatedAt time.Time       `gorm:"autoCreateTime"`
+	UpdatedAt time.Time       `gorm:"autoUpdateTime"`
+}
+
+type Favourite struct {
+	Id        uint            `gorm:"primaryKey;autoIncrement"`
+	UserId    uint            `gorm:"not null"`
+	Kind      string          `gorm:"not null"` // FavouriteCity, FavouritePoi or FavouritePlace
+	CityId    *uint           // Set for cities (and POIs whose city is known)
+	Xid       *string         // Set for POIs
+	Lat       *float64        `gorm:"type:decimal(9,6)"` // Set for places
+	Lon       *float64        `gorm:"type:decimal(9,6)"` // Set for places
+	Name      string          `gorm:"not null"`
+	Notes     *string         `gorm:"type:text"`
+	Tags      json.RawMessage `gorm:"type:json;not null"`
+	CreatedAt time.Time       `gorm:"autoCreateTime"`
+	UpdatedAt time.Time       `gorm:"autoUpdateTime"`
+
+	// Summary (Joined from cities, city_weather and city_pois when listed)
+	CityName           *string    `gorm:"->"`
+	CountryCode        *string    `gorm:"->"`
+	Temperature        *float64   `gorm:"->"`
+	WeatherDescription *string    `gorm:"->"`
+	WeatherIcon        *string    `gorm:"->"`
+	WeatherUpdatedAt   *time.Time `gorm:"->"`
+	PoiName            *string    `gorm:"->"`
+	PoiKinds           *string    `gorm:"->"`
+	LocationLat        *float64   `gorm:"->"` // City centre, cached POI point or place coordinates
+	LocationLon        *float64   `gorm:"->"`
+}
+
+type Trip struct {
+	Id        uint      `gorm:"primaryKey;autoIncrement"`
+	UserId    uint      `gorm:"not null"`
+	Name      string    `gorm:"not null"`
+	StartDate time.Time `gorm:"type:date;not null"`
+	EndDate   time.Time `gorm:"type:date;not null"`
+	Notes     *string   `gorm:"type:text"`
+	Version   int       `gorm:"not null;default:1"` // Bumped on every change to the plan
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+
+	// Joined for the current user
+	Role string `gorm:"->"` // TripRole*
+}
+
+type TripCity struct {
+	TripId   uint `gorm:"primaryKey"`
+	CityId   uint `gorm:"primaryKey"`
+	Position int  `gorm:"not null"`
+
+	// Joined from cities
+	Name        string   `gorm:"->"`
+	CountryCode string   `gorm:"->"`
+	Lat         *float64 `gorm:"->"`
+	Lon         *float64 `gorm:"->"`
+	Timezone    string   `gorm:"->"`
+}
+
+type TripItem struct {
+	Id        uint      `gorm:"primaryKey;autoIncrement"`
+	TripId    uint      `gorm:"not null"`
+	Day       int       `gorm:"not null"` // 1 = Start Date
+	TimeSlot  string    `gorm:"not null"` // TimeSlot*
+	Position  int       `gorm:"not null"` // Order within the day
+	Kind      string    `gorm:"not null"` // TripItemPoi or TripItemCustom
+	Xid       *string   // POIs only
+	CityId    *uint     // One of the trip's cities (if known)
+	Name      string    `gorm:"not null"`
+	Lat       *float64  `gorm:"type:decimal(9,6)"`
+	Lon       *float64  `gorm:"type:decimal(9,6)"`
+	Notes     *string   `gorm:"type:text"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+type TripShare struct {
+	Id           uint       `gorm:"primaryKey;autoIncrement"`
+	TripId       uint       `gorm:"not null"`
+	TokenHash    string     `gorm:"not null"` // SHA-256 of the token (Hex)
+	TokenHint    string     `gorm:"not null"` // First characters of the token, so the owner can tell links apart
+	PasswordHash *string    // bcrypt, nil when no password is needed
+	ExpiresAt    *time.Time // nil never expires
+	Views        int        `gorm:"not null"`
+	LastViewedAt *time.Time
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+}
+
+type TripMember struct {
+	Id        uint      `gorm:"primaryKey;autoIncrement"`
+	TripId    uint      `gorm:"not null"`
+	UserId    uint      `gorm:"not null"`
+	Role      string    `gorm:"not null"` // TripRoleEditor or TripRoleViewer
+	Status    string    `gorm:"not null"` // TripMemberPending or TripMemberAccepted
+	InvitedBy *uint     // nil once the inviting user is deleted
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+
+	// Joined from users
+	Username  string `gorm:"->"`
+	FirstName string `gorm:"->"`
+	LastName  string `gorm:"->"`
+}
+
+type PoiReview struct {
+	Id         uint    `gorm:"primaryKey;autoIncrement"`
+	Xid        string  `gorm:"not null"`
+	UserId     uint    `gorm:"not null"`
+	Rating     int     `gorm:"not null"` // 1 - 5
+	Review     *string `gorm:"type:text"`
+	Hidden     bool    `gorm:"not null"` // Hidden by an admin (Only the author still sees it)
+	Flagged    bool    `gorm:"not null"` // Reported for an admin to look at
+	FlagReason *string
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+
+	// Joined from users
+	Username string `gorm:"->"`
+}
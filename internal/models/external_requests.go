@@ -5,11 +5,80 @@ import "encoding/json"
 // Get ALL information from the Request
 type PhotonRequest struct {
 	Type     string          `json:"type"`
-	Features json.RawMessage `json:"features"`
+	Features []PhotonFeature `json:"features"`
+}
+
+// Photon GeoJSON Feature (Search and Reverse)
+type PhotonFeature struct {
+	Geometry struct {
+		Coordinates []float64 `json:"coordinates"` // [lon, lat]
+	} `json:"geometry"`
+	Properties struct {
+		OsmId       int64     `json:"osm_id"`
+		OsmType     string    `json:"osm_type"`
+		OsmKey      string    `json:"osm_key"`
+		OsmValue    string    `json:"osm_value"`
+		Type        string    `json:"type"`
+		Name        string    `json:"name"`
+		City        string    `json:"city"`
+		State       string    `json:"state"`
+		Country     string    `json:"country"`
+		CountryCode string    `json:"countrycode"`
+		Extent      []float64 `json:"extent"` // [minLon, maxLat, maxLon, minLat]
+	} `json:"properties"`
 }
 
 type RestCountriesRequest []json.RawMessage
 
+// Rest Countries v3.1 Country (Only the fields used by the CountryProfile)
+type RestCountry struct {
+	Name struct {
+		Common   string `json:"common"`
+		Official string `json:"official"`
+	} `json:"name"`
+	Cca2       string    `json:"cca2"`
+	Cca3       string    `json:"cca3"`
+	Tld        []string  `json:"tld"`
+	Capital    []string  `json:"capital"`
+	Region     string    `json:"region"`
+	Subregion  string    `json:"subregion"`
+	Population int64     `json:"population"`
+	Area       float64   `json:"area"`
+	Latlng     []float64 `json:"latlng"`
+	Currencies map[string]struct {
+		Name   string `json:"name"`
+		Symbol string `json:"symbol"`
+	} `json:"currencies"`
+	Languages map[string]string `json:"languages"`
+	Idd       struct {
+		Root     string   `json:"root"`
+		Suffixes []string `json:"suffixes"`
+	} `json:"idd"`
+	Timezones []string `json:"timezones"`
+	Car       struct {
+		Side string `json:"side"`
+	} `json:"car"`
+	Borders []string `json:"borders"`
+	Flags   struct {
+		Png string `json:"png"`
+		Svg string `json:"svg"`
+		Alt string `json:"alt"`
+	} `json:"flags"`
+}
+
+// European Central Bank daily reference rates (Rates are per 1 EUR)
+type ECBRatesRequest struct {
+	Cube struct {
+		Cube struct {
+			Time  string `xml:"time,attr"`
+			Rates []struct {
+				Currency string  `xml:"currency,attr"`
+				Rate     float64 `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
 type OpenWeatherRequest struct {
 	Lat             float64         `json:"lat"`
 	Long            float64         `json:"lon"`
@@ -17,6 +86,232 @@ type OpenWeatherRequest struct {
 	Timezone_Offset float64         `json:"timezone_offset"`
 	Current         json.RawMessage `json:"current"`
 	Daily           json.RawMessage `json:"daily"`
+	Hourly          json.RawMessage `json:"hourly,omitempty"`
+	Minutely        json.RawMessage `json:"minutely,omitempty"`
+	Alerts          json.RawMessage `json:"alerts,omitempty"`
+}
+
+// OpenWeather One Call shaped Current/Daily entries (Other weather providers are mapped into these)
+type OpenWeatherCondition struct {
+	Id          int    `json:"id"`
+	Main        string `json:"main"`
+	Description string `json:"description"`
+	Icon        string `json:"icon"`
+}
+
+type OpenWeatherCurrent struct {
+	Dt        int64                  `json:"dt"`
+	Sunrise   int64                  `json:"sunrise"`
+	Sunset    int64                  `json:"sunset"`
+	Temp      float64                `json:"temp"`
+	FeelsLike float64                `json:"feels_like"`
+	Pressure  float64                `json:"pressure"`
+	Humidity  float64                `json:"humidity"`
+	Uvi       float64                `json:"uvi"`
+	Clouds    float64                `json:"clouds"`
+	WindSpeed float64                `json:"wind_speed"`
+	WindDeg   float64                `json:"wind_deg"`
+	WindGust  float64                `json:"wind_gust"`
+	Weather   []OpenWeatherCondition `json:"weather"`
+}
+
+type OpenWeatherDaily struct {
+	Dt      int64 `json:"dt"`
+	Sunrise int64 `json:"sunrise"`
+	Sunset  int64 `json:"sunset"`
+
+	Temp struct {
+		Day float64 `json:"day"`
+		Min float64 `json:"min"`
+		Max float64 `json:"max"`
+	} `json:"temp"`
+
+	FeelsLike struct {
+		Day float64 `json:"day"`
+	} `json:"feels_like"`
+
+	WindSpeed float64                `json:"wind_speed"`
+	WindDeg   float64                `json:"wind_deg"`
+	WindGust  float64                `json:"wind_gust"`
+	Weather   []OpenWeatherCondition `json:"weather"`
+	Pop       float64                `json:"pop"`
+	Rain      float64                `json:"rain"`
+	Uvi       float64                `json:"uvi"`
+}
+
+type OpenWeatherHourly struct {
+	Dt        int64                  `json:"dt"`
+	Temp      float64                `json:"temp"`
+	FeelsLike float64                `json:"feels_like"`
+	Pressure  float64                `json:"pressure"`
+	Humidity  float64                `json:"humidity"`
+	Uvi       float64                `json:"uvi"`
+	Clouds    float64                `json:"clouds"`
+	WindSpeed float64                `json:"wind_speed"`
+	WindDeg   float64                `json:"wind_deg"`
+	WindGust  float64                `json:"wind_gust"`
+	Weather   []OpenWeatherCondition `json:"weather"`
+	Pop       float64                `json:"pop"`
+
+	Rain struct {
+		OneHour float64 `json:"1h"`
+	} `json:"rain"`
+
+	Snow struct {
+		OneHour float64 `json:"1h"`
+	} `json:"snow"`
+}
+
+type OpenWeatherMinutely struct {
+	Dt            int64   `json:"dt"`
+	Precipitation float64 `json:"precipitation"` // mm/h
+}
+
+type OpenWeatherAlert struct {
+	SenderName  string   `json:"sender_name"`
+	Event       string   `json:"event"`
+	Start       int64    `json:"start"`
+	End         int64    `json:"end"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+}
+
+// Open-Meteo Forecast (Requested with timeformat=unixtime and wind_speed_unit=ms to match OpenWeather)
+type OpenMeteoRequest struct {
+	Latitude         float64 `json:"latitude"`
+	Longitude        float64 `json:"longitude"`
+	Timezone         string  `json:"timezone"`
+	UtcOffsetSeconds float64 `json:"utc_offset_seconds"`
+
+	Current struct {
+		Time                int64   `json:"time"`
+		Temperature         float64 `json:"temperature_2m"`
+		RelativeHumidity    float64 `json:"relative_humidity_2m"`
+		ApparentTemperature float64 `json:"apparent_temperature"`
+		IsDay               int     `json:"is_day"`
+		Precipitation       float64 `json:"precipitation"`
+		WeatherCode         *int    `json:"weather_code"` // Null when Open-Meteo has no reading
+		CloudCover          float64 `json:"cloud_cover"`
+		PressureMsl         float64 `json:"pressure_msl"`
+		WindSpeed           float64 `json:"wind_speed_10m"`
+		WindDirection       float64 `json:"wind_direction_10m"`
+		WindGusts           float64 `json:"wind_gusts_10m"`
+		UvIndex             float64 `json:"uv_index"`
+	} `json:"current"`
+
+	Daily struct {
+		Time                        []int64   `json:"time"`
+		WeatherCode                 []*int    `json:"weather_code"`
+		TemperatureMax              []float64 `json:"temperature_2m_max"`
+		TemperatureMin              []float64 `json:"temperature_2m_min"`
+		ApparentTemperatureMax      []float64 `json:"apparent_temperature_max"`
+		Sunrise                     []int64   `json:"sunrise"`
+		Sunset                      []int64   `json:"sunset"`
+		UvIndexMax                  []float64 `json:"uv_index_max"`
+		PrecipitationSum            []float64 `json:"precipitation_sum"`
+		PrecipitationProbabilityMax []float64 `json:"precipitation_probability_max"`
+		WindSpeedMax                []float64 `json:"wind_speed_10m_max"`
+		WindGustsMax                []float64 `json:"wind_gusts_10m_max"`
+		WindDirectionDominant       []float64 `json:"wind_direction_10m_dominant"`
+	} `json:"daily"`
+}
+
+type OpenMeteoHourlyRequest struct {
+	Latitude         float64 `json:"latitude"`
+	Longitude        float64 `json:"longitude"`
+	Timezone         string  `json:"timezone"`
+	UtcOffsetSeconds float64 `json:"utc_offset_seconds"`
+
+	Hourly struct {
+		Time                     []int64   `json:"time"`
+		Temperature              []float64 `json:"temperature_2m"`
+		RelativeHumidity         []float64 `json:"relative_humidity_2m"`
+		ApparentTemperature      []float64 `json:"apparent_temperature"`
+		PrecipitationProbability []float64 `json:"precipitation_probability"`
+		Precipitation            []float64 `json:"precipitation"`
+		WeatherCode              []*int    `json:"weather_code"`
+		PressureMsl              []float64 `json:"pressure_msl"`
+		CloudCover               []float64 `json:"cloud_cover"`
+		WindSpeed                []float64 `json:"wind_speed_10m"`
+		WindDirection            []float64 `json:"wind_direction_10m"`
+		WindGusts                []float64 `json:"wind_gusts_10m"`
+		UvIndex                  []float64 `json:"uv_index"`
+		IsDay                    []int     `json:"is_day"`
+	} `json:"hourly"`
+}
+
+type OpenMeteoMinutelyRequest struct {
+	Latitude         float64 `json:"latitude"`
+	Longitude        float64 `json:"longitude"`
+	Timezone         string  `json:"timezone"`
+	UtcOffsetSeconds float64 `json:"utc_offset_seconds"`
+
+	Minutely15 struct {
+		Time          []int64   `json:"time"`
+		Precipitation []float64 `json:"precipitation"` // mm over the preceding 15 minutes
+	} `json:"minutely_15"`
+}
+
+type OpenWeatherAirRequest struct {
+	Coord struct {
+		Lat float64 `json:"lat"`
+		Lon float64 `json:"lon"`
+	} `json:"coord"`
+
+	List []struct {
+		Dt int64 `json:"dt"`
+
+		Main struct {
+			Aqi int `json:"aqi"` // 1 (Good) - 5 (Very Poor)
+		} `json:"main"`
+
+		Components struct {
+			Co   float64 `json:"co"`
+			No2  float64 `json:"no2"`
+			O3   float64 `json:"o3"`
+			So2  float64 `json:"so2"`
+			Pm25 float64 `json:"pm2_5"`
+			Pm10 float64 `json:"pm10"`
+		} `json:"components"`
+	} `json:"list"`
+}
+
+// Open-Meteo Air Quality (Pollen is only available in Europe, null elsewhere)
+type OpenMeteoAirRequest struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+
+	Current struct {
+		Time            int64    `json:"time"`
+		EuropeanAqi     *float64 `json:"european_aqi"` // Null when Open-Meteo has no reading
+		Pm10            float64  `json:"pm10"`
+		Pm25            float64  `json:"pm2_5"`
+		CarbonMonoxide  float64  `json:"carbon_monoxide"`
+		NitrogenDioxide float64  `json:"nitrogen_dioxide"`
+		SulphurDioxide  float64  `json:"sulphur_dioxide"`
+		Ozone           float64  `json:"ozone"`
+		AlderPollen     *float64 `json:"alder_pollen"`
+		BirchPollen     *float64 `json:"birch_pollen"`
+		GrassPollen     *float64 `json:"grass_pollen"`
+		MugwortPollen   *float64 `json:"mugwort_pollen"`
+		OlivePollen     *float64 `json:"olive_pollen"`
+		RagweedPollen   *float64 `json:"ragweed_pollen"`
+	} `json:"current"`
+}
+
+// Open-Meteo Historical Weather (Values are null where the archive has gaps)
+type OpenMeteoArchiveRequest struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Timezone  string  `json:"timezone"`
+
+	Daily struct {
+		Time             []string   `json:"time"` // YYYY-MM-DD
+		TemperatureMax   []*float64 `json:"temperature_2m_max"`
+		TemperatureMin   []*float64 `json:"temperature_2m_min"`
+		PrecipitationSum []*float64 `json:"precipitation_sum"`
+		SunshineDuration []*float64 `json:"sunshine_duration"` // Seconds
+	} `json:"daily"`
 }
 
 type OpenTripRequest struct {
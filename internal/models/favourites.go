@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// Favourite Kinds
+const (
+	FavouriteCity  = "city"
+	FavouritePoi   = "poi"
+	FavouritePlace = "place" // Custom place saved by its coordinates
+)
+
+// Body of POST /auth/favourites (Cities use City, CountryCode, Lat and Lon - POIs use Xid - Places use Name, Lat and Lon)
+type FavouriteRequest struct {
+	Kind        string   `json:"kind" binding:"required,oneof=city poi place"`
+	City        string   `json:"city"`
+	CountryCode string   `json:"country_code"`
+	Lat         string   `json:"lat"`
+	Lon         string   `json:"lon"`
+	Xid         string   `json:"xid"`
+	Name        string   `json:"name"` // Optional (Defaults to the city or cached POI name)
+	Notes       string   `json:"notes"`
+	Tags        []string `json:"tags"`
+}
+
+// Body of PATCH /auth/favourites/:id (Only the fields that are set are changed)
+type FavouriteUpdate struct {
+	Name  *string   `json:"name"`
+	Notes *string   `json:"notes"`
+	Tags  *[]string `json:"tags"`
+}
+
+type FavouriteView struct {
+	Id        uint              `json:"id"`
+	Kind      string            `json:"kind"`
+	CityId    *uint             `json:"city_id"`
+	Xid       *string           `json:"xid"`
+	Name      string            `json:"name"`
+	Notes     string            `json:"notes"`
+	Tags      []string          `json:"tags"`
+	Lat       *float64          `json:"lat"` // nil until the city or POI has coordinates
+	Lon       *float64          `json:"lon"`
+	Summary   *FavouriteSummary `json:"summary"` // nil if nothing is cached
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// Cheap summary from the caches (No external API calls)
+type FavouriteSummary struct {
+	CityName           string     `json:"city_name,omitempty"`
+	CountryCode        string     `json:"country_code,omitempty"`
+	Temperature        *float64   `json:"temperature,omitempty"` // °C
+	WeatherDescription string     `json:"weather_description,omitempty"`
+	WeatherIcon        string     `json:"weather_icon,omitempty"`
+	WeatherUpdatedAt   *time.Time `json:"weather_updated_at,omitempty"`
+	PoiName            string     `json:"poi_name,omitempty"`
+	PoiKinds           string     `json:"poi_kinds,omitempty"`
+}
@@ -0,0 +1,38 @@
+package models
+
+type BoundingBox struct {
+	MinLat float64 `json:"min_lat"`
+	MinLon float64 `json:"min_lon"`
+	MaxLat float64 `json:"max_lat"`
+	MaxLon float64 `json:"max_lon"`
+}
+
+// City Search Options
+type PlaceSearch struct {
+	Query string
+	Limit int
+	Lang  string
+}
+
+// Normalised Place (Geocoder results, ready for the weather/sights calls)
+type Place struct {
+	Provider    string       `json:"provider"`
+	Name        string       `json:"name"`
+	Type        string       `json:"type"` // city, town, village...
+	State       string       `json:"state"`
+	Country     string       `json:"country"`
+	CountryCode string       `json:"country_code"` // ISO 3166-1 Alpha-2
+	Lat         float64      `json:"lat"`
+	Lon         float64      `json:"lon"`
+	OsmId       int64        `json:"osm_id"`
+	OsmType     string       `json:"osm_type"`
+	Rank        int          `json:"population_rank"` // From the place type (city 4, municipality 3, town 2, village 1, other 0)
+	BoundingBox *BoundingBox `json:"bounding_box"`    // null if the provider has no extent
+}
+
+// Autocomplete Result (The query is echoed back so clients can drop responses for stale keystrokes)
+type Autocomplete struct {
+	Query   string  `json:"query"`
+	Source  string  `json:"source"` // memory, cache, local or the geocoder name
+	Results []Place `json:"results"`
+}
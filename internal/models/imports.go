@@ -0,0 +1,42 @@
+package models
+
+// Import Targets (Empty previews the matches without saving)
+const (
+	ImportTargetFavourites = "favourites"
+	ImportTargetTrip       = "trip"
+)
+
+// Cached OpenTripMap POI an imported place was matched to
+type ImportMatch struct {
+	Xid        string   `json:"xid"`
+	Name       string   `json:"name"`
+	CityId     uint     `json:"city_id"`
+	Categories []string `json:"categories"`
+	Distance   float64  `json:"distance"` // Metres from the imported point
+}
+
+type ImportItem struct {
+	Index       int          `json:"index"` // Position in the file (0 based)
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Lat         *float64     `json:"lat"` // nil when the entry could not be read
+	Lon         *float64     `json:"lon"`
+	Kind        string       `json:"kind,omitempty"` // poi (Matched) or custom
+	Match       *ImportMatch `json:"match"`
+	Saved       bool         `json:"saved"`
+	SavedId     *uint        `json:"saved_id,omitempty"` // Favourite or trip item id
+	Error       string       `json:"error,omitempty"`
+}
+
+// Response of POST /auth/import-places (One item per entry of the file, in file order)
+type ImportReport struct {
+	Format  string       `json:"format"`
+	Target  string       `json:"target"`
+	TripId  *uint        `json:"trip_id,omitempty"`
+	Total   int          `json:"total"`
+	Matched int          `json:"matched"`
+	Custom  int          `json:"custom"`
+	Saved   int          `json:"saved"`
+	Failed  int          `json:"failed"`
+	Items   []ImportItem `json:"items"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import "github.com/MCantyDev/city-explorer-server/internal/geo"
+
+// Itinerary Suggestion Parameters
+type ItineraryOptions struct {
+	Days       int
+	PerDay     int       // Maximum stops per day
+	Categories []string  // Our category ids (Empty matches every sightseeing category)
+	StartDate  string    // YYYY-MM-DD (Optional, needed to match the cached forecast)
+	Centre     geo.Point // Each day starts from here
+}
+
+type ItineraryStop struct {
+	Xid        string   `json:"xid"`
+	Name       string   `json:"name"`
+	Lat        float64  `json:"lat"`
+	Lon        float64  `json:"lon"`
+	Rate       int      `json:"rate"`
+	Categories []string `json:"categories"`
+	Outdoor    bool     `json:"outdoor"`
+	geo.Leg             // From the previous stop (The city centre for the first stop)
+}
+
+type ItineraryDay struct {
+	Day            int             `json:"day"`
+	Date           string          `json:"date,omitempty"`
+	Forecast       *WeatherDaily   `json:"forecast"`
+	HeavyRain      bool            `json:"heavy_rain"`
+	OutdoorShare   float64         `json:"outdoor_share"` // Fraction of the planned stops that are outdoors (0 - 1)
+	Stops          []ItineraryStop `json:"stops"`
+	Skipped        []ItineraryStop `json:"skipped"`  // Outdoor stops dropped because of heavy rain
+	Distance       float64         `json:"distance"` // Metres walked between stops
+	WalkingMinutes int             `json:"walking_minutes"`
+}
+
+// Response of GET /auth/suggest-itinerary
+type Itinerary struct {
+	CityId      uint           `json:"city_id"`
+	City        string         `json:"city"`
+	CountryCode string         `json:"country_code"`
+	Categories  []string       `json:"categories"`
+	Candidates  int            `json:"candidates"` // Cached sights matching the categories
+	Days        []ItineraryDay `json:"days"`
+}
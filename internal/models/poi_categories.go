@@ -0,0 +1,21 @@
+package models
+
+import "github.com/MCantyDev/city-explorer-server/internal/geo"
+
+// Server-side POI Category (Mapped from one or more OpenTripMap kinds)
+type PoiCategory struct {
+	Id       string        `json:"id"`
+	Name     string        `json:"name"` // Display Name
+	Icon     string        `json:"icon"` // Material Symbols icon name
+	Parent   string        `json:"parent,omitempty"`
+	Kinds    []string      `json:"kinds"` // OpenTripMap kinds mapped to this category
+	Children []PoiCategory `json:"children,omitempty"`
+}
+
+// Point of Interest returned to the client (OpenTripMap place with our category ids)
+type PoiDetails struct {
+	OpenTripPlaceRequest
+	Categories []string   `json:"categories"`
+	UserRating *PoiRating `json:"user_rating"` // From our users' reviews (nil without reviews)
+	*geo.Leg              // Only when an anchor point is given
+}
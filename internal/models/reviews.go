@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// Body of POST /auth/poi-reviews
+type PoiReviewRequest struct {
+	Xid    string `json:"xid" binding:"required,max=32"`
+	Rating int    `json:"rating" binding:"required,min=1,max=5"`
+	Review string `json:"review"` // Optional
+}
+
+// Body of PATCH /auth/poi-reviews/:id (Only the fields that are set are changed)
+type PoiReviewUpdate struct {
+	Rating *int    `json:"rating" binding:"omitempty,min=1,max=5"`
+	Review *string `json:"review"`
+}
+
+// Body of POST /auth/poi-reviews/:id/report
+type PoiReviewReport struct {
+	Reason string `json:"reason"` // Optional
+}
+
+// Average of the visible reviews of a POI (nil in responses until the POI has one)
+type PoiRating struct {
+	Average float64 `json:"average"` // Rounded to 1 decimal
+	Count   int     `json:"count"`
+}
+
+type PoiReviewView struct {
+	Id        uint      `json:"id"`
+	Xid       string    `json:"xid"`
+	Username  string    `json:"username"`
+	Rating    int       `json:"rating"`
+	Review    string    `json:"review"`
+	Hidden    bool      `json:"hidden"` // Only ever true on the user's own review
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Response of GET /auth/poi-reviews
+type PoiReviewsPage struct {
+	Xid          string          `json:"xid"`
+	UserRating   *PoiRating      `json:"user_rating"`
+	Distribution map[int]int     `json:"distribution"` // Stars -> Count of visible reviews
+	Own          *PoiReviewView  `json:"own"`          // The user's review (nil if they have not reviewed the POI)
+	Results      []PoiReviewView `json:"results"`
+	Page         int             `json:"page"`
+	PageSize     int             `json:"page_size"`
+	Total        int             `json:"total"`
+}
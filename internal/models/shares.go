@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Body of POST /auth/trips/:id/shares
+type TripShareRequest struct {
+	ExpiresAt *time.Time `json:"expires_at"` // RFC 3339 (Optional, never expires when missing)
+	Password  string     `json:"password"`   // Optional
+}
+
+type TripShareView struct {
+	Id           uint       `json:"id"`
+	Token        string     `json:"token,omitempty"` // Only returned when the share is created
+	Path         string     `json:"path,omitempty"`  // Public path of the link (Only returned when the share is created)
+	TokenHint    string     `json:"token_hint"`
+	HasPassword  bool       `json:"has_password"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+	Expired      bool       `json:"expired"`
+	Views        int        `json:"views"`
+	LastViewedAt *time.Time `json:"last_viewed_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// Response of GET /shared-trips/:token (Read only, without notes or ids of the owner)
+type SharedTrip struct {
+	Name      string         `json:"name"`
+	StartDate string         `json:"start_date"`
+	EndDate   string         `json:"end_date"`
+	Days      int            `json:"days"`
+	Cities    []TripCityView `json:"cities"`
+	Plan      []TripDay      `json:"plan"`
+	ExpiresAt *time.Time     `json:"expires_at"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import "github.com/MCantyDev/city-explorer-server/internal/geo"
+
+// Sights Search Parameters (Each combination is cached separately in city_sights)
+type SightsSearch struct {
+	Radius int      // Metres
+	Kinds  []string // OpenTripMap kinds (Sorted)
+	Rate   string   // Minimum OpenTripMap rating (1, 2, 3, 1h, 2h, 3h)
+}
+
+// Filtering and Pagination for the Sights endpoint (Applied to the cached search result)
+type SightsPageOptions struct {
+	Categories []string  // Our category ids (Empty matches everything)
+	Anchor     geo.Point // Distances are measured from here (Defaults to the search centre)
+	Sort       string    // services.SightsSort*
+	Offset     int
+	PageSize   int
+}
+
+// OpenTripMap GeoJSON Feature with our category ids
+type SightFeature struct {
+	Type       string          `json:"type"`
+	Id         string          `json:"id"`
+	Geometry   SightGeometry   `json:"geometry"`
+	Properties SightProperties `json:"properties"`
+}
+
+type SightGeometry struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"` // [lon, lat]
+}
+
+type SightProperties struct {
+	Xid        string     `json:"xid"`
+	Name       string     `json:"name"`
+	Dist       float64    `json:"dist"` // Metres from the search centre
+	Rate       int        `json:"rate"`
+	Osm        string     `json:"osm,omitempty"`
+	Wikidata   string     `json:"wikidata,omitempty"`
+	Kinds      string     `json:"kinds"`
+	Categories []string   `json:"categories"`
+	UserRating *PoiRating `json:"user_rating"` // From our users' reviews (nil without reviews)
+	geo.Leg               // From the anchor point
+}
+
+// GeoJSON FeatureCollection with pagination members
+type SightsPage struct {
+	Type       string         `json:"type"`
+	Features   []SightFeature `json:"features"`
+	Facets     map[string]int `json:"facets"` // Category id -> Count (Before the category filter)
+	Page       int            `json:"page"`
+	PageSize   int            `json:"page_size"`
+	Total      int            `json:"total"` // After the category filter
+	TotalPages int            `json:"total_pages"`
+	NextCursor string         `json:"next_cursor"` // Empty on the last page
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Trip Roles (Owners manage members, shares and deletion, editors change the plan, viewers read it)
+const (
+	TripRoleOwner  = "owner"
+	TripRoleEditor = "editor"
+	TripRoleViewer = "viewer"
+)
+
+// Trip Member Statuses (Declined invitations are deleted)
+const (
+	TripMemberPending  = "pending"
+	TripMemberAccepted = "accepted"
+)
+
+// Body of POST /auth/trips/:id/members (User is a username or email address)
+type TripInviteRequest struct {
+	User string `json:"user" binding:"required"`
+	Role string `json:"role" binding:"required,oneof=editor viewer"`
+}
+
+// Body of PATCH /auth/trips/:id/members/:userId
+type TripMemberUpdate struct {
+	Role string `json:"role" binding:"required,oneof=editor viewer"`
+}
+
+type TripMemberView struct {
+	UserId    uint      `json:"user_id"`
+	Username  string    `json:"username"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+	Role      string    `json:"role"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"` // When the invitation was sent
+}
+
+// Response item of GET /auth/trip-invitations
+type TripInvitationView struct {
+	TripId    uint      `json:"trip_id"`
+	TripName  string    `json:"trip_name"`
+	StartDate string    `json:"start_date"`
+	EndDate   string    `json:"end_date"`
+	Role      string    `json:"role"`
+	InvitedBy string    `json:"invited_by"` // Username, empty once that user is deleted
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,126 @@
+package models
+
+import "time"
+
+// Trip Item Kinds
+const (
+	TripItemPoi    = "poi"
+	TripItemCustom = "custom"
+)
+
+// Time Slots (Labels only, items are ordered by position)
+const (
+	TimeSlotAny       = "any"
+	TimeSlotMorning   = "morning"
+	TimeSlotAfternoon = "afternoon"
+	TimeSlotEvening   = "evening"
+	TimeSlotNight     = "night"
+)
+
+type TripCityRequest struct {
+	City        string `json:"city" binding:"required"`
+	CountryCode string `json:"country_code" binding:"required,len=2"`
+	Lat         string `json:"lat" binding:"required"`
+	Lon         string `json:"lon" binding:"required"`
+}
+
+// Body of POST /auth/trips (Dates are YYYY-MM-DD)
+type TripRequest struct {
+	Name      string            `json:"name" binding:"required"`
+	StartDate string            `json:"start_date" binding:"required"`
+	EndDate   string            `json:"end_date" binding:"required"`
+	Notes     string            `json:"notes"`
+	Cities    []TripCityRequest `json:"cities" binding:"dive"`
+}
+
+// Body of PATCH /auth/trips/:id (Only the fields that are set are changed, Cities replaces the list)
+type TripUpdate struct {
+	Name      *string            `json:"name"`
+	StartDate *string            `json:"start_date"`
+	EndDate   *string            `json:"end_date"`
+	Notes     *string            `json:"notes"`
+	Cities    *[]TripCityRequest `json:"cities" binding:"omitempty,dive"`
+}
+
+// Body of POST /auth/trips/:id/items (POIs use Xid, custom places use Name and optional Lat / Lon)
+type TripItemRequest struct {
+	Kind     string   `json:"kind" binding:"required,oneof=poi custom"`
+	Day      int      `json:"day" binding:"required,min=1"`
+	TimeSlot string   `json:"time_slot"`
+	Xid      string   `json:"xid"`
+	CityId   *uint    `json:"city_id"`
+	Name     string   `json:"name"`
+	Lat      *float64 `json:"lat"`
+	Lon      *float64 `json:"lon"`
+	Notes    string   `json:"notes"`
+}
+
+// Body of PATCH /auth/trips/:id/items/:itemId
+type TripItemUpdate struct {
+	Day      *int     `json:"day"`
+	TimeSlot *string  `json:"time_slot"`
+	CityId   *uint    `json:"city_id"`
+	Name     *string  `json:"name"`
+	Lat      *float64 `json:"lat"`
+	Lon      *float64 `json:"lon"`
+	Notes    *string  `json:"notes"`
+}
+
+// Body of PUT /auth/trips/:id/items/order (Every item in its new order, items can move between days)
+type TripOrderRequest struct {
+	Items []TripOrderItem `json:"items" binding:"required,dive"`
+}
+
+type TripOrderItem struct {
+	Id  uint `json:"id" binding:"required"`
+	Day int  `json:"day" binding:"required,min=1"`
+}
+
+type TripCityView struct {
+	Id          uint     `json:"id"`
+	Name        string   `json:"name"`
+	CountryCode string   `json:"country_code"`
+	Lat         *float64 `json:"lat"`
+	Lon         *float64 `json:"lon"`
+}
+
+type TripItemView struct {
+	Id       uint     `json:"id"`
+	Day      int      `json:"day"`
+	TimeSlot string   `json:"time_slot"`
+	Position int      `json:"position"`
+	Kind     string   `json:"kind"`
+	Xid      *string  `json:"xid"`
+	CityId   *uint    `json:"city_id"`
+	Name     string   `json:"name"`
+	Lat      *float64 `json:"lat"`
+	Lon      *float64 `json:"lon"`
+	Notes    string   `json:"notes"`
+}
+
+type TripDay struct {
+	Day      int            `json:"day"`
+	Date     string         `json:"date"`
+	CityId   *uint          `json:"city_id"`  // City the forecast is for
+	Forecast *WeatherDaily  `json:"forecast"` // nil outside the cached forecast window
+	Items    []TripItemView `json:"items"`
+}
+
+type TripSummary struct {
+	Id        uint           `json:"id"`
+	Name      string         `json:"name"`
+	StartDate string         `json:"start_date"`
+	EndDate   string         `json:"end_date"`
+	Days      int            `json:"days"`
+	Cities    []TripCityView `json:"cities"`
+	Role      string         `json:"role"`    // The current user's role (TripRole*)
+	Version   int            `json:"version"` // Also sent as the ETag of GET /auth/trips/:id
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+type TripView struct {
+	TripSummary
+	Notes string    `json:"notes"`
+	Plan  []TripDay `json:"plan"`
+}
@@ -0,0 +1,135 @@
+package models
+
+// Normalised Weather returned to the client (Independent of the weather provider schema)
+
+// Version 1 - Raw OpenWeather One Call shape (Legacy)
+// Version 2 - Normalised Weather
+const (
+	WeatherSchemaLegacy     = 1
+	WeatherSchemaNormalised = 2
+	WeatherSchemaVersion    = WeatherSchemaNormalised
+)
+
+const (
+	UnitsMetric   = "metric"
+	UnitsImperial = "imperial"
+)
+
+// Query options for the Weather endpoints
+type WeatherOptions struct {
+	SchemaVersion int
+	Units         string
+	Lang          string
+}
+
+type WeatherUnits struct {
+	Temperature   string `json:"temperature"`
+	WindSpeed     string `json:"wind_speed"`
+	Precipitation string `json:"precipitation"`
+	Pressure      string `json:"pressure"`
+}
+
+type WeatherCondition struct {
+	Id    int    `json:"id"`    // OpenWeather condition id (Used as the canonical condition code)
+	Code  string `json:"code"`  // Stable condition key (e.g. light_rain)
+	Group string `json:"group"` // Condition group (e.g. rain)
+	Text  string `json:"text"`  // Localised condition text
+	Icon  string `json:"icon"`
+}
+
+type WeatherWind struct {
+	Speed     float64 `json:"speed"`
+	Gust      float64 `json:"gust"`
+	Direction float64 `json:"direction"` // Degrees (Meteorological)
+}
+
+type WeatherCurrent struct {
+	Time        int64            `json:"time"`
+	Sunrise     int64            `json:"sunrise"`
+	Sunset      int64            `json:"sunset"`
+	Temperature float64          `json:"temperature"`
+	FeelsLike   float64          `json:"feels_like"`
+	Humidity    float64          `json:"humidity"`
+	Pressure    float64          `json:"pressure"`
+	CloudCover  float64          `json:"cloud_cover"`
+	UvIndex     float64          `json:"uv_index"`
+	Wind        WeatherWind      `json:"wind"`
+	Condition   WeatherCondition `json:"condition"`
+}
+
+type WeatherDaily struct {
+	Date                     int64            `json:"date"`
+	Sunrise                  int64            `json:"sunrise"`
+	Sunset                   int64            `json:"sunset"`
+	TemperatureMin           float64          `json:"temperature_min"`
+	TemperatureMax           float64          `json:"temperature_max"`
+	TemperatureDay           float64          `json:"temperature_day"`
+	FeelsLike                float64          `json:"feels_like"`
+	PrecipitationProbability float64          `json:"precipitation_probability"` // Percentage (0 - 100)
+	Precipitation            float64          `json:"precipitation"`
+	UvIndex                  float64          `json:"uv_index"`
+	Wind                     WeatherWind      `json:"wind"`
+	Condition                WeatherCondition `json:"condition"`
+}
+
+type WeatherHour struct {
+	Time                     int64            `json:"time"`
+	Temperature              float64          `json:"temperature"`
+	FeelsLike                float64          `json:"feels_like"`
+	Humidity                 float64          `json:"humidity"`
+	Pressure                 float64          `json:"pressure"`
+	CloudCover               float64          `json:"cloud_cover"`
+	UvIndex                  float64          `json:"uv_index"`
+	PrecipitationProbability float64          `json:"precipitation_probability"` // Percentage (0 - 100)
+	Precipitation            float64          `json:"precipitation"`
+	Wind                     WeatherWind      `json:"wind"`
+	Condition                WeatherCondition `json:"condition"`
+}
+
+type WeatherMinute struct {
+	Time          int64   `json:"time"`
+	Precipitation float64 `json:"precipitation"` // Rate per hour
+}
+
+type WeatherAlert struct {
+	Sender      string   `json:"sender"`
+	Event       string   `json:"event"`
+	Start       int64    `json:"start"`
+	End         int64    `json:"end"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+}
+
+// Shared fields for every Weather response
+type WeatherMeta struct {
+	SchemaVersion  int          `json:"schema_version"`
+	Units          string       `json:"units"`
+	UnitLabels     WeatherUnits `json:"unit_labels"`
+	Lang           string       `json:"lang"`
+	Lat            float64      `json:"lat"`
+	Lon            float64      `json:"lon"`
+	Timezone       string       `json:"timezone"`
+	TimezoneOffset float64      `json:"timezone_offset"`
+}
+
+type Weather struct {
+	WeatherMeta
+	Current WeatherCurrent `json:"current"`
+	Daily   []WeatherDaily `json:"daily"`
+}
+
+type WeatherHourly struct {
+	WeatherMeta
+	Hourly []WeatherHour `json:"hourly"`
+}
+
+type WeatherMinutely struct {
+	WeatherMeta
+	IntervalMinutes int             `json:"interval_minutes"` // 1 for OpenWeather, 15 for Open-Meteo
+	Minutely        []WeatherMinute `json:"minutely"`
+}
+
+type WeatherAlerts struct {
+	WeatherMeta
+	Alerts []WeatherAlert `json:"alerts"`
+}
@@ -1,6 +1,8 @@
 package routes
 
 import (
+	"time"
+
 	"github.com/MCantyDev/city-explorer-server/internal/handlers"
 	"github.com/MCantyDev/city-explorer-server/internal/middleware"
 	"github.com/gin-gonic/gin"
@@ -11,17 +13,65 @@ func SetupRoutes(router *gin.Engine) {
 	router.POST("/login", handlers.Login)
 	router.POST("/sign-up", handlers.SignUp)
 
+	// Public share links (Limited per IP to slow down password guessing)
+	router.GET("/shared-trips/:token", middleware.IPRateLimitMiddleware(30, time.Minute), handlers.GetSharedTrip)
+
 	// Grouping
 	auth := router.Group("/auth")
 	auth.Use(middleware.SessionAuthMiddleware())
 	{
 		auth.GET("/profile", handlers.GetProfile)
 		auth.GET("/logout", handlers.Logout)
+		auth.PATCH("/home-country", handlers.SetHomeCountry)
 		auth.GET("/get-country", handlers.GetCountry)
+		auth.GET("/get-country/neighbours", handlers.GetCountryNeighbours)
 		auth.GET("/get-cities", handlers.GetCities)
+		auth.GET("/autocomplete-cities", middleware.RateLimitMiddleware(20, 10*time.Second), handlers.AutocompleteCities)
 		auth.GET("/get-city-weather", handlers.GetWeather)
+		auth.GET("/get-city-weather-hourly", handlers.GetHourlyWeather)
+		auth.GET("/get-city-weather-minutely", handlers.GetMinutelyWeather)
+		auth.GET("/get-city-weather-alerts", handlers.GetWeatherAlerts)
+		auth.GET("/get-city-air-quality", handlers.GetAirQuality)
+		auth.GET("/get-city-climate", handlers.GetClimate)
+		auth.GET("/get-city-time", handlers.GetCityTime)
+		auth.GET("/suggest-itinerary", handlers.SuggestItinerary)
 		auth.GET("/get-city-sights", handlers.GetTravelDestinations)
 		auth.GET("/get-city-poi", handlers.GetTravelDestination)
+		auth.GET("/poi-categories", handlers.GetPoiCategories)
+		auth.GET("/poi-reviews", handlers.GetPoiReviews)
+		auth.POST("/poi-reviews", middleware.RateLimitMiddleware(10, time.Minute), handlers.AddPoiReview)
+		auth.PATCH("/poi-reviews/:id", handlers.EditPoiReview)
+		auth.DELETE("/poi-reviews/:id", handlers.DeletePoiReview)
+		auth.POST("/poi-reviews/:id/report", middleware.RateLimitMiddleware(10, time.Minute), handlers.ReportPoiReview)
+		auth.GET("/reverse-geocode", handlers.ReverseGeocode)
+		auth.GET("/convert-currency", handlers.ConvertCurrency)
+		auth.GET("/favourites", handlers.GetFavourites)
+		auth.GET("/favourites/export", handlers.ExportFavourites)
+		auth.GET("/favourites/:id", handlers.GetFavourite)
+		auth.POST("/favourites", handlers.AddFavourite)
+		auth.PATCH("/favourites/:id", handlers.EditFavourite)
+		auth.DELETE("/favourites/:id", handlers.DeleteFavourite)
+		auth.POST("/import-places", handlers.ImportPlaces)
+		auth.GET("/trips", handlers.GetTrips)
+		auth.GET("/trips/:id", handlers.GetTrip)
+		auth.GET("/trips/:id/export", handlers.ExportTrip)
+		auth.POST("/trips", handlers.AddTrip)
+		auth.PATCH("/trips/:id", handlers.EditTrip)
+		auth.DELETE("/trips/:id", handlers.DeleteTrip)
+		auth.POST("/trips/:id/items", handlers.AddTripItem)
+		auth.PUT("/trips/:id/items/order", handlers.ReorderTripItems)
+		auth.PATCH("/trips/:id/items/:itemId", handlers.EditTripItem)
+		auth.DELETE("/trips/:id/items/:itemId", handlers.DeleteTripItem)
+		auth.GET("/trips/:id/shares", handlers.GetTripShares)
+		auth.POST("/trips/:id/shares", handlers.AddTripShare)
+		auth.DELETE("/trips/:id/shares/:shareId", handlers.DeleteTripShare)
+		auth.GET("/trips/:id/members", handlers.GetTripMembers)
+		auth.POST("/trips/:id/members", handlers.AddTripMember)
+		auth.PATCH("/trips/:id/members/:userId", handlers.EditTripMember)
+		auth.DELETE("/trips/:id/members/:userId", handlers.DeleteTripMember)
+		auth.GET("/trip-invitations", handlers.GetTripInvitations)
+		auth.POST("/trip-invitations/:id/accept", handlers.AcceptTripInvitation)
+		auth.POST("/trip-invitations/:id/decline", handlers.DeclineTripInvitation)
 		// auth.GET("/check-admin-status", handlers.CheckAdminStatus)
 	}
 
@@ -32,18 +82,43 @@ func SetupRoutes(router *gin.Engine) {
 		admin.GET("/get-users", handlers.GetUsers)
 		admin.GET("/get-countries", handlers.GetCountries)
 		admin.GET("/get-city-weather", handlers.GetCityWeatherTable)
+		admin.GET("/get-city-weather-hourly", handlers.GetCityWeatherHourlyTable)
+		admin.GET("/get-city-weather-minutely", handlers.GetCityWeatherMinutelyTable)
+		admin.GET("/get-city-weather-alerts", handlers.GetCityWeatherAlertsTable)
+		admin.GET("/get-city-air-quality", handlers.GetCityAirQualityTable)
+		admin.GET("/get-city-weather-history", handlers.GetCityWeatherHistoryTable)
 		admin.GET("/get-city-sights", handlers.GetCitySightsTable)
 		admin.GET("/get-city-pois", handlers.GetCityPoisTable)
+		admin.GET("/get-reverse-geocodes", handlers.GetReverseGeocodesTable)
+		admin.GET("/get-autocomplete-cache", handlers.GetAutocompleteCacheTable)
+		admin.GET("/get-exchange-rates", handlers.GetExchangeRatesTable)
+		admin.GET("/get-poi-reviews", handlers.GetPoiReviewsTable)
 		admin.POST("/add-user", handlers.AddUser)
+		admin.POST("/preload-countries", handlers.PreloadCountries)
 		admin.PATCH("/edit-user", handlers.EditUser)
 		admin.PATCH("/refresh-country", handlers.RefreshCountry)
 		admin.PATCH("/refresh-city-weather", handlers.RefreshCityWeather)
+		admin.PATCH("/refresh-city-weather-hourly", handlers.RefreshCityWeatherHourly)
+		admin.PATCH("/refresh-city-weather-minutely", handlers.RefreshCityWeatherMinutely)
+		admin.PATCH("/refresh-city-weather-alerts", handlers.RefreshCityWeatherAlerts)
+		admin.PATCH("/refresh-city-air-quality", handlers.RefreshCityAirQuality)
 		admin.PATCH("/refresh-city-sights", handlers.RefreshCitySights)
 		admin.PATCH("/refresh-city-poi", handlers.RefreshCityPoi)
+		admin.PATCH("/hide-poi-review", handlers.HidePoiReview)
+		admin.PATCH("/flag-poi-review", handlers.FlagPoiReview)
 		admin.DELETE("/delete-user", handlers.DeleteUser)
 		admin.DELETE("/delete-country", handlers.DeleteCountry)
 		admin.DELETE("/delete-city-weather", handlers.DeleteCityWeather)
+		admin.DELETE("/delete-city-weather-hourly", handlers.DeleteCityWeatherHourly)
+		admin.DELETE("/delete-city-weather-minutely", handlers.DeleteCityWeatherMinutely)
+		admin.DELETE("/delete-city-weather-alerts", handlers.DeleteCityWeatherAlerts)
+		admin.DELETE("/delete-city-air-quality", handlers.DeleteCityAirQuality)
+		admin.DELETE("/delete-city-weather-history", handlers.DeleteCityWeatherHistory)
 		admin.DELETE("/delete-city-sights", handlers.DeleteCitySights)
 		admin.DELETE("/delete-city-poi", handlers.DeleteCityPoi)
+		admin.DELETE("/delete-reverse-geocode", handlers.DeleteReverseGeocode)
+		admin.DELETE("/delete-autocomplete-cache", handlers.DeleteAutocompleteCache)
+		admin.DELETE("/delete-exchange-rates", handlers.DeleteExchangeRates)
+		admin.DELETE("/delete-poi-review", handlers.RemovePoiReview)
 	}
 }
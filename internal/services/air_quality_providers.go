@@ -0,0 +1,207 @@
+package services
+
+/* Air Quality Providers
+
+- OpenWeather Air Pollution API (Requires OPENWEATHER_KEY)
+- Open-Meteo Air Quality API (Keyless, includes Pollen in Europe)
+- Every provider returns the normalised models.AirQuality
+- The configured provider is tried first, the other provider is used as a fallback
+*/
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/MCantyDev/city-explorer-server/internal/config"
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+)
+
+type AirQualityProvider interface {
+	Name() string
+	Available() bool // False if the provider is missing config (e.g. API Key)
+	FetchAirQuality(lat string, lon string) (*models.AirQuality, error)
+}
+
+type openWeatherAirProvider struct{}
+type openMeteoAirProvider struct{}
+
+var airQualityProviders = map[string]AirQualityProvider{
+	config.WeatherProviderOpenWeather: openWeatherAirProvider{},
+	config.WeatherProviderOpenMeteo:   openMeteoAirProvider{},
+}
+
+// FetchAirQuality - Fetches air quality from the configured provider, falling back to the other provider on failure
+func FetchAirQuality(lat string, lon string) (*models.AirQuality, error) {
+	var failures []string
+
+	for _, provider := range airQualityProviderOrder() {
+		if !provider.Available() {
+			continue
+		}
+
+		airQuality, err := provider.FetchAirQuality(lat, lon)
+		if err == nil {
+			return airQuality, nil
+		}
+
+		fmt.Printf("Air quality provider '%s' failed: %s\n", provider.Name(), err)
+		failures = append(failures, fmt.Sprintf("%s: %s", provider.Name(), err))
+	}
+
+	if len(failures) == 0 {
+		return nil, fmt.Errorf("no air quality provider is available")
+	}
+	return nil, fmt.Errorf("all air quality providers failed (%s)", strings.Join(failures, ", "))
+}
+
+// airQualityProviderOrder - Primary provider first, followed by the fallback
+func airQualityProviderOrder() []AirQualityProvider {
+	primary := config.Cfg.AirQuality.Provider
+	order := []AirQualityProvider{airQualityProviders[primary]}
+
+	for name, provider := range airQualityProviders {
+		if name != primary {
+			order = append(order, provider)
+		}
+	}
+	return order
+}
+
+// OpenWeather (No Pollen)
+
+func (openWeatherAirProvider) Name() string {
+	return config.Cfg.OpenWeatherAirAPI.Name
+}
+
+func (openWeatherAirProvider) Available() bool {
+	return config.Cfg.OpenWeatherAirAPI.Key != ""
+}
+
+func (openWeatherAirProvider) FetchAirQuality(lat string, lon string) (*models.AirQuality, error) {
+	api := config.Cfg.OpenWeatherAirAPI
+
+	url := fmt.Sprintf(api.URL, lat, lon, api.Key)
+	if !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("'%s' URL is not set up properly", api.Name)
+	}
+
+	data, err := FetchExternalAPI(url)
+	if err != nil {
+		return nil, err
+	}
+
+	var external models.OpenWeatherAirRequest
+	if err := json.Unmarshal(data, &external); err != nil {
+		return nil, fmt.Errorf("invalid JSON from %s: %s", api.Name, err)
+	}
+	if len(external.List) == 0 {
+		return nil, fmt.Errorf("%s returned no air quality data", api.Name)
+	}
+
+	latest := external.List[0]
+	return &models.AirQuality{
+		Provider: api.Name,
+		Lat:      external.Coord.Lat,
+		Lon:      external.Coord.Lon,
+		Time:     latest.Dt,
+		Aqi:      latest.Main.Aqi,
+		Category: airQualityCategory(latest.Main.Aqi),
+		Pollutants: models.AirQualityPollutants{
+			Pm25: latest.Components.Pm25,
+			Pm10: latest.Components.Pm10,
+			O3:   latest.Components.O3,
+			No2:  latest.Components.No2,
+			So2:  latest.Components.So2,
+			Co:   latest.Components.Co,
+		},
+	}, nil
+}
+
+// Open-Meteo
+
+func (openMeteoAirProvider) Name() string {
+	return config.Cfg.OpenMeteoAirAPI.Name
+}
+
+func (openMeteoAirProvider) Available() bool {
+	return true
+}
+
+func (openMeteoAirProvider) FetchAirQuality(lat string, lon string) (*models.AirQuality, error) {
+	api := config.Cfg.OpenMeteoAirAPI
+
+	url := fmt.Sprintf(api.URL, lat, lon)
+	if !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("'%s' URL is not set up properly", api.Name)
+	}
+
+	data, err := FetchExternalAPI(url)
+	if err != nil {
+		return nil, err
+	}
+
+	var external models.OpenMeteoAirRequest
+	if err := json.Unmarshal(data, &external); err != nil {
+		return nil, fmt.Errorf("invalid JSON from %s: %s", api.Name, err)
+	}
+
+	current := external.Current
+	aqi := europeanAqiLevel(current.EuropeanAqi)
+
+	airQuality := &models.AirQuality{
+		Provider: api.Name,
+		Lat:      external.Latitude,
+		Lon:      external.Longitude,
+		Time:     current.Time,
+		Aqi:      aqi,
+		Category: airQualityCategory(aqi),
+		Pollutants: models.AirQualityPollutants{
+			Pm25: current.Pm25,
+			Pm10: current.Pm10,
+			O3:   current.Ozone,
+			No2:  current.NitrogenDioxide,
+			So2:  current.SulphurDioxide,
+			Co:   current.CarbonMonoxide,
+		},
+	}
+
+	pollen := models.AirQualityPollen{
+		Alder:   current.AlderPollen,
+		Birch:   current.BirchPollen,
+		Grass:   current.GrassPollen,
+		Mugwort: current.MugwortPollen,
+		Olive:   current.OlivePollen,
+		Ragweed: current.RagweedPollen,
+	}
+	if pollen.Alder != nil || pollen.Birch != nil || pollen.Grass != nil || pollen.Mugwort != nil || pollen.Olive != nil || pollen.Ragweed != nil {
+		airQuality.Pollen = &pollen
+	}
+
+	return airQuality, nil
+}
+
+// europeanAqiLevel - Buckets the European AQI (0 - 100+) into the shared 1 - 5 scale (0 when missing, reported as "unknown")
+func europeanAqiLevel(europeanAqi *float64) int {
+	switch {
+	case europeanAqi == nil:
+		return 0
+	case *europeanAqi < 20:
+		return 1
+	case *europeanAqi < 40:
+		return 2
+	case *europeanAqi < 60:
+		return 3
+	case *europeanAqi < 80:
+		return 4
+	default:
+		return 5
+	}
+}
+
+func airQualityCategory(aqi int) string {
+	if aqi < 1 || aqi > len(models.AirQualityCategories) {
+		return "unknown"
+	}
+	return models.AirQualityCategories[aqi-1]
+}
@@ -4,10 +4,18 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 )
 
+const externalTimeout = 15 * time.Second
+
+// Client used for every External API call (Swapped out by SetupExternalClient when recording/replaying fixtures)
+var externalClient = &http.Client{
+	Timeout: externalTimeout,
+}
+
 func FetchExternalAPI(url string) ([]byte, error) {
-	resp, err := http.Get(url)
+	resp, err := externalClient.Get(url)
 	if err != nil {
 		return nil, err
 	}
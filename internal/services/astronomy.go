@@ -0,0 +1,96 @@
+package services
+
+/* Astronomy
+
+- Local time, sun and moon for a city on a date (Computed with the astro package, no external API)
+- Timezones come from the city (IANA, saved by the weather providers), then the country (single UTC offset), then the longitude
+*/
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/MCantyDev/city-explorer-server/internal/astro"
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+)
+
+// CityTimezone - Best known timezone for a city (and where it came from)
+func CityTimezone(city *models.City, country *models.Country, lon float64) (*time.Location, string) {
+	if city != nil && city.Timezone != "" {
+		if loc, err := time.LoadLocation(city.Timezone); err == nil {
+			return loc, models.TimezoneSourceCity
+		}
+	}
+
+	if country != nil {
+		if profile, err := ParseCountryProfile(country.Data); err == nil && len(profile.Timezones) == 1 {
+			if loc, ok := parseUTCOffset(profile.Timezones[0]); ok {
+				return loc, models.TimezoneSourceCountry
+			}
+		}
+	}
+
+	hours := int(math.Round(lon / 15))
+	return fixedZone(hours * 3600), models.TimezoneSourceSolar
+}
+
+// GetCityTime - Local time, sun and moon on a date ('date' is YYYY-MM-DD in the city's timezone, empty for today)
+func GetCityTime(loc *time.Location, source string, lat float64, lon float64, date string) (*models.CityTime, error) {
+	now := time.Now().In(loc)
+
+	day := now
+	if date != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", date, loc)
+		if err != nil {
+			return nil, fmt.Errorf("'date' must be in the format YYYY-MM-DD")
+		}
+		day = parsed
+	}
+
+	sun := astro.Sun(day, lat, lon)
+	noon := time.Date(day.Year(), day.Month(), day.Day(), 12, 0, 0, 0, loc)
+	minutes := int(sun.DayLength.Minutes())
+
+	return &models.CityTime{
+		Timezone:       loc.String(),
+		TimezoneSource: source,
+		LocalTime:      now.Truncate(time.Second),
+		UTCOffset:      now.Format("-07:00"),
+		Date:           day.Format("2006-01-02"),
+		Sun: models.CitySun{
+			SunTimes:         sun,
+			DayLength:        fmt.Sprintf("%dh%02dm", minutes/60, minutes%60),
+			DayLengthMinutes: minutes,
+		},
+		Moon: astro.Moon(noon),
+	}, nil
+}
+
+// parseUTCOffset - Parses Rest Countries offsets (e.g. UTC, UTC+05:30, UTC-03:00)
+func parseUTCOffset(offset string) (*time.Location, bool) {
+	if offset == "UTC" {
+		return time.UTC, true
+	}
+
+	parsed, err := time.Parse("UTC-07:00", offset)
+	if err != nil {
+		return nil, false
+	}
+	_, seconds := parsed.Zone()
+	return fixedZone(seconds), true
+}
+
+// fixedZone - Fixed offset zone named like Rest Countries (e.g. UTC+01:00)
+func fixedZone(seconds int) *time.Location {
+	if seconds == 0 {
+		return time.UTC
+	}
+
+	sign := "+"
+	if seconds < 0 {
+		sign = "-"
+	}
+	abs := int(math.Abs(float64(seconds)))
+	return time.FixedZone(fmt.Sprintf("UTC%s%02d:%02d", sign, abs/3600, abs%3600/60), seconds)
+}
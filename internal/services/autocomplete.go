@@ -0,0 +1,247 @@
+package services
+
+/* City Autocomplete
+
+- Prefixes are answered from (in order) the memory cache, the autocomplete_cache table, the local city index (cities table) and finally the geocoder
+- Geocoder results are saved to both caches, and the cities are added to the local index
+*/
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MCantyDev/city-explorer-server/internal/database"
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+)
+
+const (
+	MinAutocompletePrefix    = 2
+	MaxAutocompletePrefix    = 100
+	MaxAutocompleteLimit     = 10 // Results are cached at this size, smaller limits are sliced from it
+	autocompleteMemoryTTL    = 10 * time.Minute
+	autocompleteMemoryMax    = 5000 // Entries
+	autocompleteDatabaseDays = 7
+)
+
+// Autocomplete Sources
+const (
+	AutocompleteSourceMemory = "memory"
+	AutocompleteSourceCache  = "cache"
+	AutocompleteSourceLocal  = "local"
+)
+
+type autocompleteEntry struct {
+	Places []models.Place
+	Expiry time.Time
+}
+
+var (
+	autocompleteMemoryMutex sync.Mutex
+	autocompleteMemory      = map[string]autocompleteEntry{}
+)
+
+// ParseAutocomplete - Validates the autocomplete parameters (The prefix is lower cased and whitespace collapsed)
+func ParseAutocomplete(prefix string, limit string, lang string) (models.PlaceSearch, error) {
+	prefix = strings.ToLower(strings.Join(strings.Fields(prefix), " "))
+	if length := len([]rune(prefix)); length < MinAutocompletePrefix || length > MaxAutocompletePrefix {
+		return models.PlaceSearch{}, fmt.Errorf("'q' must be between %d and %d characters", MinAutocompletePrefix, MaxAutocompletePrefix)
+	}
+
+	search, err := ParsePlaceSearch(prefix, "", lang)
+	if err != nil {
+		return search, err
+	}
+	search.Limit = MaxAutocompleteLimit
+
+	if limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil || parsed < 1 || parsed > MaxAutocompleteLimit {
+			return search, fmt.Errorf("'limit' must be between 1 and %d", MaxAutocompleteLimit)
+		}
+		search.Limit = parsed
+	}
+
+	return search, nil
+}
+
+// AutocompleteCities - Suggests cities for a prefix
+func AutocompleteCities(search models.PlaceSearch) (*models.Autocomplete, error) {
+	key := search.Lang + ":" + search.Query
+
+	// Memory
+	if places, ok := autocompleteFromMemory(key); ok {
+		return autocompleteResult(search, AutocompleteSourceMemory, places), nil
+	}
+
+	// Database Cache
+	var cached models.AutocompleteCache
+	query := database.NewQueryBuilder("SELECT").Table("autocomplete_cache").Where("prefix = ?").Where("lang = ?").Build()
+	_, err := database.Execute(&cached, query, search.Query, search.Lang)
+	if err == nil && cached.Id > 0 && cached.ExpiryDate.After(time.Now()) {
+		var places []models.Place
+		if err := json.Unmarshal(cached.Data, &places); err == nil {
+			autocompleteToMemory(key, places)
+			return autocompleteResult(search, AutocompleteSourceCache, places), nil
+		}
+	}
+
+	// Local Index (Only used when it can fill the whole response)
+	local, err := searchLocalCities(search.Query, search.Limit)
+	if err == nil && len(local) >= search.Limit {
+		return autocompleteResult(search, AutocompleteSourceLocal, local), nil
+	}
+
+	// Geocoder
+	cacheSearch := search
+	cacheSearch.Limit = MaxAutocompleteLimit
+	places, err := SearchCities(cacheSearch)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(places)
+	if err != nil {
+		return nil, err
+	}
+	expiry := time.Now().AddDate(0, 0, autocompleteDatabaseDays)
+
+	if cached.Id > 0 {
+		query = database.NewQueryBuilder("UPDATE").Table("autocomplete_cache").Columns("data", "expiry_date").Where("id = ?").Build()
+		_, err = database.Execute(nil, query, data, expiry, cached.Id)
+	} else {
+		query = database.NewQueryBuilder("INSERT").Table("autocomplete_cache").Columns("prefix", "lang", "data", "expiry_date").Values(4).Build()
+		_, err = database.Execute(nil, query, search.Query, search.Lang, data, expiry)
+	}
+	if err != nil {
+		fmt.Printf("Failed to cache autocomplete prefix '%s': %s\n", search.Query, err)
+	}
+
+	autocompleteToMemory(key, places)
+	IndexCities(places)
+
+	return autocompleteResult(search, geocoder.Name(), places), nil
+}
+
+// ClearAutocompleteMemory - Empties the memory cache (e.g. after the database cache is changed)
+func ClearAutocompleteMemory() {
+	autocompleteMemoryMutex.Lock()
+	defer autocompleteMemoryMutex.Unlock()
+
+	autocompleteMemory = map[string]autocompleteEntry{}
+}
+
+// IndexCities - Adds geocoded cities to the local index (cities table), merging into the canonical city when it already exists
+func IndexCities(places []models.Place) {
+	for _, place := range places {
+		if place.OsmId == 0 {
+			continue
+		}
+
+		// Same OSM id, otherwise the same canonical key (Name, Country Code, Rounded Coordinates)
+		var city models.City
+		query := database.NewQueryBuilder("SELECT").Table("cities").Where("osm_id = ?").Build()
+		_, err := database.Execute(&city, query, place.OsmId)
+		if err == nil && city.Id == 0 {
+			query = database.NewQueryBuilder("SELECT").Table("cities").Where("name = ?").Where("country_code = ?").Where("lat_key = ROUND(?, 1)").Where("lon_key = ROUND(?, 1)").Build()
+			_, err = database.Execute(&city, query, place.Name, place.CountryCode, place.Lat, place.Lon)
+		}
+		if err != nil {
+			continue
+		}
+
+		var countryId *uint
+		if country, _ := GetCountry(place.CountryCode); country != nil {
+			countryId = &country.Id
+		}
+
+		if city.Id > 0 {
+			// Coordinates are kept so the canonical key does not change
+			query = database.NewQueryBuilder("UPDATE").Table("cities").Columns("state", "country_id", "osm_id", "population_rank").Where("id = ?").Build()
+			_, err = database.Execute(nil, query, place.State, countryId, place.OsmId, place.Rank, city.Id)
+		} else {
+			query = database.NewQueryBuilder("INSERT").Table("cities").Columns("name", "state", "country_code", "country_id", "lat", "lon", "osm_id", "population_rank").Values(8).Build()
+			_, err = database.Execute(nil, query, place.Name, place.State, place.CountryCode, countryId, place.Lat, place.Lon, place.OsmId, place.Rank)
+		}
+		if err != nil {
+			fmt.Printf("Failed to index city '%s': %s\n", place.Name, err)
+		}
+	}
+}
+
+// searchLocalCities - Indexed cities starting with the prefix (Largest first)
+func searchLocalCities(prefix string, limit int) ([]models.Place, error) {
+	var cities []models.City
+	query := database.NewQueryBuilder("SELECT").Table("cities").Where("name LIKE ?").Where("osm_id IS NOT NULL").OrderBy("population_rank DESC, name").Limit().Build()
+	_, err := database.Execute(&cities, query, escapeLike(prefix)+"%", limit)
+	if err != nil {
+		return nil, err
+	}
+
+	places := make([]models.Place, 0, len(cities))
+	for _, city := range cities {
+		place := models.Place{
+			Provider:    AutocompleteSourceLocal,
+			Name:        city.Name,
+			State:       city.State,
+			CountryCode: city.CountryCode,
+			Rank:        city.PopulationRank,
+		}
+		if city.Lat != nil && city.Lon != nil {
+			place.Lat = *city.Lat
+			place.Lon = *city.Lon
+		}
+		if city.OsmId != nil {
+			place.OsmId = *city.OsmId
+		}
+		places = append(places, place)
+	}
+	return places, nil
+}
+
+func autocompleteResult(search models.PlaceSearch, source string, places []models.Place) *models.Autocomplete {
+	return &models.Autocomplete{
+		Query:   search.Query,
+		Source:  source,
+		Results: places[:min(search.Limit, len(places))],
+	}
+}
+
+func autocompleteFromMemory(key string) ([]models.Place, bool) {
+	autocompleteMemoryMutex.Lock()
+	defer autocompleteMemoryMutex.Unlock()
+
+	entry, ok := autocompleteMemory[key]
+	if !ok || entry.Expiry.Before(time.Now()) {
+		return nil, false
+	}
+	return entry.Places, true
+}
+
+func autocompleteToMemory(key string, places []models.Place) {
+	autocompleteMemoryMutex.Lock()
+	defer autocompleteMemoryMutex.Unlock()
+
+	// Drop expired entries once full (Everything if still full)
+	if len(autocompleteMemory) >= autocompleteMemoryMax {
+		now := time.Now()
+		for k, entry := range autocompleteMemory {
+			if entry.Expiry.Before(now) {
+				delete(autocompleteMemory, k)
+			}
+		}
+		if len(autocompleteMemory) >= autocompleteMemoryMax {
+			autocompleteMemory = map[string]autocompleteEntry{}
+		}
+	}
+
+	autocompleteMemory[key] = autocompleteEntry{Places: places, Expiry: time.Now().Add(autocompleteMemoryTTL)}
+}
+
+// escapeLike - Escapes LIKE wildcards in user input
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
@@ -0,0 +1,328 @@
+package services
+
+/* Historical Weather and Climate Normals
+
+- Daily history for the last ClimateYears full years is fetched once per location and cached
+- Monthly normals and past-years summaries for a date range are computed from the cached history
+*/
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/MCantyDev/city-explorer-server/internal/config"
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+)
+
+// Number of full years used for normals and past-years summaries
+const ClimateYears = 10
+
+// Days with at least this much precipitation (mm) count as a precipitation day
+const precipitationDayThreshold = 1.0
+
+type HistoricalWeatherProvider interface {
+	Name() string
+	FetchHistory(lat string, lon string, startYear int, endYear int) (*models.WeatherHistory, error)
+}
+
+type openMeteoArchiveProvider struct{}
+
+var historicalWeatherProvider HistoricalWeatherProvider = openMeteoArchiveProvider{}
+
+// FetchWeatherHistory - Fetches daily weather history for full years (startYear - endYear inclusive)
+func FetchWeatherHistory(lat string, lon string, startYear int, endYear int) (*models.WeatherHistory, error) {
+	return historicalWeatherProvider.FetchHistory(lat, lon, startYear, endYear)
+}
+
+// ClimateYearRange - The full years available in the archive (The archive lags a few days behind, so a year is only used from the 10th of January)
+func ClimateYearRange(now time.Time) (int, int) {
+	endYear := now.Year() - 1
+	if now.Before(climateRolloverDate(now.Year())) {
+		endYear--
+	}
+	return endYear - ClimateYears + 1, endYear
+}
+
+// ClimateExpiry - History only expires once another full year is available
+func ClimateExpiry(now time.Time) time.Time {
+	rollover := climateRolloverDate(now.Year())
+	if now.Before(rollover) {
+		return rollover
+	}
+	return climateRolloverDate(now.Year() + 1)
+}
+
+func climateRolloverDate(year int) time.Time {
+	return time.Date(year, time.January, 10, 0, 0, 0, 0, time.UTC)
+}
+
+// Open-Meteo Archive
+
+func (openMeteoArchiveProvider) Name() string {
+	return config.Cfg.OpenMeteoArchiveAPI.Name
+}
+
+func (openMeteoArchiveProvider) FetchHistory(lat string, lon string, startYear int, endYear int) (*models.WeatherHistory, error) {
+	api := config.Cfg.OpenMeteoArchiveAPI
+
+	startDate := fmt.Sprintf("%d-01-01", startYear)
+	endDate := fmt.Sprintf("%d-12-31", endYear)
+
+	url := fmt.Sprintf(api.URL, lat, lon, startDate, endDate)
+	if !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("'%s' URL is not set up properly", api.Name)
+	}
+
+	data, err := FetchExternalAPI(url)
+	if err != nil {
+		return nil, err
+	}
+
+	var external models.OpenMeteoArchiveRequest
+	if err := json.Unmarshal(data, &external); err != nil {
+		return nil, fmt.Errorf("invalid JSON from %s: %s", api.Name, err)
+	}
+
+	// Sunshine is returned in seconds
+	sunshine := make([]*float64, len(external.Daily.SunshineDuration))
+	for i, seconds := range external.Daily.SunshineDuration {
+		if seconds != nil {
+			hours := *seconds / 3600
+			sunshine[i] = &hours
+		}
+	}
+
+	return &models.WeatherHistory{
+		Provider:       api.Name,
+		Lat:            external.Latitude,
+		Lon:            external.Longitude,
+		Timezone:       external.Timezone,
+		Dates:          external.Daily.Time,
+		TemperatureMax: external.Daily.TemperatureMax,
+		TemperatureMin: external.Daily.TemperatureMin,
+		Precipitation:  external.Daily.PrecipitationSum,
+		SunshineHours:  sunshine,
+	}, nil
+}
+
+// Climate Summaries
+
+// climateAccumulator - Running totals for a set of days (nil values are skipped)
+type climateAccumulator struct {
+	sumMax, sumMin, sumSunshine float64
+	countMax, countMin          int
+	countSunshine               int
+	highestMax, lowestMin       float64
+	precipitation               float64
+	precipitationDays           int
+	days                        int // Days of history found (Values included or not)
+}
+
+func newClimateAccumulator() *climateAccumulator {
+	return &climateAccumulator{highestMax: math.Inf(-1), lowestMin: math.Inf(1)}
+}
+
+func (a *climateAccumulator) add(history *models.WeatherHistory, i int) {
+	a.days++
+	if value := valueAt(history.TemperatureMax, i); value != nil {
+		a.sumMax += *value
+		a.countMax++
+		a.highestMax = math.Max(a.highestMax, *value)
+	}
+	if value := valueAt(history.TemperatureMin, i); value != nil {
+		a.sumMin += *value
+		a.countMin++
+		a.lowestMin = math.Min(a.lowestMin, *value)
+	}
+	if value := valueAt(history.Precipitation, i); value != nil {
+		a.precipitation += *value
+		if *value >= precipitationDayThreshold {
+			a.precipitationDays++
+		}
+	}
+	if value := valueAt(history.SunshineHours, i); value != nil {
+		a.sumSunshine += *value
+		a.countSunshine++
+	}
+}
+
+func (a *climateAccumulator) summary(imperial bool) models.ClimateSummary {
+	return models.ClimateSummary{
+		TemperatureMax:    convertTemperature(average(a.sumMax, a.countMax), imperial),
+		TemperatureMin:    convertTemperature(average(a.sumMin, a.countMin), imperial),
+		HighestMax:        convertTemperature(finite(a.highestMax), imperial),
+		LowestMin:         convertTemperature(finite(a.lowestMin), imperial),
+		Precipitation:     convertPrecipitation(a.precipitation, imperial),
+		PrecipitationDays: float64(a.precipitationDays),
+		SunshineHours:     round1(average(a.sumSunshine, a.countSunshine)),
+	}
+}
+
+// SummariseClimate - Computes monthly normals and (optionally) past-years summaries for a date range
+func SummariseClimate(history *models.WeatherHistory, startYear int, endYear int, options models.ClimateOptions) *models.Climate {
+	imperial := options.Units == models.UnitsImperial
+
+	climate := &models.Climate{
+		Provider:   history.Provider,
+		Units:      options.Units,
+		UnitLabels: unitLabels(imperial),
+		Lat:        history.Lat,
+		Lon:        history.Lon,
+		Timezone:   history.Timezone,
+		StartYear:  startYear,
+		EndYear:    endYear,
+		Normals:    climateNormals(history, imperial),
+	}
+
+	if options.HasRange {
+		climate.Range = climateRange(history, startYear, endYear, options, imperial)
+	}
+
+	return climate
+}
+
+// climateNormals - Averages every month across all years of history
+func climateNormals(history *models.WeatherHistory, imperial bool) []models.ClimateNormal {
+	// Per Month-of-Year, Per Year (So totals can be averaged across years)
+	months := make([]map[int]*climateAccumulator, 12)
+	for i := range months {
+		months[i] = map[int]*climateAccumulator{}
+	}
+
+	for i, date := range history.Dates {
+		day, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			continue
+		}
+
+		byYear := months[day.Month()-1]
+		if byYear[day.Year()] == nil {
+			byYear[day.Year()] = newClimateAccumulator()
+		}
+		byYear[day.Year()].add(history, i)
+	}
+
+	normals := make([]models.ClimateNormal, 12)
+	for i, byYear := range months {
+		month := newClimateAccumulator()
+		var totalPrecipitation float64
+		var totalPrecipitationDays int
+
+		for _, year := range byYear {
+			month.sumMax += year.sumMax
+			month.countMax += year.countMax
+			month.sumMin += year.sumMin
+			month.countMin += year.countMin
+			month.sumSunshine += year.sumSunshine
+			month.countSunshine += year.countSunshine
+			totalPrecipitation += year.precipitation
+			totalPrecipitationDays += year.precipitationDays
+		}
+
+		years := len(byYear)
+		normals[i] = models.ClimateNormal{
+			Month:             i + 1,
+			TemperatureMax:    convertTemperature(average(month.sumMax, month.countMax), imperial),
+			TemperatureMin:    convertTemperature(average(month.sumMin, month.countMin), imperial),
+			Precipitation:     convertPrecipitation(average(totalPrecipitation, years), imperial),
+			PrecipitationDays: round1(average(float64(totalPrecipitationDays), years)),
+			SunshineHours:     round1(average(month.sumSunshine, month.countSunshine)),
+		}
+	}
+
+	return normals
+}
+
+// climateRange - Summarises the same Month/Day range in every year of history (Ranges may wrap over the new year)
+func climateRange(history *models.WeatherHistory, startYear int, endYear int, options models.ClimateOptions, imperial bool) *models.ClimateRange {
+	index := make(map[string]int, len(history.Dates))
+	for i, date := range history.Dates {
+		index[date] = i
+	}
+
+	result := &models.ClimateRange{
+		Start: options.RangeStart.Format("01-02"),
+		End:   options.RangeEnd.Format("01-02"),
+		Years: []models.ClimateYear{},
+	}
+
+	// Totals of the yearly values before conversion (Years without a value are not counted)
+	overall := newClimateAccumulator()
+	var sumPrecipitation float64
+	var sumPrecipitationDays int
+	for year := startYear; year <= endYear; year++ {
+		start := time.Date(year, options.RangeStart.Month(), options.RangeStart.Day(), 0, 0, 0, 0, time.UTC)
+		end := time.Date(year, options.RangeEnd.Month(), options.RangeEnd.Day(), 0, 0, 0, 0, time.UTC)
+		if end.Before(start) {
+			end = end.AddDate(1, 0, 0)
+		}
+		if end.Year() > endYear {
+			continue // Range wraps into a year that is not in the history
+		}
+
+		accumulator := newClimateAccumulator()
+		for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+			if i, ok := index[day.Format("2006-01-02")]; ok {
+				accumulator.add(history, i)
+			}
+		}
+		if accumulator.days == 0 {
+			continue // Missing from the history
+		}
+
+		result.Years = append(result.Years, models.ClimateYear{
+			Year:           year,
+			Start:          start.Format("2006-01-02"),
+			End:            end.Format("2006-01-02"),
+			ClimateSummary: accumulator.summary(imperial),
+		})
+
+		if accumulator.countMax > 0 {
+			overall.sumMax += average(accumulator.sumMax, accumulator.countMax)
+			overall.countMax++
+			overall.highestMax = math.Max(overall.highestMax, accumulator.highestMax)
+		}
+		if accumulator.countMin > 0 {
+			overall.sumMin += average(accumulator.sumMin, accumulator.countMin)
+			overall.countMin++
+			overall.lowestMin = math.Min(overall.lowestMin, accumulator.lowestMin)
+		}
+		if accumulator.countSunshine > 0 {
+			overall.sumSunshine += average(accumulator.sumSunshine, accumulator.countSunshine)
+			overall.countSunshine++
+		}
+		sumPrecipitation += accumulator.precipitation
+		sumPrecipitationDays += accumulator.precipitationDays
+	}
+
+	if years := len(result.Years); years > 0 {
+		result.Average = models.ClimateSummary{
+			TemperatureMax:    convertTemperature(average(overall.sumMax, overall.countMax), imperial),
+			TemperatureMin:    convertTemperature(average(overall.sumMin, overall.countMin), imperial),
+			HighestMax:        convertTemperature(finite(overall.highestMax), imperial),
+			LowestMin:         convertTemperature(finite(overall.lowestMin), imperial),
+			Precipitation:     convertPrecipitation(average(sumPrecipitation, years), imperial),
+			PrecipitationDays: round1(average(float64(sumPrecipitationDays), years)),
+			SunshineHours:     round1(average(overall.sumSunshine, overall.countSunshine)),
+		}
+	}
+
+	return result
+}
+
+func average(sum float64, count int) float64 {
+	if count == 0 {
+		return 0
+	}
+	return sum / float64(count)
+}
+
+func finite(value float64) float64 {
+	if math.IsInf(value, 0) {
+		return 0
+	}
+	return value
+}
@@ -0,0 +1,335 @@
+package services
+
+/* Countries
+
+- Fetches countries from Rest Countries and caches them in the countries table (One Rest Countries object per row)
+- Parses the cached data into the typed models.CountryProfile
+- Resolves bordering countries into summaries (Fetching any missing neighbours in a single request)
+- Preloads the full country catalogue in batches (Admin endpoint and 'preload-countries' command)
+*/
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/MCantyDev/city-explorer-server/internal/config"
+	"github.com/MCantyDev/city-explorer-server/internal/database"
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+)
+
+// How long a cached country is valid for
+const countryTTLYears = 1
+
+// Countries fetched per List API request when preloading
+const countryPreloadBatch = 50
+
+// FetchCountry - Fetches a single country from Rest Countries (Alpha-2 or Alpha-3 code)
+func FetchCountry(code string) (json.RawMessage, error) {
+	countries, err := fetchRestCountries(config.Cfg.RestCountriesAPI, code)
+	if err != nil {
+		return nil, err
+	}
+	if len(countries) == 0 {
+		return nil, fmt.Errorf("%s returned no country for '%s'", config.Cfg.RestCountriesAPI.Name, code)
+	}
+
+	// Rest Countries always returns an array, even for a single code
+	return countries[0], nil
+}
+
+// SaveCountry - Inserts or updates the cached country (Matched on the Alpha-2 code)
+func SaveCountry(name string, data json.RawMessage) error {
+	var country models.RestCountry
+	if err := json.Unmarshal(data, &country); err != nil {
+		return fmt.Errorf("invalid country data: %s", err)
+	}
+
+	var existing models.Country
+	query := database.NewQueryBuilder("SELECT").Table("countries").Where("iso_code = ?").Build()
+	_, err := database.Execute(&existing, query, country.Cca2)
+	if err != nil {
+		return err
+	}
+
+	return saveCountry(&existing, name, &country, data)
+}
+
+// PreloadCountries - Fetches every country code from Rest Countries, then the countries in batches, and upserts them into the countries table
+func PreloadCountries() (*models.CountryPreloadReport, error) {
+	api := config.Cfg.RestCountriesAllAPI
+	if !strings.HasPrefix(api.URL, "https://") {
+		return nil, fmt.Errorf("'%s' URL is not properly setup", api.Name)
+	}
+
+	data, err := FetchExternalAPI(api.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	var codes []struct {
+		Cca2 string `json:"cca2"`
+	}
+	if err := json.Unmarshal(data, &codes); err != nil {
+		return nil, fmt.Errorf("invalid JSON from %s: %s", api.Name, err)
+	}
+
+	report := &models.CountryPreloadReport{
+		Inserted: []string{},
+		Updated:  []string{},
+		Failed:   []models.CountryPreloadFailure{},
+	}
+
+	// Full countries (The same data a single country fetch caches)
+	var countries models.RestCountriesRequest
+	for start := 0; start < len(codes); start += countryPreloadBatch {
+		batch := []string{}
+		for _, code := range codes[start:min(start+countryPreloadBatch, len(codes))] {
+			batch = append(batch, code.Cca2)
+		}
+
+		fetched, err := fetchRestCountries(config.Cfg.RestCountriesListAPI, strings.Join(batch, ","))
+		if err != nil {
+			for _, code := range batch {
+				report.Failed = append(report.Failed, models.CountryPreloadFailure{Code: code, Error: err.Error()})
+			}
+			continue
+		}
+		countries = append(countries, fetched...)
+	}
+	report.Fetched = len(countries)
+
+	// Load existing rows once rather than once per country
+	var cached []models.Country
+	query := database.NewQueryBuilder("SELECT").Table("countries").Build()
+	_, err = database.Execute(&cached, query)
+	if err != nil {
+		return nil, err
+	}
+	existing := map[string]*models.Country{}
+	for i := range cached {
+		existing[strings.ToUpper(cached[i].IsoCode)] = &cached[i]
+	}
+
+	for _, raw := range countries {
+		var country models.RestCountry
+		if err := json.Unmarshal(raw, &country); err != nil || country.Cca2 == "" {
+			report.Failed = append(report.Failed, models.CountryPreloadFailure{Code: country.Cca2, Error: "invalid country data"})
+			continue
+		}
+
+		row, ok := existing[country.Cca2]
+		if !ok {
+			row = &models.Country{}
+		}
+		changed := !ok || !sameJSON(row.Data, raw) || row.IsoCode3 != country.Cca3
+
+		// Unchanged rows are still saved so their expiry date is extended
+		if err := saveCountry(row, "", &country, raw); err != nil {
+			report.Failed = append(report.Failed, models.CountryPreloadFailure{Code: country.Cca2, Error: err.Error()})
+			continue
+		}
+
+		switch {
+		case !ok:
+			report.Inserted = append(report.Inserted, country.Cca2)
+		case changed:
+			report.Updated = append(report.Updated, country.Cca2)
+		default:
+			report.Unchanged++
+		}
+	}
+
+	return report, nil
+}
+
+// saveCountry - Updates the existing row (Id > 0) or inserts a new one (Name defaults to the common name)
+func saveCountry(existing *models.Country, name string, country *models.RestCountry, data json.RawMessage) error {
+	if name == "" {
+		name = country.Name.Common
+	}
+	expiry := time.Now().AddDate(countryTTLYears, 0, 0)
+
+	if existing.Id > 0 {
+		query := database.NewQueryBuilder("UPDATE").Table("countries").Columns("iso_code3", "data", "expiry_date").Where("id = ?").Build()
+		_, err := database.Execute(nil, query, country.Cca3, data, expiry, existing.Id)
+		return err
+	}
+
+	query := database.NewQueryBuilder("INSERT").Table("countries").Columns("name", "iso_code", "iso_code3", "data", "expiry_date").Values(5).Build()
+	_, err := database.Execute(nil, query, name, country.Cca2, country.Cca3, data, expiry)
+	return err
+}
+
+// sameJSON - Compares two JSON documents ignoring formatting and key order (MySQL reformats stored JSON)
+func sameJSON(a []byte, b []byte) bool {
+	var left, right any
+	if json.Unmarshal(a, &left) != nil || json.Unmarshal(b, &right) != nil {
+		return false
+	}
+	return reflect.DeepEqual(left, right)
+}
+
+// ParseCountryProfile - Parses cached Rest Countries data into the typed profile
+func ParseCountryProfile(data []byte) (*models.CountryProfile, error) {
+	var country models.RestCountry
+	if err := json.Unmarshal(data, &country); err != nil {
+		return nil, fmt.Errorf("invalid country data: %s", err)
+	}
+
+	profile := &models.CountryProfile{
+		SchemaVersion:   models.CountrySchemaProfile,
+		Code:            country.Cca2,
+		Code3:           country.Cca3,
+		Name:            country.Name.Common,
+		OfficialName:    country.Name.Official,
+		Capital:         nonNil(country.Capital),
+		Region:          country.Region,
+		Subregion:       country.Subregion,
+		Population:      country.Population,
+		Area:            country.Area,
+		Currencies:      []models.CountryCurrency{},
+		Languages:       []models.CountryLanguage{},
+		CallingCodes:    []string{},
+		Timezones:       nonNil(country.Timezones),
+		DrivingSide:     country.Car.Side,
+		Borders:         nonNil(country.Borders),
+		TopLevelDomains: nonNil(country.Tld),
+		Flag: models.CountryFlag{
+			Png: country.Flags.Png,
+			Svg: country.Flags.Svg,
+			Alt: country.Flags.Alt,
+		},
+	}
+	if len(country.Latlng) == 2 {
+		profile.Lat = country.Latlng[0]
+		profile.Lon = country.Latlng[1]
+	}
+
+	// Maps are sorted by code so the output is stable
+	for code, currency := range country.Currencies {
+		profile.Currencies = append(profile.Currencies, models.CountryCurrency{Code: code, Name: currency.Name, Symbol: currency.Symbol})
+	}
+	sort.Slice(profile.Currencies, func(i, j int) bool { return profile.Currencies[i].Code < profile.Currencies[j].Code })
+
+	for code, name := range country.Languages {
+		profile.Languages = append(profile.Languages, models.CountryLanguage{Code: code, Name: name})
+	}
+	sort.Slice(profile.Languages, func(i, j int) bool { return profile.Languages[i].Code < profile.Languages[j].Code })
+
+	// Calling codes are split into a root (+3) and suffixes (3) - Countries with many suffixes (e.g. the US) share a single root code
+	switch {
+	case country.Idd.Root == "":
+	case len(country.Idd.Suffixes) == 1:
+		profile.CallingCodes = append(profile.CallingCodes, country.Idd.Root+country.Idd.Suffixes[0])
+	case len(country.Idd.Suffixes) > 1 && len(country.Idd.Suffixes) <= 5:
+		for _, suffix := range country.Idd.Suffixes {
+			profile.CallingCodes = append(profile.CallingCodes, country.Idd.Root+suffix)
+		}
+	default:
+		profile.CallingCodes = append(profile.CallingCodes, country.Idd.Root)
+	}
+
+	return profile, nil
+}
+
+// CountrySummary - Short summary of a profile
+func CountrySummary(profile *models.CountryProfile) models.CountrySummary {
+	return models.CountrySummary{
+		Code:       profile.Code,
+		Code3:      profile.Code3,
+		Name:       profile.Name,
+		Capital:    profile.Capital,
+		Region:     profile.Region,
+		Population: profile.Population,
+		Flag:       profile.Flag,
+	}
+}
+
+// GetNeighbours - Summaries of the bordering countries (From the cache, missing or expired neighbours are fetched and cached)
+func GetNeighbours(profile *models.CountryProfile) ([]models.CountrySummary, error) {
+	neighbours := []models.CountrySummary{}
+	if len(profile.Borders) == 0 {
+		return neighbours, nil
+	}
+
+	var cached []models.Country
+	query := database.NewQueryBuilder("SELECT").Table("countries").Where("iso_code3 IN ?").Build()
+	_, err := database.Execute(&cached, query, profile.Borders)
+	if err != nil {
+		return nil, err
+	}
+
+	found := map[string]json.RawMessage{}
+	for _, country := range cached {
+		if country.ExpiryDate.After(time.Now()) {
+			found[strings.ToUpper(country.IsoCode3)] = country.Data
+		}
+	}
+
+	var missing []string
+	for _, code := range profile.Borders {
+		if _, ok := found[code]; !ok {
+			missing = append(missing, code)
+		}
+	}
+
+	if len(missing) > 0 {
+		fetched, err := fetchRestCountries(config.Cfg.RestCountriesListAPI, strings.Join(missing, ","))
+		if err != nil {
+			return nil, err
+		}
+		for _, data := range fetched {
+			if err := SaveCountry("", data); err != nil {
+				fmt.Printf("Failed to cache neighbouring country: %s\n", err)
+			}
+			var country models.RestCountry
+			if err := json.Unmarshal(data, &country); err == nil {
+				found[country.Cca3] = data
+			}
+		}
+	}
+
+	// Same order as the borders
+	for _, code := range profile.Borders {
+		data, ok := found[code]
+		if !ok {
+			continue
+		}
+		neighbour, err := ParseCountryProfile(data)
+		if err != nil {
+			continue
+		}
+		neighbours = append(neighbours, CountrySummary(neighbour))
+	}
+
+	return neighbours, nil
+}
+
+func fetchRestCountries(api config.ExternalAPI, codes string) ([]json.RawMessage, error) {
+	url := fmt.Sprintf(api.URL, codes)
+	if !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("'%s' URL is not properly setup", api.Name)
+	}
+
+	data, err := FetchExternalAPI(url)
+	if err != nil {
+		return nil, err
+	}
+
+	var countries models.RestCountriesRequest
+	if err := json.Unmarshal(data, &countries); err != nil {
+		return nil, fmt.Errorf("invalid JSON from %s: %s", api.Name, err)
+	}
+	return countries, nil
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
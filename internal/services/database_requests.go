@@ -1,28 +1,89 @@
 package services
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/MCantyDev/city-explorer-server/internal/database"
+	"github.com/MCantyDev/city-explorer-server/internal/geo"
 	"github.com/MCantyDev/city-explorer-server/internal/models"
 )
 
-func GetOrCreateCity(name string) (*models.City, error) {
-	var city models.City
+// Cities within this many degrees of each other (and with the same name and country) are the same city
+const cityMatchDegrees = 0.25
+
+// GetOrCreateCity - Finds the canonical city by name, country and location (Homonyms like Paris, FR and Paris, TX are separate cities)
+func GetOrCreateCity(name string, country *models.Country, lat string, lon string) (*models.City, error) {
+	point, err := geo.ParsePoint(lat, lon)
+	if err != nil {
+		return nil, err
+	}
 
-	query := database.NewQueryBuilder("SELECT").Table("cities").Where("name = ?").Build()
-	_, err := database.Execute(&city, query, name)
-	if err == nil && city.Id > 0 {
-		return &city, nil
+	found, err := FindCity(name, country.IsoCode, point)
+	if err != nil {
+		return nil, err
+	}
+	if found != nil {
+		// Cities indexed before their country was fetched
+		if found.CountryId == nil {
+			query := database.NewQueryBuilder("UPDATE").Table("cities").Columns("country_id").Where("id = ?").Build()
+			_, err = database.Execute(nil, query, country.Id, found.Id)
+			found.CountryId = &country.Id
+		}
+		return found, err
 	}
 
-	city = models.City{Name: name}
+	city := models.City{
+		Name:        name,
+		CountryCode: strings.ToUpper(country.IsoCode),
+		CountryId:   &country.Id,
+		Lat:         &point.Lat,
+		Lon:         &point.Lon,
+	}
 	_, err = database.Execute(&city, "INSERT")
 	if err != nil {
+		// Another request created the city since the lookup above
+		if database.IsDuplicateKey(err) {
+			found, err := FindCity(name, country.IsoCode, point)
+			if err == nil && found == nil {
+				err = fmt.Errorf("city '%s' exists but could not be found", name)
+			}
+			return found, err
+		}
+		return nil, err
+	}
+
+	return &city, nil
+}
+
+// FindCity - The closest matching canonical city, without creating it (nil if the city is not known)
+func FindCity(name string, countryCode string, point geo.Point) (*models.City, error) {
+	var city models.City
+
+	query := database.NewQueryBuilder("SELECT").Table("cities").
+		Where("name = ?").Where("country_code = ?").Where("ABS(lat - ?) < ?").Where("ABS(lon - ?) < ?").
+		OrderBy("ABS(lat - ?) + ABS(lon - ?)").Limit().Build()
+	_, err := database.Execute(&city, query, name, countryCode, point.Lat, cityMatchDegrees, point.Lon, cityMatchDegrees, point.Lat, point.Lon, 1)
+	if err != nil || city.Id == 0 {
 		return nil, err
 	}
 
 	return &city, nil
 }
 
+// SetCityTimezone - Records the timezone reported by a weather provider (Only if not already known)
+func SetCityTimezone(cityId uint, timezone string) {
+	if timezone == "" {
+		return
+	}
+
+	query := database.NewQueryBuilder("UPDATE").Table("cities").Columns("timezone").Where("id = ?").Where("timezone = ''").Build()
+	_, err := database.Execute(nil, query, timezone, cityId)
+	if err != nil {
+		fmt.Printf("Failed to set timezone for city %d: %s\n", cityId, err)
+	}
+}
+
 func GetCountry(code string) (*models.Country, error) {
 	var country models.Country
 
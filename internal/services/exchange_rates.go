@@ -0,0 +1,248 @@
+package services
+
+/* Exchange Rates
+
+- European Central Bank daily reference rates (XML, Keyless, EUR based)
+- Fixture stand-in (Static rates file in FIXTURES_DIR, for offline development and CI)
+- Rates are cached per provider and publication date (The ECB 'time' attribute) in the exchange_rates table
+- The provider is checked again once the cached rates are ExchangeRatesRefresh old
+- If the provider fails, the most recent cached rates are used instead
+*/
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/MCantyDev/city-explorer-server/internal/config"
+	"github.com/MCantyDev/city-explorer-server/internal/database"
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+)
+
+type ExchangeRateProvider interface {
+	Name() string
+	FetchRates() (*models.ExchangeRates, error)
+}
+
+type ecbRatesProvider struct{}
+type fixtureRatesProvider struct{}
+
+var exchangeRateProviders = map[string]ExchangeRateProvider{
+	config.ExchangeRateProviderECB:     ecbRatesProvider{},
+	config.ExchangeRateProviderFixture: fixtureRatesProvider{},
+}
+
+// File read by the fixture provider (Relative to FIXTURES_DIR)
+const exchangeRatesFixture = "exchange_rates.json"
+
+// How long fetched rates are used before the provider is checked for newer ones (The ECB publishes once a working day)
+const ExchangeRatesRefresh = 6 * time.Hour
+
+var ErrUnknownCurrency = errors.New("unknown currency")
+
+// ParseCurrencyCode - Validates an ISO 4217 currency code (Returned upper case)
+func ParseCurrencyCode(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 {
+		return "", fmt.Errorf("currency must be an ISO 4217 code (e.g. EUR)")
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return "", fmt.Errorf("currency must be an ISO 4217 code (e.g. EUR)")
+		}
+	}
+	return code, nil
+}
+
+// GetExchangeRates - The latest rates from the configured provider (Cached by publication date, falls back to the latest cached rates)
+func GetExchangeRates() (*models.ExchangeRates, error) {
+	provider := exchangeRateProviders[config.Cfg.ExchangeRates.Provider]
+
+	// Rates fetched recently are used as they are (Weekends and holidays have no new rates)
+	var cached models.ExchangeRate
+	query := database.NewQueryBuilder("SELECT").Table("exchange_rates").Where("provider = ?").OrderBy("updated_at DESC").Limit().Build()
+	_, err := database.Execute(&cached, query, provider.Name(), 1)
+	if err == nil && cached.Id > 0 && time.Since(cached.UpdatedAt) < ExchangeRatesRefresh {
+		var rates models.ExchangeRates
+		if err := json.Unmarshal(cached.Data, &rates); err == nil {
+			return &rates, nil
+		}
+	}
+
+	rates, err := provider.FetchRates()
+	if err != nil {
+		fmt.Printf("Exchange rate provider '%s' failed: %s\n", provider.Name(), err)
+		return latestExchangeRates(provider.Name(), err)
+	}
+
+	if err := saveExchangeRates(provider.Name(), rates); err != nil {
+		fmt.Printf("Failed to cache exchange rates: %s\n", err)
+	}
+
+	return rates, nil
+}
+
+// saveExchangeRates - Inserts or refreshes the row for the provider and the date the rates were published
+func saveExchangeRates(provider string, rates *models.ExchangeRates) error {
+	data, err := json.Marshal(rates)
+	if err != nil {
+		return err
+	}
+
+	rateDate := rates.Date
+	if _, err := time.Parse("2006-01-02", rateDate); err != nil {
+		rateDate = time.Now().UTC().Format("2006-01-02")
+	}
+
+	var existing models.ExchangeRate
+	query := database.NewQueryBuilder("SELECT").Table("exchange_rates").Columns("id").Where("rate_date = ?").Where("provider = ?").Build()
+	if _, err := database.Execute(&existing, query, rateDate, provider); err != nil {
+		return err
+	}
+
+	// updated_at is set explicitly, it marks when the provider was last checked even if the rates are unchanged
+	if existing.Id > 0 {
+		query = database.NewQueryBuilder("UPDATE").Table("exchange_rates").Columns("base", "data", "updated_at").Where("id = ?").Build()
+		_, err = database.Execute(nil, query, rates.Base, data, time.Now(), existing.Id)
+		return err
+	}
+
+	query = database.NewQueryBuilder("INSERT").Table("exchange_rates").Columns("rate_date", "provider", "base", "data").Values(4).Build()
+	_, err = database.Execute(nil, query, rateDate, provider, rates.Base, data)
+	return err
+}
+
+// ConvertCurrency - Converts an amount between two currencies using today's rates
+func ConvertCurrency(amount float64, from string, to string) (*models.CurrencyConversion, error) {
+	rates, err := GetExchangeRates()
+	if err != nil {
+		return nil, err
+	}
+
+	fromRate, ok := rates.Rates[from]
+	if !ok {
+		return nil, fmt.Errorf("%w '%s'", ErrUnknownCurrency, from)
+	}
+	toRate, ok := rates.Rates[to]
+	if !ok {
+		return nil, fmt.Errorf("%w '%s'", ErrUnknownCurrency, to)
+	}
+
+	// Both rates are relative to the base currency
+	rate := toRate / fromRate
+
+	return &models.CurrencyConversion{
+		Amount:    amount,
+		From:      from,
+		To:        to,
+		Rate:      math.Round(rate*1e6) / 1e6,
+		Result:    math.Round(amount*rate*100) / 100,
+		RatesDate: rates.Date,
+		Provider:  rates.Provider,
+	}, nil
+}
+
+// CountryCurrency - The first of a country's currencies with an exchange rate (Countries like Panama also use USD)
+func CountryCurrency(currencies []models.CountryCurrency) (string, error) {
+	rates, err := GetExchangeRates()
+	if err != nil {
+		return "", err
+	}
+
+	codes := []string{}
+	for _, currency := range currencies {
+		if _, ok := rates.Rates[currency.Code]; ok {
+			return currency.Code, nil
+		}
+		codes = append(codes, currency.Code)
+	}
+	return "", fmt.Errorf("%w: no exchange rate for %s, submit 'from' or 'to' instead", ErrUnknownCurrency, strings.Join(codes, ", "))
+}
+
+// latestExchangeRates - Most recent cached rates for a provider (Used when the provider is unavailable)
+func latestExchangeRates(provider string, cause error) (*models.ExchangeRates, error) {
+	var cached models.ExchangeRate
+	query := database.NewQueryBuilder("SELECT").Table("exchange_rates").Where("provider = ?").OrderBy("rate_date DESC").Limit().Build()
+	_, err := database.Execute(&cached, query, provider, 1)
+	if err != nil || cached.Id == 0 {
+		return nil, fmt.Errorf("no exchange rates available (%s)", cause)
+	}
+
+	var rates models.ExchangeRates
+	if err := json.Unmarshal(cached.Data, &rates); err != nil {
+		return nil, fmt.Errorf("invalid cached exchange rates: %s", err)
+	}
+	return &rates, nil
+}
+
+// European Central Bank
+
+func (ecbRatesProvider) Name() string {
+	return config.ExchangeRateProviderECB
+}
+
+func (ecbRatesProvider) FetchRates() (*models.ExchangeRates, error) {
+	api := config.Cfg.ECBRatesAPI
+	if !strings.HasPrefix(api.URL, "https://") {
+		return nil, fmt.Errorf("'%s' URL is not properly setup", api.Name)
+	}
+
+	data, err := FetchExternalAPI(api.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	var externalData models.ECBRatesRequest
+	if err := xml.Unmarshal(data, &externalData); err != nil {
+		return nil, fmt.Errorf("invalid XML from %s: %s", api.Name, err)
+	}
+	if len(externalData.Cube.Cube.Rates) == 0 {
+		return nil, fmt.Errorf("%s returned no rates", api.Name)
+	}
+
+	// The ECB does not list the base currency itself
+	rates := &models.ExchangeRates{
+		Provider: config.ExchangeRateProviderECB,
+		Base:     "EUR",
+		Date:     externalData.Cube.Cube.Time,
+		Rates:    map[string]float64{"EUR": 1},
+	}
+	for _, rate := range externalData.Cube.Cube.Rates {
+		rates.Rates[rate.Currency] = rate.Rate
+	}
+
+	return rates, nil
+}
+
+// Fixture Stand-in (FIXTURES_DIR/exchange_rates.json)
+
+func (fixtureRatesProvider) Name() string {
+	return config.ExchangeRateProviderFixture
+}
+
+func (fixtureRatesProvider) FetchRates() (*models.ExchangeRates, error) {
+	path := filepath.Join(config.Cfg.Fixtures.Dir, exchangeRatesFixture)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("no exchange rates fixture at '%s'", path)
+	}
+
+	var rates models.ExchangeRates
+	if err := json.Unmarshal(data, &rates); err != nil {
+		return nil, fmt.Errorf("invalid exchange rates fixture '%s': %s", path, err)
+	}
+	if rates.Base == "" || len(rates.Rates) == 0 {
+		return nil, fmt.Errorf("exchange rates fixture '%s' has no base or rates", path)
+	}
+
+	rates.Provider = config.ExchangeRateProviderFixture
+	rates.Rates[rates.Base] = 1
+	return &rates, nil
+}
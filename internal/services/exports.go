@@ -0,0 +1,67 @@
+package services
+
+/* Exports
+
+- Converts trips and favourites into export documents (Rendered as GPX, KML, GeoJSON or iCalendar by the export package)
+*/
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/MCantyDev/city-explorer-server/internal/export"
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+)
+
+// TripDocument - A trip's items in plan order (Each item is dated by its day)
+func TripDocument(trip *models.TripView, generated time.Time) export.Document {
+	document := export.Document{
+		Id:          fmt.Sprintf("trip-%d", trip.Id),
+		Name:        trip.Name,
+		Description: trip.Notes,
+		Generated:   generated,
+		Places:      []export.Place{},
+	}
+
+	for _, day := range trip.Plan {
+		for _, item := range day.Items {
+			document.Places = append(document.Places, export.Place{
+				Id:       fmt.Sprintf("item-%d", item.Id),
+				Name:     item.Name,
+				Notes:    item.Notes,
+				Kind:     item.Kind,
+				Xid:      deref(item.Xid),
+				Lat:      item.Lat,
+				Lon:      item.Lon,
+				Day:      day.Day,
+				Date:     day.Date,
+				TimeSlot: item.TimeSlot,
+			})
+		}
+	}
+	return document
+}
+
+// FavouritesDocument - The user's favourites (Undated, so they cannot be exported as a calendar)
+func FavouritesDocument(favourites []models.FavouriteView, generated time.Time) export.Document {
+	document := export.Document{
+		Id:        "favourites",
+		Name:      "Favourites",
+		Generated: generated,
+		Places:    []export.Place{},
+	}
+
+	for _, favourite := range favourites {
+		document.Places = append(document.Places, export.Place{
+			Id:    fmt.Sprintf("favourite-%d", favourite.Id),
+			Name:  favourite.Name,
+			Notes: favourite.Notes,
+			Kind:  favourite.Kind,
+			Xid:   deref(favourite.Xid),
+			Lat:   favourite.Lat,
+			Lon:   favourite.Lon,
+			Tags:  favourite.Tags,
+		})
+	}
+	return document
+}
@@ -0,0 +1,297 @@
+package services
+
+/* Favourites
+
+- Saved cities, POIs (by xid) and custom places (by coordinates) per user, with notes and tags
+- Listing joins a cheap summary from the caches (Latest city_weather temperature, cached city_pois name and kinds)
+*/
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/MCantyDev/city-explorer-server/internal/database"
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+)
+
+const (
+	MaxFavouriteTags      = 10
+	MaxFavouriteTagLength = 30
+	MaxFavouriteNotes     = 2000
+	MaxFavouriteName      = 255
+)
+
+var (
+	ErrFavouriteNotFound = errors.New("favourite not found")
+	ErrFavouriteExists   = errors.New("already saved as a favourite")
+	ErrInvalidFavourite  = errors.New("invalid favourite")
+)
+
+// NormaliseTags - Lower case, trimmed and deduplicated tags (Order is kept)
+func NormaliseTags(tags []string) ([]string, error) {
+	normalised := []string{}
+	seen := map[string]bool{}
+
+	for _, tag := range tags {
+		tag = strings.Join(strings.Fields(strings.ToLower(tag)), " ")
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > MaxFavouriteTagLength {
+			return nil, fmt.Errorf("%w: tags must be at most %d characters", ErrInvalidFavourite, MaxFavouriteTagLength)
+		}
+		seen[tag] = true
+		normalised = append(normalised, tag)
+	}
+
+	if len(normalised) > MaxFavouriteTags {
+		return nil, fmt.Errorf("%w: at most %d tags are allowed", ErrInvalidFavourite, MaxFavouriteTags)
+	}
+	return normalised, nil
+}
+
+// ValidateFavouriteText - Checks the name and notes lengths
+func ValidateFavouriteText(name string, notes string) error {
+	if utf8.RuneCountInString(name) > MaxFavouriteName {
+		return fmt.Errorf("%w: name must be at most %d characters", ErrInvalidFavourite, MaxFavouriteName)
+	}
+	if utf8.RuneCountInString(notes) > MaxFavouriteNotes {
+		return fmt.Errorf("%w: notes must be at most %d characters", ErrInvalidFavourite, MaxFavouriteNotes)
+	}
+	return nil
+}
+
+// IsFavouriteKind - True for city, poi and place
+func IsFavouriteKind(kind string) bool {
+	return kind == models.FavouriteCity || kind == models.FavouritePoi || kind == models.FavouritePlace
+}
+
+// GetCachedPoi - Most recently cached POI for an xid (nil if it has never been fetched)
+func GetCachedPoi(xid string) *models.CityPoi {
+	var poi models.CityPoi
+	query := database.NewQueryBuilder("SELECT").Table("city_pois").Where("xid = ?").OrderBy("updated_at DESC").Limit().Build()
+	_, err := database.Execute(&poi, query, xid, 1)
+	if err != nil || poi.Id == 0 {
+		return nil
+	}
+	return &poi
+}
+
+// ListFavourites - A user's favourites, newest first (Optionally filtered by kind and tag)
+func ListFavourites(userId uint, kind string, tag string) ([]models.FavouriteView, error) {
+	builder := favouriteQuery().Where("favourites.user_id = ?")
+	args := []any{userId}
+
+	if kind != "" {
+		builder.Where("favourites.kind = ?")
+		args = append(args, kind)
+	}
+	if tag != "" {
+		builder.Where("JSON_CONTAINS(favourites.tags, JSON_QUOTE(?))")
+		args = append(args, strings.ToLower(strings.TrimSpace(tag)))
+	}
+
+	var favourites []models.Favourite
+	_, err := database.Execute(&favourites, builder.OrderBy("favourites.created_at DESC, favourites.id DESC").Build(), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]models.FavouriteView, 0, len(favourites))
+	for _, favourite := range favourites {
+		views = append(views, favouriteView(favourite))
+	}
+	return views, nil
+}
+
+// GetFavourite - A single favourite owned by the user
+func GetFavourite(userId uint, id uint) (*models.FavouriteView, error) {
+	var favourite models.Favourite
+	query := favouriteQuery().Where("favourites.user_id = ?").Where("favourites.id = ?").Build()
+	_, err := database.Execute(&favourite, query, userId, id)
+	if err != nil {
+		return nil, err
+	}
+	if favourite.Id == 0 {
+		return nil, ErrFavouriteNotFound
+	}
+
+	view := favouriteView(favourite)
+	return &view, nil
+}
+
+// CreateFavourite - Saves a city (CityId), POI (Xid) or place (Lat and Lon) for the user
+func CreateFavourite(userId uint, favourite models.Favourite, tags []string) (*models.FavouriteView, error) {
+	target := ""
+	switch favourite.Kind {
+	case models.FavouriteCity:
+		target = fmt.Sprint(*favourite.CityId)
+	case models.FavouritePoi:
+		target = *favourite.Xid
+	default:
+		// Rounded as stored, so the target matches the generated column (DECIMAL(9, 6) values joined with a comma)
+		lat, lon := math.Round(*favourite.Lat*1e6)/1e6, math.Round(*favourite.Lon*1e6)/1e6
+		favourite.Lat, favourite.Lon = &lat, &lon
+		target = fmt.Sprintf("%.6f,%.6f", lat, lon)
+	}
+
+	var existing models.Favourite
+	query := database.NewQueryBuilder("SELECT").Table("favourites").Columns("id").Where("user_id = ?").Where("kind = ?").Where("target = ?").Build()
+	_, err := database.Execute(&existing, query, userId, favourite.Kind, target)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Id > 0 {
+		return nil, ErrFavouriteExists
+	}
+
+	data, err := json.Marshal(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	query = database.NewQueryBuilder("INSERT").Table("favourites").Columns("user_id", "kind", "city_id", "xid", "lat", "lon", "name", "notes", "tags").Values(9).Build()
+	_, err = database.Execute(nil, query, userId, favourite.Kind, favourite.CityId, favourite.Xid, favourite.Lat, favourite.Lon, favourite.Name, favourite.Notes, data)
+	if err != nil {
+		// Another request saved the same favourite since the check above
+		if database.IsDuplicateKey(err) {
+			return nil, ErrFavouriteExists
+		}
+		return nil, err
+	}
+
+	query = database.NewQueryBuilder("SELECT").Table("favourites").Columns("id").Where("user_id = ?").Where("kind = ?").Where("target = ?").Build()
+	_, err = database.Execute(&existing, query, userId, favourite.Kind, target)
+	if err != nil {
+		return nil, err
+	}
+	return GetFavourite(userId, existing.Id)
+}
+
+// UpdateFavourite - Changes the name, notes and/or tags of a favourite
+func UpdateFavourite(userId uint, id uint, update models.FavouriteUpdate) (*models.FavouriteView, error) {
+	current, err := GetFavourite(userId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	name := current.Name
+	if update.Name != nil {
+		name = strings.TrimSpace(*update.Name)
+	}
+	notes := current.Notes
+	if update.Notes != nil {
+		notes = strings.TrimSpace(*update.Notes)
+	}
+	tags := current.Tags
+	if update.Tags != nil {
+		if tags, err = NormaliseTags(*update.Tags); err != nil {
+			return nil, err
+		}
+	}
+	if err := ValidateFavouriteText(name, notes); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	query := database.NewQueryBuilder("UPDATE").Table("favourites").Columns("name", "notes", "tags").Where("id = ?").Where("user_id = ?").Build()
+	_, err = database.Execute(nil, query, name, nullableText(notes), data, id, userId)
+	if err != nil {
+		return nil, err
+	}
+	return GetFavourite(userId, id)
+}
+
+// DeleteFavourite - Removes a favourite owned by the user
+func DeleteFavourite(userId uint, id uint) error {
+	query := database.NewQueryBuilder("DELETE").Table("favourites").Where("id = ?").Where("user_id = ?").Build()
+	rows, err := database.Execute(nil, query, id, userId)
+	if err != nil {
+		return err
+	}
+	if affected, ok := rows.(int64); ok && affected == 0 {
+		return ErrFavouriteNotFound
+	}
+	return nil
+}
+
+// favouriteQuery - Favourites with the summary columns (Latest cached weather for the city and cached POI)
+func favouriteQuery() *database.QueryBuilder {
+	return database.NewQueryBuilder("SELECT").Table("favourites").
+		Columns(
+			"favourites.*",
+			"cities.name AS city_name",
+			"cities.country_code AS country_code",
+			"CAST(city_weather.data->>'$.current.temp' AS DECIMAL(6, 2)) AS temperature",
+			"city_weather.data->>'$.current.weather[0].description' AS weather_description",
+			"city_weather.data->>'$.current.weather[0].icon' AS weather_icon",
+			"city_weather.updated_at AS weather_updated_at",
+			"city_pois.data->>'$.name' AS poi_name",
+			"city_pois.data->>'$.kinds' AS poi_kinds",
+			"CASE favourites.kind WHEN 'poi' THEN CAST(city_pois.data->>'$.point.lat' AS DECIMAL(9, 6)) WHEN 'city' THEN cities.lat ELSE favourites.lat END AS location_lat",
+			"CASE favourites.kind WHEN 'poi' THEN CAST(city_pois.data->>'$.point.lon' AS DECIMAL(9, 6)) WHEN 'city' THEN cities.lon ELSE favourites.lon END AS location_lon",
+		).
+		Join("LEFT JOIN cities ON cities.id = favourites.city_id").
+		Join("LEFT JOIN city_weather ON city_weather.id = (SELECT latest.id FROM city_weather latest WHERE latest.city_id = favourites.city_id ORDER BY latest.updated_at DESC LIMIT 1)").
+		Join("LEFT JOIN city_pois ON city_pois.id = (SELECT latest.id FROM city_pois latest WHERE latest.xid = favourites.xid ORDER BY latest.updated_at DESC LIMIT 1)")
+}
+
+func favouriteView(favourite models.Favourite) models.FavouriteView {
+	view := models.FavouriteView{
+		Id:        favourite.Id,
+		Kind:      favourite.Kind,
+		CityId:    favourite.CityId,
+		Xid:       favourite.Xid,
+		Name:      favourite.Name,
+		Tags:      []string{},
+		Lat:       favourite.LocationLat,
+		Lon:       favourite.LocationLon,
+		CreatedAt: favourite.CreatedAt,
+		UpdatedAt: favourite.UpdatedAt,
+	}
+	if favourite.Notes != nil {
+		view.Notes = *favourite.Notes
+	}
+	if err := json.Unmarshal(favourite.Tags, &view.Tags); err != nil || view.Tags == nil {
+		view.Tags = []string{}
+	}
+
+	summary := models.FavouriteSummary{
+		CityName:           deref(favourite.CityName),
+		CountryCode:        deref(favourite.CountryCode),
+		Temperature:        favourite.Temperature,
+		WeatherDescription: deref(favourite.WeatherDescription),
+		WeatherIcon:        deref(favourite.WeatherIcon),
+		WeatherUpdatedAt:   favourite.WeatherUpdatedAt,
+		PoiName:            deref(favourite.PoiName),
+		PoiKinds:           deref(favourite.PoiKinds),
+	}
+	if summary != (models.FavouriteSummary{}) {
+		view.Summary = &summary
+	}
+
+	return view
+}
+
+// nullableText - Empty strings are saved as NULL
+func nullableText(text string) *string {
+	if text == "" {
+		return nil
+	}
+	return &text
+}
+
+func deref(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
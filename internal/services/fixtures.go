@@ -0,0 +1,152 @@
+package services
+
+/* External API Fixtures
+
+- Record Mode: Calls the real External API and saves the response to the fixtures directory
+- Replay Mode: Serves previously recorded responses without touching the network
+- API Keys are stripped from the URL before it is used as a fixture key (so keys are never written to disk)
+*/
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/MCantyDev/city-explorer-server/internal/config"
+)
+
+// Query parameters which hold API Keys for the External APIs
+var secretParams = []string{"apikey", "appid", "key"}
+
+type Fixture struct {
+	URL         string `json:"url"`
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type"`
+	Body        string `json:"body"`
+}
+
+type FixtureTransport struct {
+	Mode string
+	Dir  string
+	Next http.RoundTripper // Used to call the real External API in record mode
+}
+
+// SetupExternalClient - Swaps the External API client for a fixture backed one (if record or replay mode is set), returns a function restoring the previous client
+func SetupExternalClient() func() {
+	previous := externalClient
+	restore := func() { externalClient = previous }
+	if config.Cfg.Fixtures.Mode == config.FixtureModeLive {
+		return restore
+	}
+
+	externalClient = &http.Client{
+		Timeout: externalTimeout, // Record mode still calls the real External API
+		Transport: &FixtureTransport{
+			Mode: config.Cfg.Fixtures.Mode,
+			Dir:  config.Cfg.Fixtures.Dir,
+			Next: http.DefaultTransport,
+		},
+	}
+	fmt.Printf("External APIs running in '%s' mode using fixtures from '%s'\n", config.Cfg.Fixtures.Mode, config.Cfg.Fixtures.Dir)
+	return restore
+}
+
+func (t *FixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := FixtureKey(req.URL)
+	path := filepath.Join(t.Dir, req.URL.Host, fixtureFileName(key))
+
+	if t.Mode == config.FixtureModeReplay {
+		return replayFixture(req, path, key)
+	}
+
+	resp, err := t.Next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	fixture := Fixture{
+		URL:         key,
+		Status:      resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+		Body:        string(body),
+	}
+	if err := saveFixture(path, fixture); err != nil {
+		return nil, err
+	}
+
+	return fixture.response(req), nil
+}
+
+// FixtureKey - Builds a stable key for a URL (API Keys removed, Query parameters sorted)
+func FixtureKey(u *url.URL) string {
+	query := u.Query()
+	for _, param := range secretParams {
+		query.Del(param)
+	}
+
+	return fmt.Sprintf("%s://%s%s?%s", u.Scheme, u.Host, u.Path, query.Encode())
+}
+
+func fixtureFileName(key string) string {
+	hash := sha1.Sum([]byte(key))
+	return hex.EncodeToString(hash[:]) + ".json"
+}
+
+func replayFixture(req *http.Request, path string, key string) (*http.Response, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("no fixture recorded for '%s' (run with EXTERNAL_API_MODE=record first)", key)
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("invalid fixture file '%s': %s", path, err)
+	}
+
+	return fixture.response(req), nil
+}
+
+func saveFixture(path string, fixture Fixture) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create fixture directory: %s", err)
+	}
+
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+func (f Fixture) response(req *http.Request) *http.Response {
+	header := http.Header{}
+	if f.ContentType != "" {
+		header.Set("Content-Type", f.ContentType)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.Status, http.StatusText(f.Status)),
+		StatusCode:    f.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader([]byte(f.Body))),
+		ContentLength: int64(len(f.Body)),
+		Request:       req,
+	}
+}
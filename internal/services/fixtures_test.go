@@ -0,0 +1,144 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/MCantyDev/city-explorer-server/internal/config"
+	"github.com/MCantyDev/city-explorer-server/internal/geo"
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+)
+
+// Paris, FR as returned by the committed Photon fixture
+const parisLat, parisLon = "48.8534951", "2.3483915"
+
+// replayFixtures - Serves the External APIs from the committed fixtures (Config and client are restored after the test)
+func replayFixtures(t *testing.T) {
+	t.Helper()
+
+	previous := config.Cfg
+	config.Cfg = &config.Config{
+		Fixtures: config.FixturesConfig{
+			Mode: config.FixtureModeReplay,
+			Dir:  "../fixtures",
+		},
+		RestCountriesAPI: config.ExternalAPI{
+			Name: "Rest-Countries API",
+			URL:  "https://restcountries.com/v3.1/alpha/%s",
+		},
+		OpenMeteoAPI: config.ExternalAPI{
+			Name: "Open-Meteo API",
+			URL:  "https://api.open-meteo.com/v1/forecast?latitude=%s&longitude=%s&current=temperature_2m,relative_humidity_2m,apparent_temperature,is_day,precipitation,weather_code,cloud_cover,pressure_msl,wind_speed_10m,wind_direction_10m,wind_gusts_10m,uv_index&daily=weather_code,temperature_2m_max,temperature_2m_min,apparent_temperature_max,sunrise,sunset,uv_index_max,precipitation_sum,precipitation_probability_max,wind_speed_10m_max,wind_gusts_10m_max,wind_direction_10m_dominant&wind_speed_unit=ms&timeformat=unixtime&timezone=auto",
+		},
+		OpenTripAPI: config.SecureExternalAPI{
+			Name: "OpenTrip API",
+			URL:  "https://api.opentripmap.com/0.1/en/places/radius?lat=%s&lon=%s&radius=%d&limit=%d&kinds=%s&rate=%s&apikey=%s",
+			Key:  "replay",
+		},
+	}
+	config.Cfg.Weather.Provider = config.WeatherProviderOpenMeteo
+
+	restore := SetupExternalClient()
+	t.Cleanup(func() {
+		restore()
+		config.Cfg = previous
+	})
+}
+
+func TestFetchCountryReplay(t *testing.T) {
+	replayFixtures(t)
+
+	data, err := FetchCountry("FR")
+	if err != nil {
+		t.Fatalf("FetchCountry() error = %v", err)
+	}
+	profile, err := ParseCountryProfile(data)
+	if err != nil {
+		t.Fatalf("ParseCountryProfile() error = %v", err)
+	}
+
+	if profile.Code != "FR" || profile.Name != "France" || !reflect.DeepEqual(profile.Capital, []string{"Paris"}) {
+		t.Errorf("profile = %s, %s, %v, want FR, France, [Paris]", profile.Code, profile.Name, profile.Capital)
+	}
+	if want := []models.CountryCurrency{{Code: "EUR", Name: "Euro", Symbol: "€"}}; !reflect.DeepEqual(profile.Currencies, want) {
+		t.Errorf("currencies = %+v, want %+v", profile.Currencies, want)
+	}
+	if want := []string{"+33"}; !reflect.DeepEqual(profile.CallingCodes, want) {
+		t.Errorf("calling codes = %v, want %v", profile.CallingCodes, want)
+	}
+
+	if _, err := FetchCountry("ZZ"); err == nil {
+		t.Errorf("FetchCountry(ZZ) error = nil, want no fixture recorded")
+	}
+}
+
+func TestFetchWeatherReplay(t *testing.T) {
+	replayFixtures(t)
+
+	data, err := FetchWeather(parisLat, parisLon)
+	if err != nil {
+		t.Fatalf("FetchWeather() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		units       string
+		temperature float64
+		rain        float64 // Second day's precipitation
+	}{
+		{name: "metric", units: models.UnitsMetric, temperature: 14.6, rain: 6.4},
+		{name: "imperial", units: models.UnitsImperial, temperature: 58.3, rain: 0.25},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			weather, err := NormaliseWeather(data, models.WeatherOptions{Units: test.units, Lang: "en"})
+			if err != nil {
+				t.Fatalf("NormaliseWeather() error = %v", err)
+			}
+
+			if weather.Timezone != "Europe/Paris" || len(weather.Daily) != 7 {
+				t.Fatalf("weather = %s with %d days, want Europe/Paris with 7 days", weather.Timezone, len(weather.Daily))
+			}
+			if weather.Current.Temperature != test.temperature {
+				t.Errorf("current temperature = %v, want %v", weather.Current.Temperature, test.temperature)
+			}
+			if day := weather.Daily[1]; day.Precipitation != test.rain || day.PrecipitationProbability != 85 || day.Condition.Group != "rain" {
+				t.Errorf("second day = %v, %v%%, %s, want %v, 85%%, rain", day.Precipitation, day.PrecipitationProbability, day.Condition.Group, test.rain)
+			}
+		})
+	}
+}
+
+func TestFetchSightsReplay(t *testing.T) {
+	replayFixtures(t)
+
+	search, err := ParseSightsSearch("", "", "")
+	if err != nil {
+		t.Fatalf("ParseSightsSearch() error = %v", err)
+	}
+	data, err := FetchSights(parisLat, parisLon, search)
+	if err != nil {
+		t.Fatalf("FetchSights() error = %v", err)
+	}
+
+	anchor, err := geo.ParsePoint(parisLat, parisLon)
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, err := PaginateSights(data, models.SightsPageOptions{PageSize: 4, Sort: SightsSortRating, Anchor: anchor})
+	if err != nil {
+		t.Fatalf("PaginateSights() error = %v", err)
+	}
+
+	if page.Total != 6 || page.TotalPages != 2 || page.NextCursor == "" {
+		t.Errorf("page = %d total, %d pages, cursor %q, want 6 total, 2 pages and a cursor", page.Total, page.TotalPages, page.NextCursor)
+	}
+	names := []string{}
+	for _, feature := range page.Features {
+		names = append(names, feature.Properties.Name)
+	}
+	if want := []string{"Théâtre du Châtelet", "Cinéma Le Champo", "Hôtel Dieu", "Paris Visites Bus Stop"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("names = %v, want %v", names, want)
+	}
+}
@@ -0,0 +1,208 @@
+package services
+
+/* Geocoder
+
+- Photon (Keyless, OpenStreetMap data)
+- Every geocoder returns the normalised models.Place
+- City searches are filtered to populated places (city, town, village...) and deduplicated
+*/
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/MCantyDev/city-explorer-server/internal/config"
+	"github.com/MCantyDev/city-explorer-server/internal/geo"
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+)
+
+// Reverse geocoding results are cached per snapped location (~1km)
+const ReverseGeocodeSnapDecimals = 2
+
+// City Search Limits and Languages (Languages supported by Photon)
+const (
+	DefaultPlaceSearchLimit = 10
+	MaxPlaceSearchLimit     = 50
+)
+
+var PlaceSearchLanguages = []string{"en", "de", "fr", "it"}
+
+// Populated place types (OSM 'place' values) -> Population Rank
+var placeRanks = map[string]int{
+	"city":         4,
+	"municipality": 3,
+	"town":         2,
+	"village":      1,
+}
+
+// Returned when there is no city near the coordinates
+var ErrPlaceNotFound = errors.New("no city found near these coordinates")
+
+type Geocoder interface {
+	Name() string
+	Search(search models.PlaceSearch) ([]models.Place, error)
+	Reverse(point geo.Point) (*models.Place, error)
+}
+
+type photonGeocoder struct{}
+
+var geocoder Geocoder = photonGeocoder{}
+
+// ParsePlaceSearch - Validates the city search parameters (Empty values use the defaults)
+func ParsePlaceSearch(query string, limit string, lang string) (models.PlaceSearch, error) {
+	search := models.PlaceSearch{
+		Query: strings.TrimSpace(query),
+		Limit: DefaultPlaceSearchLimit,
+		Lang:  strings.ToLower(lang),
+	}
+
+	if search.Query == "" {
+		return search, fmt.Errorf("search query must not be empty")
+	}
+
+	if limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil || parsed < 1 || parsed > MaxPlaceSearchLimit {
+			return search, fmt.Errorf("'limit' must be between 1 and %d", MaxPlaceSearchLimit)
+		}
+		search.Limit = parsed
+	}
+
+	if search.Lang == "" {
+		search.Lang = PlaceSearchLanguages[0]
+	}
+	if !slices.Contains(PlaceSearchLanguages, search.Lang) {
+		return search, fmt.Errorf("'lang' must be one of: %s", strings.Join(PlaceSearchLanguages, ", "))
+	}
+
+	return search, nil
+}
+
+// SearchCities - Finds populated places matching a query (Best match first)
+func SearchCities(search models.PlaceSearch) ([]models.Place, error) {
+	return geocoder.Search(search)
+}
+
+// ReverseGeocode - Finds the city at (or nearest to) a point
+func ReverseGeocode(point geo.Point) (*models.Place, error) {
+	return geocoder.Reverse(point)
+}
+
+// Photon
+
+func (photonGeocoder) Name() string {
+	return config.Cfg.PhotonAPI.Name
+}
+
+func (photonGeocoder) Search(search models.PlaceSearch) ([]models.Place, error) {
+	api := config.Cfg.PhotonAPI
+
+	// Streets, shops etc. are filtered out afterwards, so more results are requested than needed
+	fetchLimit := min(search.Limit*3, MaxPlaceSearchLimit)
+
+	// Encoding the Query for spaces and such
+	encodedQuery := url.QueryEscape(search.Query)
+
+	url := fmt.Sprintf(api.URL, encodedQuery, search.Lang, fetchLimit)
+	if !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("'%s' URL is not set up properly", api.Name)
+	}
+
+	data, err := FetchExternalAPI(url)
+	if err != nil {
+		return nil, err
+	}
+
+	var external models.PhotonRequest
+	if err := json.Unmarshal(data, &external); err != nil {
+		return nil, fmt.Errorf("invalid JSON from %s: %s", api.Name, err)
+	}
+
+	places := []models.Place{}
+	seen := map[string]bool{}
+	for _, feature := range external.Features {
+		if feature.Properties.OsmKey != "place" || placeRanks[feature.Properties.OsmValue] == 0 {
+			continue
+		}
+
+		place := photonPlace(feature)
+
+		// The same city can be returned more than once (e.g. as a node and as a relation)
+		key := strings.ToLower(place.Name + "|" + place.State + "|" + place.CountryCode)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		places = append(places, *place)
+		if len(places) == search.Limit {
+			break
+		}
+	}
+
+	return places, nil
+}
+
+func (photonGeocoder) Reverse(point geo.Point) (*models.Place, error) {
+	api := config.Cfg.PhotonReverseAPI
+
+	url := fmt.Sprintf(api.URL, formatCoordinate(point.Lat), formatCoordinate(point.Lon))
+	if !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("'%s' URL is not set up properly", api.Name)
+	}
+
+	data, err := FetchExternalAPI(url)
+	if err != nil {
+		return nil, err
+	}
+
+	var external models.PhotonRequest
+	if err := json.Unmarshal(data, &external); err != nil {
+		return nil, fmt.Errorf("invalid JSON from %s: %s", api.Name, err)
+	}
+	if len(external.Features) == 0 {
+		return nil, ErrPlaceNotFound
+	}
+
+	return photonPlace(external.Features[0]), nil
+}
+
+// photonPlace - Maps a Photon feature into a Place
+func photonPlace(feature models.PhotonFeature) *models.Place {
+	properties := feature.Properties
+
+	place := &models.Place{
+		Provider:    config.Cfg.PhotonAPI.Name,
+		Name:        properties.Name,
+		Type:        properties.Type,
+		State:       properties.State,
+		Country:     properties.Country,
+		CountryCode: strings.ToUpper(properties.CountryCode),
+		OsmId:       properties.OsmId,
+		OsmType:     properties.OsmType,
+		Rank:        placeRanks[properties.OsmValue],
+	}
+	if coordinates := feature.Geometry.Coordinates; len(coordinates) >= 2 {
+		place.Lon = coordinates[0]
+		place.Lat = coordinates[1]
+	}
+	if extent := properties.Extent; len(extent) == 4 {
+		place.BoundingBox = &models.BoundingBox{
+			MinLon: extent[0],
+			MaxLat: extent[1],
+			MaxLon: extent[2],
+			MinLat: extent[3],
+		}
+	}
+
+	return place
+}
+
+func formatCoordinate(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
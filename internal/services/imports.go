@@ -0,0 +1,330 @@
+package services
+
+/* Imports
+
+- Matches places read from an uploaded file to cached OpenTripMap POIs (city_sights) by proximity, the rest become custom places
+- Optionally saves the results to the user's favourites or to a trip, reporting an error per item
+*/
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/MCantyDev/city-explorer-server/internal/database"
+	"github.com/MCantyDev/city-explorer-server/internal/geo"
+	"github.com/MCantyDev/city-explorer-server/internal/importer"
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+)
+
+const (
+	DefaultImportRadius  = 50 // Metres between an imported point and a cached POI
+	MinImportRadius      = 10
+	MaxImportRadius      = 500
+	ImportNameFactor     = 3 // POIs with the same name as the imported place match up to this many times the radius
+	ImportClusterDegrees = 1 // Places in the same grid cell share one cached sights query
+
+	metresPerDegree = 111320
+)
+
+// ParseImportRadius - Validates the matching radius in metres (Empty uses the default)
+func ParseImportRadius(value string) (float64, error) {
+	if value == "" {
+		return DefaultImportRadius, nil
+	}
+	radius, err := strconv.Atoi(value)
+	if err != nil || radius < MinImportRadius || radius > MaxImportRadius {
+		return 0, fmt.Errorf("'radius' must be between %d and %d metres", MinImportRadius, MaxImportRadius)
+	}
+	return float64(radius), nil
+}
+
+// MatchImportedPlaces - One item per entry of the file (in file order), matched to the closest cached POI within the radius
+func MatchImportedPlaces(result *importer.Result, radius float64) (*models.ImportReport, error) {
+	pois, err := importCandidates(result.Places, radius*ImportNameFactor)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ImportReport{Format: result.Format, Items: []models.ImportItem{}}
+	for _, place := range result.Places {
+		item := models.ImportItem{
+			Index:       place.Index,
+			Name:        place.Name,
+			Description: place.Description,
+			Lat:         &place.Lat,
+			Lon:         &place.Lon,
+			Kind:        models.TripItemCustom,
+		}
+		if match := matchPoi(place, pois, radius); match != nil {
+			item.Kind = models.TripItemPoi
+			item.Match = match
+		}
+		report.Items = append(report.Items, item)
+	}
+	for _, problem := range result.Problems {
+		report.Items = append(report.Items, models.ImportItem{Index: problem.Index, Name: problem.Name, Error: problem.Error})
+	}
+
+	sort.Slice(report.Items, func(i, j int) bool {
+		return report.Items[i].Index < report.Items[j].Index
+	})
+	countImport(report)
+	return report, nil
+}
+
+// SaveImportToFavourites - Saves each readable item as a POI (matched) or place (custom) favourite
+func SaveImportToFavourites(userId uint, report *models.ImportReport, tags []string) {
+	report.Target = models.ImportTargetFavourites
+
+	for i := range report.Items {
+		item := &report.Items[i]
+		if item.Error != "" {
+			continue
+		}
+
+		favourite := models.Favourite{Kind: models.FavouritePlace, Name: item.Name, Lat: item.Lat, Lon: item.Lon}
+		if item.Match != nil {
+			favourite = models.Favourite{Kind: models.FavouritePoi, Name: item.Name, Xid: &item.Match.Xid, CityId: &item.Match.CityId}
+		}
+		if item.Description != "" {
+			favourite.Notes = &item.Description
+		}
+
+		view, err := CreateFavourite(userId, favourite, tags)
+		if err != nil {
+			item.Error = importError(err)
+			continue
+		}
+		item.Saved = true
+		item.SavedId = &view.Id
+	}
+	countImport(report)
+}
+
+// SaveImportToTrip - Adds each readable item to the end of a trip day (POIs keep their xid, the rest are custom places)
+func SaveImportToTrip(userId uint, tripId uint, version *int, day int, timeSlot string, report *models.ImportReport) error {
+	trip, err := loadTrip(userId, tripId, models.TripRoleEditor)
+	if err != nil {
+		return err
+	}
+	if days := TripLength(trip); day < 1 || day > days {
+		return fmt.Errorf("%w: 'day' must be between 1 and %d", ErrInvalidTrip, days)
+	}
+	if _, err := ParseTimeSlot(timeSlot); err != nil {
+		return err
+	}
+	if err := claimTripVersion(trip, version); err != nil {
+		return err
+	}
+
+	cities, err := tripCities(trip.Id)
+	if err != nil {
+		return err
+	}
+	inTrip := map[uint]bool{}
+	for _, city := range cities[trip.Id] {
+		inTrip[city.CityId] = true
+	}
+
+	report.Target = models.ImportTargetTrip
+	report.TripId = &trip.Id
+
+	for i := range report.Items {
+		item := &report.Items[i]
+		if item.Error != "" {
+			continue
+		}
+
+		tripItem := models.TripItem{
+			Day:      day,
+			TimeSlot: timeSlot,
+			Kind:     item.Kind,
+			Name:     item.Name,
+			Lat:      item.Lat,
+			Lon:      item.Lon,
+		}
+		if item.Match != nil {
+			tripItem.Xid = &item.Match.Xid
+			if inTrip[item.Match.CityId] {
+				tripItem.CityId = &item.Match.CityId
+			}
+		}
+		if item.Description != "" {
+			tripItem.Notes = &item.Description
+		}
+
+		err := prepareTripItem(trip, &tripItem)
+		if err == nil {
+			err = insertTripItem(trip, &tripItem)
+		}
+		if err != nil {
+			item.Error = importError(err)
+			continue
+		}
+		item.Saved = true
+		item.SavedId = &tripItem.Id
+	}
+	countImport(report)
+	return nil
+}
+
+type importPoi struct {
+	Xid        string
+	Name       string
+	CityId     uint
+	Categories []string
+	Point      geo.Point
+}
+
+// importCandidates - Cached sights that could be within reach of the places (Deduplicated by xid)
+func importCandidates(places []importer.Place, reach float64) ([]importPoi, error) {
+	pois := []importPoi{}
+	seen := map[string]bool{}
+
+	// One query per cluster, so places far apart do not pull in everything between them
+	for _, cluster := range importClusters(places) {
+		cached, err := importClusterSights(cluster, reach)
+		if err != nil {
+			return nil, err
+		}
+		pois = appendImportPois(pois, seen, cached)
+	}
+	return pois, nil
+}
+
+// importClusters - Places grouped by the ImportClusterDegrees grid cell they are in
+func importClusters(places []importer.Place) [][]importer.Place {
+	type cell struct{ lat, lon int }
+
+	clusters := [][]importer.Place{}
+	indexes := map[cell]int{}
+	for _, place := range places {
+		key := cell{int(math.Floor(place.Lat / ImportClusterDegrees)), int(math.Floor(place.Lon / ImportClusterDegrees))}
+		index, ok := indexes[key]
+		if !ok {
+			index = len(clusters)
+			indexes[key] = index
+			clusters = append(clusters, nil)
+		}
+		clusters[index] = append(clusters[index], place)
+	}
+	return clusters
+}
+
+// importClusterSights - Cached sights rows around a cluster of places
+func importClusterSights(places []importer.Place, reach float64) ([]models.CitySights, error) {
+	minLat, maxLat, minLon, maxLon := 90.0, -90.0, 180.0, -180.0
+	for _, place := range places {
+		minLat, maxLat = math.Min(minLat, place.Lat), math.Max(maxLat, place.Lat)
+		minLon, maxLon = math.Min(minLon, place.Lon), math.Max(maxLon, place.Lon)
+	}
+
+	// Rows are stored by their search centre, so the box grows by the largest search radius
+	latMargin := (MaxSightsRadius + reach) / metresPerDegree
+	lonMargin := 180.0
+	if cos := math.Cos(math.Max(math.Abs(minLat), math.Abs(maxLat)) * math.Pi / 180); cos > 0.01 {
+		lonMargin = math.Min(180, latMargin/cos)
+	}
+
+	var cached []models.CitySights
+	query := database.NewQueryBuilder("SELECT").Table("city_sights").Where("lat BETWEEN ? AND ?").Where("lon BETWEEN ? AND ?").OrderBy("updated_at DESC").Build()
+	_, err := database.Execute(&cached, query, minLat-latMargin, maxLat+latMargin, minLon-lonMargin, maxLon+lonMargin)
+	return cached, err
+}
+
+// appendImportPois - Adds the features of the cached rows that have not been seen yet
+func appendImportPois(pois []importPoi, seen map[string]bool, cached []models.CitySights) []importPoi {
+	for _, row := range cached {
+		var collection struct {
+			Features []models.SightFeature `json:"features"`
+		}
+		if err := json.Unmarshal(row.Data, &collection); err != nil {
+			continue
+		}
+
+		for _, feature := range collection.Features {
+			properties := feature.Properties
+			coordinates := feature.Geometry.Coordinates
+			if properties.Xid == "" || len(coordinates) < 2 || seen[properties.Xid] {
+				continue
+			}
+			seen[properties.Xid] = true
+			pois = append(pois, importPoi{
+				Xid:        properties.Xid,
+				Name:       properties.Name,
+				CityId:     row.CityId,
+				Categories: CategoriesForKinds(properties.Kinds),
+				Point:      geo.Point{Lat: coordinates[1], Lon: coordinates[0]},
+			})
+		}
+	}
+	return pois
+}
+
+// matchPoi - The closest POI with the same name within ImportNameFactor x radius, otherwise the closest POI within the radius (nil if none)
+func matchPoi(place importer.Place, pois []importPoi, radius float64) *models.ImportMatch {
+	point := geo.Point{Lat: place.Lat, Lon: place.Lon}
+	name := normaliseName(place.Name)
+
+	var nearest, named *importPoi
+	nearestDistance, namedDistance := radius, radius*ImportNameFactor
+	for i := range pois {
+		distance := geo.Distance(point, pois[i].Point)
+		if distance <= nearestDistance {
+			nearest, nearestDistance = &pois[i], distance
+		}
+		if distance <= namedDistance && name != "" && normaliseName(pois[i].Name) == name {
+			named, namedDistance = &pois[i], distance
+		}
+	}
+
+	best, distance := nearest, nearestDistance
+	if named != nil {
+		best, distance = named, namedDistance
+	}
+	if best == nil {
+		return nil
+	}
+	return &models.ImportMatch{
+		Xid:        best.Xid,
+		Name:       best.Name,
+		CityId:     best.CityId,
+		Categories: best.Categories,
+		Distance:   math.Round(distance),
+	}
+}
+
+// countImport - Recounts the report totals from its items
+func countImport(report *models.ImportReport) {
+	report.Total, report.Matched, report.Custom, report.Saved, report.Failed = len(report.Items), 0, 0, 0, 0
+	for _, item := range report.Items {
+		switch {
+		case item.Error != "":
+			report.Failed++
+		case item.Kind == models.TripItemPoi:
+			report.Matched++
+		default:
+			report.Custom++
+		}
+		if item.Saved {
+			report.Saved++
+		}
+	}
+}
+
+// importError - Message for an item that could not be saved (Database errors are not shown to the user)
+func importError(err error) string {
+	if errors.Is(err, ErrFavouriteExists) || errors.Is(err, ErrInvalidFavourite) || errors.Is(err, ErrInvalidTrip) {
+		return err.Error()
+	}
+	return "database error while saving"
+}
+
+func normaliseName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
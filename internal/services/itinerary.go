@@ -0,0 +1,483 @@
+package services
+
+/* Itinerary Suggestions
+
+- Builds a day by day plan from the sights already cached for a city (No external API calls)
+- Sights are picked by rating, clustered by proximity into one group per day (k-means, capped per day)
+- Each day is routed from the city centre with a nearest neighbour pass improved by 2-opt
+- Outdoor-heavy days are moved off days where the cached forecast shows heavy rain (Outdoor stops are skipped when no dry day is left)
+*/
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/MCantyDev/city-explorer-server/internal/database"
+	"github.com/MCantyDev/city-explorer-server/internal/geo"
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+)
+
+const (
+	DefaultItineraryDays   = 3
+	MaxItineraryDays       = 14
+	DefaultItineraryPerDay = 5
+	MaxItineraryPerDay     = 12
+
+	HeavyRainPrecipitation = 10.0 // Millimetres in a day
+	HeavyRainProbability   = 60.0 // Percentage, for heavy rain and thunderstorm conditions with less forecast rainfall
+	OutdoorHeavyShare      = 0.5  // Days with more than this share of outdoor stops are moved off rainy days
+
+	kMeansIterations = 50
+)
+
+// Categories used when none are requested (Places to visit rather than services)
+var DefaultItineraryCategories = []string{"culture", "history", "architecture", "religion", "nature", "entertainment"}
+
+// Categories that count as outdoors (Children are included through their parent)
+var outdoorCategories = []string{"nature"}
+
+var ErrNoCachedSights = errors.New("no sights cached for this city, search its sights first")
+
+// ParseItineraryOptions - Validates the number of days, stops per day, categories and start date (Empty values use the defaults)
+func ParseItineraryOptions(days string, perDay string, categories string, startDate string) (models.ItineraryOptions, error) {
+	options := models.ItineraryOptions{Days: DefaultItineraryDays, PerDay: DefaultItineraryPerDay}
+
+	if days != "" {
+		parsed, err := strconv.Atoi(days)
+		if err != nil || parsed < 1 || parsed > MaxItineraryDays {
+			return options, fmt.Errorf("'days' must be between 1 and %d", MaxItineraryDays)
+		}
+		options.Days = parsed
+	}
+
+	if perDay != "" {
+		parsed, err := strconv.Atoi(perDay)
+		if err != nil || parsed < 1 || parsed > MaxItineraryPerDay {
+			return options, fmt.Errorf("'per-day' must be between 1 and %d", MaxItineraryPerDay)
+		}
+		options.PerDay = parsed
+	}
+
+	ids, err := ParsePoiCategories(categories)
+	if err != nil {
+		return options, err
+	}
+	options.Categories = ids
+	if len(options.Categories) == 0 {
+		options.Categories = DefaultItineraryCategories
+	}
+
+	if startDate != "" {
+		if _, err := time.Parse("2006-01-02", startDate); err != nil {
+			return options, fmt.Errorf("'start-date' must be a YYYY-MM-DD date")
+		}
+		options.StartDate = startDate
+	}
+
+	return options, nil
+}
+
+// SuggestItinerary - A suggested plan for the city from its cached sights and forecast
+func SuggestItinerary(city *models.City, options models.ItineraryOptions) (*models.Itinerary, error) {
+	var cached []models.CitySights
+	query := database.NewQueryBuilder("SELECT").Table("city_sights").Where("city_id = ?").OrderBy("updated_at DESC").Build()
+	_, err := database.Execute(&cached, query, city.Id)
+	if err != nil {
+		return nil, err
+	}
+	if len(cached) == 0 {
+		return nil, ErrNoCachedSights
+	}
+
+	candidates := itineraryCandidates(cached, options)
+
+	itinerary := &models.Itinerary{
+		CityId:      city.Id,
+		City:        city.Name,
+		CountryCode: city.CountryCode,
+		Categories:  options.Categories,
+		Candidates:  len(candidates),
+		Days:        make([]models.ItineraryDay, options.Days),
+	}
+
+	// Forecast for each day (Only when the start date is known and the city has cached weather)
+	rainy := make([]bool, options.Days)
+	if options.StartDate != "" {
+		forecasts, err := cachedForecasts(city.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		start, _ := time.Parse("2006-01-02", options.StartDate)
+		for i := range itinerary.Days {
+			day := &itinerary.Days[i]
+			day.Date = start.AddDate(0, 0, i).Format("2006-01-02")
+			if weather, ok := forecasts[city.Id]; ok {
+				day.Forecast = dailyForecast(weather, day.Date)
+			}
+			day.HeavyRain = IsHeavyRain(day.Forecast)
+			rainy[i] = day.HeavyRain
+		}
+	}
+
+	planDays(itinerary.Days, candidates, options, rainy)
+	return itinerary, nil
+}
+
+// planDays - Picks the best rated candidates, clusters them into days, moves outdoor-heavy days off rainy days and routes each day
+func planDays(days []models.ItineraryDay, candidates []itineraryCandidate, options models.ItineraryOptions, rainy []bool) {
+	// Highest rated sights first, closest to the centre within a rating
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Rate != candidates[j].Rate {
+			return candidates[i].Rate > candidates[j].Rate
+		}
+		return candidates[i].CentreDistance < candidates[j].CentreDistance
+	})
+	candidates = candidates[:min(len(candidates), options.Days*options.PerDay)]
+
+	clusters := clusterStops(candidates, options.Days, options.PerDay)
+
+	for i, cluster := range scheduleClusters(clusters, rainy) {
+		day := &days[i]
+		day.Day = i + 1
+		day.Skipped = []models.ItineraryStop{}
+
+		// Outdoor-heavy days that could not be moved to a dry day keep only their indoor stops
+		if rainy[i] && outdoorShare(cluster) > OutdoorHeavyShare {
+			var indoor []models.ItineraryStop
+			for _, stop := range cluster {
+				if stop.Outdoor {
+					day.Skipped = append(day.Skipped, stop)
+				} else {
+					indoor = append(indoor, stop)
+				}
+			}
+			cluster = indoor
+		}
+
+		day.Stops = routeStops(options.Centre, cluster)
+		day.OutdoorShare = math.Round(outdoorShare(day.Stops)*100) / 100
+		for _, stop := range day.Stops {
+			day.Distance += stop.Leg.Distance
+		}
+		day.WalkingMinutes = geo.WalkingMinutes(day.Distance)
+	}
+}
+
+// IsHeavyRain - True if the daily forecast shows heavy rain (false without a forecast)
+func IsHeavyRain(forecast *models.WeatherDaily) bool {
+	if forecast == nil {
+		return false
+	}
+	if forecast.Precipitation >= HeavyRainPrecipitation {
+		return true
+	}
+	heavy := forecast.Condition.Code == "heavy_rain" || forecast.Condition.Group == "thunderstorm"
+	return heavy && forecast.PrecipitationProbability >= HeavyRainProbability
+}
+
+type itineraryCandidate struct {
+	models.ItineraryStop
+	CentreDistance float64 // Metres from the city centre
+}
+
+// itineraryCandidates - Named sights from every cached search of the city that match the categories (Deduplicated by xid)
+func itineraryCandidates(cached []models.CitySights, options models.ItineraryOptions) []itineraryCandidate {
+	candidates := []itineraryCandidate{}
+	seen := map[string]bool{}
+
+	for _, row := range cached {
+		var collection struct {
+			Features []models.SightFeature `json:"features"`
+		}
+		if err := json.Unmarshal(row.Data, &collection); err != nil {
+			continue
+		}
+
+		for _, feature := range collection.Features {
+			properties := feature.Properties
+			coordinates := feature.Geometry.Coordinates
+			if properties.Xid == "" || properties.Name == "" || len(coordinates) < 2 || seen[properties.Xid] {
+				continue
+			}
+
+			categories := CategoriesForKinds(properties.Kinds)
+			if !HasPoiCategory(categories, options.Categories) {
+				continue
+			}
+			seen[properties.Xid] = true
+
+			point := geo.Point{Lat: coordinates[1], Lon: coordinates[0]}
+			candidates = append(candidates, itineraryCandidate{
+				ItineraryStop: models.ItineraryStop{
+					Xid:        properties.Xid,
+					Name:       properties.Name,
+					Lat:        point.Lat,
+					Lon:        point.Lon,
+					Rate:       properties.Rate,
+					Categories: categories,
+					Outdoor:    HasPoiCategory(categories, outdoorCategories),
+				},
+				CentreDistance: geo.Distance(options.Centre, point),
+			})
+		}
+	}
+	return candidates
+}
+
+// clusterStops - Groups the candidates into at most k clusters of at most capacity stops (k-means seeded with the best rated sight and the furthest sights from the seeds)
+func clusterStops(candidates []itineraryCandidate, k int, capacity int) [][]models.ItineraryStop {
+	k = min(k, len(candidates))
+	if k == 0 {
+		return nil
+	}
+
+	points := make([]geo.Point, len(candidates))
+	for i, candidate := range candidates {
+		points[i] = geo.Point{Lat: candidate.Lat, Lon: candidate.Lon}
+	}
+
+	// Farthest point seeding (Deterministic, the candidates are sorted by rating)
+	centroids := []geo.Point{points[0]}
+	for len(centroids) < k {
+		best, bestDistance := 0, -1.0
+		for i, point := range points {
+			if distance := nearestDistance(point, centroids); distance > bestDistance {
+				best, bestDistance = i, distance
+			}
+		}
+		centroids = append(centroids, points[best])
+	}
+
+	assignment := make([]int, len(points))
+	for iteration := 0; iteration < kMeansIterations; iteration++ {
+		changed := iteration == 0
+		for i, point := range points {
+			nearest := nearestCentroid(point, centroids, nil)
+			if nearest != assignment[i] {
+				assignment[i] = nearest
+				changed = true
+			}
+		}
+		balanceClusters(points, centroids, assignment, capacity)
+
+		for c := range centroids {
+			var sum geo.Point
+			count := 0
+			for i, point := range points {
+				if assignment[i] == c {
+					sum.Lat += point.Lat
+					sum.Lon += point.Lon
+					count++
+				}
+			}
+			if count > 0 {
+				centroids[c] = geo.Point{Lat: sum.Lat / float64(count), Lon: sum.Lon / float64(count)}
+			}
+		}
+
+		if !changed {
+			break
+		}
+	}
+
+	clusters := make([][]models.ItineraryStop, k)
+	for i, candidate := range candidates {
+		clusters[assignment[i]] = append(clusters[assignment[i]], candidate.ItineraryStop)
+	}
+
+	// Empty clusters are dropped (Two sights at the same place)
+	filled := clusters[:0]
+	for _, cluster := range clusters {
+		if len(cluster) > 0 {
+			filled = append(filled, cluster)
+		}
+	}
+	return filled
+}
+
+// balanceClusters - Moves stops out of clusters over capacity, cheapest move (extra distance to the next nearest cluster with room) first
+func balanceClusters(points []geo.Point, centroids []geo.Point, assignment []int, capacity int) {
+	for {
+		sizes := make([]int, len(centroids))
+		for _, c := range assignment {
+			sizes[c]++
+		}
+
+		full := map[int]bool{}
+		over := -1
+		for c, size := range sizes {
+			if size >= capacity {
+				full[c] = true
+			}
+			if size > capacity && over < 0 {
+				over = c
+			}
+		}
+		if over < 0 || len(full) == len(centroids) {
+			return
+		}
+
+		best, bestTarget, bestCost := -1, -1, math.Inf(1)
+		for i, point := range points {
+			if assignment[i] != over {
+				continue
+			}
+			target := nearestCentroid(point, centroids, full)
+			cost := geo.Distance(point, centroids[target]) - geo.Distance(point, centroids[over])
+			if cost < bestCost {
+				best, bestTarget, bestCost = i, target, cost
+			}
+		}
+		assignment[best] = bestTarget
+	}
+}
+
+// nearestCentroid - Index of the closest centroid (Skipping excluded ones)
+func nearestCentroid(point geo.Point, centroids []geo.Point, excluded map[int]bool) int {
+	nearest, nearestDistance := -1, math.Inf(1)
+	for c, centroid := range centroids {
+		if excluded[c] {
+			continue
+		}
+		if distance := geo.Distance(point, centroid); distance < nearestDistance {
+			nearest, nearestDistance = c, distance
+		}
+	}
+	return nearest
+}
+
+func nearestDistance(point geo.Point, centroids []geo.Point) float64 {
+	nearest := math.Inf(1)
+	for _, centroid := range centroids {
+		nearest = math.Min(nearest, geo.Distance(point, centroid))
+	}
+	return nearest
+}
+
+// scheduleClusters - Puts one cluster on each day, moving outdoor-heavy clusters to dry days when some days are rainy (Days without a cluster are empty)
+func scheduleClusters(clusters [][]models.ItineraryStop, rainy []bool) [][]models.ItineraryStop {
+	schedule := make([][]models.ItineraryStop, len(rainy))
+
+	anyRain := false
+	for _, wet := range rainy {
+		anyRain = anyRain || wet
+	}
+	if !anyRain {
+		copy(schedule, clusters)
+		return schedule
+	}
+
+	// Most outdoor clusters take the dry days first, the rest fill the remaining days in order
+	order := make([]int, len(clusters))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return outdoorShare(clusters[order[i]]) > outdoorShare(clusters[order[j]])
+	})
+
+	placed := make([]bool, len(clusters))
+	for _, c := range order {
+		if outdoorShare(clusters[c]) <= OutdoorHeavyShare {
+			break
+		}
+		for day := range schedule {
+			if !rainy[day] && schedule[day] == nil {
+				schedule[day] = clusters[c]
+				placed[c] = true
+				break
+			}
+		}
+	}
+
+	// Indoor clusters prefer rainy days so dry days stay free for any outdoor cluster left
+	for _, preferRain := range []bool{true, false} {
+		for c, cluster := range clusters {
+			if placed[c] || (preferRain && outdoorShare(cluster) > OutdoorHeavyShare) {
+				continue
+			}
+			for day := range schedule {
+				if schedule[day] == nil && (rainy[day] || !preferRain) {
+					schedule[day] = cluster
+					placed[c] = true
+					break
+				}
+			}
+		}
+	}
+	return schedule
+}
+
+// outdoorShare - Fraction of the stops that are outdoors
+func outdoorShare(stops []models.ItineraryStop) float64 {
+	if len(stops) == 0 {
+		return 0
+	}
+	outdoor := 0
+	for _, stop := range stops {
+		if stop.Outdoor {
+			outdoor++
+		}
+	}
+	return float64(outdoor) / float64(len(stops))
+}
+
+// routeStops - Orders the stops as a walk from the start (Nearest neighbour, then 2-opt until no reversal shortens it) and sets each leg
+func routeStops(start geo.Point, stops []models.ItineraryStop) []models.ItineraryStop {
+	route := make([]models.ItineraryStop, 0, len(stops))
+	remaining := append([]models.ItineraryStop{}, stops...)
+
+	current := start
+	for len(remaining) > 0 {
+		nearest := 0
+		for i, stop := range remaining {
+			if geo.Distance(current, stopPoint(stop)) < geo.Distance(current, stopPoint(remaining[nearest])) {
+				nearest = i
+			}
+		}
+		route = append(route, remaining[nearest])
+		current = stopPoint(remaining[nearest])
+		remaining = append(remaining[:nearest], remaining[nearest+1:]...)
+	}
+
+	// 2-opt on an open path (The start is fixed, the walk ends at the last stop)
+	for improved := true; improved; {
+		improved = false
+		for i := 0; i < len(route)-1; i++ {
+			before := start
+			if i > 0 {
+				before = stopPoint(route[i-1])
+			}
+			for j := i + 1; j < len(route); j++ {
+				delta := geo.Distance(before, stopPoint(route[j])) - geo.Distance(before, stopPoint(route[i]))
+				if j+1 < len(route) {
+					after := stopPoint(route[j+1])
+					delta += geo.Distance(stopPoint(route[i]), after) - geo.Distance(stopPoint(route[j]), after)
+				}
+				if delta < -0.01 {
+					for a, b := i, j; a < b; a, b = a+1, b-1 {
+						route[a], route[b] = route[b], route[a]
+					}
+					improved = true
+				}
+			}
+		}
+	}
+
+	previous := start
+	for i := range route {
+		route[i].Leg = geo.NewLeg(previous, stopPoint(route[i]))
+		previous = stopPoint(route[i])
+	}
+	return route
+}
+
+func stopPoint(stop models.ItineraryStop) geo.Point {
+	return geo.Point{Lat: stop.Lat, Lon: stop.Lon}
+}
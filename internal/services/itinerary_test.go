@@ -0,0 +1,238 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/MCantyDev/city-explorer-server/internal/geo"
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+)
+
+// Points are given in thousandths of a degree around (0, 0), roughly 111 metres apart
+func stop(xid string, x float64, y float64, outdoor bool) models.ItineraryStop {
+	return models.ItineraryStop{Xid: xid, Lat: y / 1000, Lon: x / 1000, Rate: 3, Outdoor: outdoor}
+}
+
+func candidate(xid string, x float64, y float64, rate int, outdoor bool) itineraryCandidate {
+	s := stop(xid, x, y, outdoor)
+	s.Rate = rate
+	return itineraryCandidate{ItineraryStop: s, CentreDistance: geo.Distance(geo.Point{}, stopPoint(s))}
+}
+
+func xids(stops []models.ItineraryStop) []string {
+	ids := []string{}
+	for _, s := range stops {
+		ids = append(ids, s.Xid)
+	}
+	return ids
+}
+
+func TestClusterStops(t *testing.T) {
+	tests := []struct {
+		name       string
+		candidates []itineraryCandidate
+		k          int
+		capacity   int
+		want       [][]string
+	}{
+		{
+			name: "two neighbourhoods",
+			candidates: []itineraryCandidate{
+				candidate("w1", -10, 0, 3, false), candidate("e1", 10, 0, 3, false),
+				candidate("w2", -11, 1, 3, false), candidate("e2", 11, 1, 3, false),
+				candidate("w3", -10, -1, 3, false), candidate("e3", 10, -1, 3, false),
+			},
+			k: 2, capacity: 3,
+			want: [][]string{{"w1", "w2", "w3"}, {"e1", "e2", "e3"}},
+		},
+		{
+			name: "capacity moves the stop closest to the other cluster",
+			candidates: []itineraryCandidate{
+				candidate("w1", -10, 0, 3, false), candidate("w2", -11, 1, 3, false),
+				candidate("w3", -10, -1, 3, false), candidate("w4", -4, 0, 3, false),
+				candidate("e1", 10, 0, 3, false), candidate("e2", 11, 1, 3, false),
+			},
+			k: 2, capacity: 3,
+			want: [][]string{{"w1", "w2", "w3"}, {"w4", "e1", "e2"}},
+		},
+		{
+			name:       "fewer candidates than days",
+			candidates: []itineraryCandidate{candidate("a", 0, 1, 3, false), candidate("b", 0, 9, 3, false)},
+			k:          3, capacity: 5,
+			want: [][]string{{"a"}, {"b"}},
+		},
+		{
+			name: "no candidates",
+			k:    3, capacity: 5,
+			want: [][]string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := [][]string{}
+			for _, cluster := range clusterStops(test.candidates, test.k, test.capacity) {
+				got = append(got, xids(cluster))
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("clusterStops() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestScheduleClusters(t *testing.T) {
+	indoor := []models.ItineraryStop{stop("museum", 0, 1, false), stop("gallery", 0, 2, false)}
+	outdoor := []models.ItineraryStop{stop("park", 5, 1, true), stop("garden", 5, 2, true)}
+
+	tests := []struct {
+		name     string
+		clusters [][]models.ItineraryStop
+		rainy    []bool
+		want     []string // First stop of each day ("" for an empty day)
+	}{
+		{name: "no rain keeps the order", clusters: [][]models.ItineraryStop{outdoor, indoor}, rainy: []bool{false, false}, want: []string{"park", "museum"}},
+		{name: "outdoor day moves to the dry day", clusters: [][]models.ItineraryStop{outdoor, indoor}, rainy: []bool{true, false}, want: []string{"museum", "park"}},
+		{name: "indoor day takes the rainy day", clusters: [][]models.ItineraryStop{indoor, outdoor}, rainy: []bool{false, true}, want: []string{"park", "museum"}},
+		{name: "no dry day left", clusters: [][]models.ItineraryStop{outdoor, indoor}, rainy: []bool{true, true}, want: []string{"museum", "park"}},
+		{name: "more days than clusters", clusters: [][]models.ItineraryStop{outdoor}, rainy: []bool{true, false, false}, want: []string{"", "park", ""}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := []string{}
+			for _, day := range scheduleClusters(test.clusters, test.rainy) {
+				first := ""
+				if len(day) > 0 {
+					first = day[0].Xid
+				}
+				got = append(got, first)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("scheduleClusters() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRouteStops(t *testing.T) {
+	tests := []struct {
+		name  string
+		stops []models.ItineraryStop
+		want  []string
+	}{
+		{
+			name:  "nearest first along a street",
+			stops: []models.ItineraryStop{stop("c", 3, 0, false), stop("a", 1, 0, false), stop("b", 2, 0, false)},
+			want:  []string{"a", "b", "c"},
+		},
+		{
+			// Nearest neighbour leaves the stop behind the start until last, 2-opt visits it first
+			name:  "2-opt removes the walk back",
+			stops: []models.ItineraryStop{stop("n1", 0, 2, false), stop("n2", 1, 3, false), stop("n3", 0, 4, false), stop("s", -1, -2, false)},
+			want:  []string{"s", "n1", "n2", "n3"},
+		},
+		{
+			name:  "single stop",
+			stops: []models.ItineraryStop{stop("a", 1, 1, false)},
+			want:  []string{"a"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			route := routeStops(geo.Point{}, test.stops)
+			if got := xids(route); !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("routeStops() = %v, want %v", got, test.want)
+			}
+
+			// Each leg starts where the previous stop ended
+			previous := geo.Point{}
+			for _, s := range route {
+				if want := geo.NewLeg(previous, stopPoint(s)); s.Leg != want {
+					t.Errorf("leg to %s = %+v, want %+v", s.Xid, s.Leg, want)
+				}
+				previous = stopPoint(s)
+			}
+		})
+	}
+}
+
+func TestPlanDays(t *testing.T) {
+	// Museums west of the centre, parks east, one low rated sight that does not make the cut
+	candidates := func() []itineraryCandidate {
+		return []itineraryCandidate{
+			candidate("park-far", 12, 0, 3, true), candidate("museum-far", -12, 0, 3, false),
+			candidate("park-near", 8, 1, 3, true), candidate("museum-near", -8, 1, 3, false),
+			candidate("cafe", 1, 1, 1, false),
+		}
+	}
+	options := models.ItineraryOptions{Days: 2, PerDay: 2}
+
+	tests := []struct {
+		name    string
+		rainy   []bool
+		stops   [][]string
+		skipped [][]string
+	}{
+		{
+			name:    "dry days",
+			rainy:   []bool{false, false},
+			stops:   [][]string{{"park-near", "park-far"}, {"museum-near", "museum-far"}},
+			skipped: [][]string{{}, {}},
+		},
+		{
+			name:    "parks move off the rainy first day",
+			rainy:   []bool{true, false},
+			stops:   [][]string{{"museum-near", "museum-far"}, {"park-near", "park-far"}},
+			skipped: [][]string{{}, {}},
+		},
+		{
+			name:    "outdoor stops are skipped when every day is rainy",
+			rainy:   []bool{true, true},
+			stops:   [][]string{{"museum-near", "museum-far"}, {}},
+			skipped: [][]string{{}, {"park-near", "park-far"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			days := make([]models.ItineraryDay, options.Days)
+			planDays(days, candidates(), options, test.rainy)
+
+			for i, day := range days {
+				if day.Day != i+1 {
+					t.Errorf("days[%d].Day = %d, want %d", i, day.Day, i+1)
+				}
+				if got := xids(day.Stops); !reflect.DeepEqual(got, test.stops[i]) {
+					t.Errorf("day %d stops = %v, want %v", i+1, got, test.stops[i])
+				}
+				if got := xids(day.Skipped); !reflect.DeepEqual(got, test.skipped[i]) {
+					t.Errorf("day %d skipped = %v, want %v", i+1, got, test.skipped[i])
+				}
+			}
+		})
+	}
+}
+
+func TestIsHeavyRain(t *testing.T) {
+	tests := []struct {
+		name     string
+		forecast *models.WeatherDaily
+		want     bool
+	}{
+		{name: "no forecast", forecast: nil, want: false},
+		{name: "heavy rainfall", forecast: &models.WeatherDaily{Precipitation: 12}, want: true},
+		{name: "light rain", forecast: &models.WeatherDaily{Precipitation: 2, PrecipitationProbability: 90, Condition: models.WeatherCondition{Code: "light_rain", Group: "rain"}}, want: false},
+		{name: "likely thunderstorm", forecast: &models.WeatherDaily{Precipitation: 4, PrecipitationProbability: 70, Condition: models.WeatherCondition{Code: "thunderstorm", Group: "thunderstorm"}}, want: true},
+		{name: "unlikely heavy rain", forecast: &models.WeatherDaily{Precipitation: 4, PrecipitationProbability: 30, Condition: models.WeatherCondition{Code: "heavy_rain", Group: "rain"}}, want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := IsHeavyRain(test.forecast); got != test.want {
+				t.Errorf("IsHeavyRain() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package services
+
+/* OpenTripMap Category Tree
+
+- Subset of https://dev.opentripmap.org/catalog (Top level categories and their main sub categories)
+- Used to validate the 'kinds' search parameter
+*/
+
+// Parent Kind -> Child Kinds
+var openTripKinds = map[string][]string{
+	"interesting_places":    {"architecture", "cultural", "historic", "industrial_facilities", "natural", "other", "religion"},
+	"architecture":          {"bridges", "fortifications", "historic_architecture", "lighthouses", "skyscrapers", "towers"},
+	"cultural":              {"museums", "theatres_and_entertainments", "urban_environment"},
+	"historic":              {"archaeology", "burial_places", "fortifications", "historical_places", "monuments_and_memorials"},
+	"industrial_facilities": {"factories", "mineshafts", "power_stations", "railway_stations"},
+	"natural":               {"beaches", "geological_formations", "islands", "natural_springs", "nature_reserves", "water"},
+	"religion":              {"buddhist_temples", "cathedrals", "churches", "hindu_temples", "monasteries", "mosques", "synagogues"},
+	"other":                 {"gardens_and_parks", "installation", "tourist_object", "view_points"},
+	"tourist_facilities":    {"banks", "foods", "shops", "transport"},
+	"foods":                 {"bakeries", "bars", "biergartens", "cafes", "fast_food", "food_courts", "pubs", "restaurants"},
+	"shops":                 {"conveniences", "malls", "marketplace", "outdoor", "supermarkets"},
+	"transport":             {"bicycle_rental", "boat_sharing", "car_rental", "car_sharing", "charging_station", "fuel"},
+	"banks":                 {"atm", "bank", "bureau_de_change"},
+	"accomodations":         {"alpine_hut", "apartments", "campsites", "guest_houses", "hostels", "other_hotels", "resorts", "villas_and_chalet"},
+	"amusements":            {"amusement_parks", "baths_and_saunas", "ferris_wheels", "miniature_parks", "roller_coasters", "water_parks"},
+	"sport":                 {"climbing", "diving", "kitesurfing", "pools", "stadiums", "surfing", "winter_sports"},
+	"adult":                 {"alcohol", "casino", "hookah", "nightclubs"},
+}
+
+// Top level categories (Roots of the tree)
+var openTripRootKinds = []string{"interesting_places", "tourist_facilities", "accomodations", "amusements", "sport", "adult"}
+
+// IsOpenTripKind - True if the kind is anywhere in the category tree
+func IsOpenTripKind(kind string) bool {
+	if _, ok := openTripKinds[kind]; ok {
+		return true
+	}
+	for _, children := range openTripKinds {
+		for _, child := range children {
+			if child == kind {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package services
+
+/* POI Category Taxonomy
+
+- Our own category ids, display names and icons (The frontend no longer parses OpenTripMap kinds)
+- Each category maps to one or more OpenTripMap kinds, a place belongs to every category matching one of its kinds (and their parents)
+*/
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+)
+
+// Taxonomy (Top level categories in display order)
+var poiCategoryTree = []models.PoiCategory{
+	{Id: "culture", Name: "Culture", Icon: "theater_comedy", Kinds: []string{"cultural", "urban_environment"}, Children: []models.PoiCategory{
+		{Id: "museums", Name: "Museums", Icon: "museum", Kinds: []string{"museums"}},
+		{Id: "theatres", Name: "Theatres", Icon: "theaters", Kinds: []string{"theatres_and_entertainments"}},
+	}},
+	{Id: "history", Name: "History", Icon: "history_edu", Kinds: []string{"historic", "historical_places", "archaeology", "burial_places"}, Children: []models.PoiCategory{
+		{Id: "monuments", Name: "Monuments & Memorials", Icon: "account_balance", Kinds: []string{"monuments_and_memorials"}},
+		{Id: "castles", Name: "Castles & Fortifications", Icon: "castle", Kinds: []string{"fortifications"}},
+	}},
+	{Id: "architecture", Name: "Architecture", Icon: "location_city", Kinds: []string{"architecture", "historic_architecture", "bridges", "towers", "skyscrapers", "lighthouses"}},
+	{Id: "religion", Name: "Religious Sites", Icon: "church", Kinds: []string{"religion", "churches", "cathedrals", "monasteries", "mosques", "synagogues", "buddhist_temples", "hindu_temples"}},
+	{Id: "nature", Name: "Nature", Icon: "forest", Kinds: []string{"natural", "nature_reserves", "water", "islands", "geological_formations", "natural_springs"}, Children: []models.PoiCategory{
+		{Id: "parks", Name: "Parks & Gardens", Icon: "park", Kinds: []string{"gardens_and_parks"}},
+		{Id: "beaches", Name: "Beaches", Icon: "beach_access", Kinds: []string{"beaches"}},
+		{Id: "viewpoints", Name: "Viewpoints", Icon: "landscape", Kinds: []string{"view_points"}},
+	}},
+	{Id: "food", Name: "Food & Drink", Icon: "restaurant", Kinds: []string{"foods"}, Children: []models.PoiCategory{
+		{Id: "restaurants", Name: "Restaurants", Icon: "restaurant_menu", Kinds: []string{"restaurants", "fast_food", "food_courts"}},
+		{Id: "cafes", Name: "Cafes & Bakeries", Icon: "local_cafe", Kinds: []string{"cafes", "bakeries"}},
+		{Id: "bars", Name: "Bars & Pubs", Icon: "local_bar", Kinds: []string{"bars", "pubs", "biergartens"}},
+	}},
+	{Id: "shopping", Name: "Shopping", Icon: "shopping_bag", Kinds: []string{"shops", "malls", "marketplace", "supermarkets", "conveniences", "outdoor"}},
+	{Id: "accommodation", Name: "Accommodation", Icon: "hotel", Kinds: []string{"accomodations", "other_hotels", "hostels", "guest_houses", "apartments", "campsites", "resorts", "villas_and_chalet", "alpine_hut"}},
+	{Id: "entertainment", Name: "Entertainment", Icon: "attractions", Kinds: []string{"amusements", "amusement_parks", "water_parks", "roller_coasters", "ferris_wheels", "miniature_parks", "baths_and_saunas"}},
+	{Id: "sport", Name: "Sport", Icon: "sports_soccer", Kinds: []string{"sport", "stadiums", "pools", "climbing", "diving", "surfing", "kitesurfing", "winter_sports"}},
+	{Id: "nightlife", Name: "Nightlife", Icon: "nightlife", Kinds: []string{"adult", "nightclubs", "casino", "alcohol", "hookah"}},
+	{Id: "transport", Name: "Transport", Icon: "directions_bus", Kinds: []string{"transport", "railway_stations", "car_rental", "bicycle_rental", "car_sharing", "boat_sharing", "charging_station", "fuel"}},
+	{Id: "services", Name: "Services", Icon: "atm", Kinds: []string{"banks", "atm", "bank", "bureau_de_change"}},
+}
+
+// Lookups built from the tree
+var (
+	poiCategoryOrder  []string                          // Every category id in display order
+	poiCategoriesById = map[string]models.PoiCategory{} // Children are not included
+	poiCategoryByKind = map[string][]string{}           // OpenTripMap kind -> category ids
+)
+
+func init() {
+	var index func(categories []models.PoiCategory, parent string)
+	index = func(categories []models.PoiCategory, parent string) {
+		for i := range categories {
+			category := &categories[i]
+			category.Parent = parent
+
+			flat := *category
+			flat.Children = nil
+			poiCategoryOrder = append(poiCategoryOrder, category.Id)
+			poiCategoriesById[category.Id] = flat
+			for _, kind := range category.Kinds {
+				poiCategoryByKind[kind] = append(poiCategoryByKind[kind], category.Id)
+			}
+
+			index(category.Children, category.Id)
+		}
+	}
+	index(poiCategoryTree, "")
+}
+
+// PoiCategories - The full category tree
+func PoiCategories() []models.PoiCategory {
+	return poiCategoryTree
+}
+
+// ParsePoiCategories - Validates a comma separated list of category ids
+func ParsePoiCategories(value string) ([]string, error) {
+	var ids []string
+	seen := map[string]bool{}
+	for _, id := range strings.Split(value, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		if _, ok := poiCategoriesById[id]; !ok {
+			return nil, fmt.Errorf("unknown category '%s'", id)
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// PoiCategoryKinds - The OpenTripMap kinds to search for a set of categories (Comma separated)
+func PoiCategoryKinds(ids []string) string {
+	var kinds []string
+	seen := map[string]bool{}
+	for _, id := range ids {
+		for _, kind := range poiCategoriesById[id].Kinds {
+			if !seen[kind] && IsOpenTripKind(kind) {
+				seen[kind] = true
+				kinds = append(kinds, kind)
+			}
+		}
+	}
+	return strings.Join(kinds, ",")
+}
+
+// CategoriesForKinds - Maps an OpenTripMap kinds string (e.g. "museums,cultural,interesting_places") to our category ids (Parents included)
+func CategoriesForKinds(kinds string) []string {
+	matched := map[string]bool{}
+	for _, kind := range strings.Split(kinds, ",") {
+		for _, id := range poiCategoryByKind[strings.TrimSpace(kind)] {
+			matched[id] = true
+			if parent := poiCategoriesById[id].Parent; parent != "" {
+				matched[parent] = true
+			}
+		}
+	}
+
+	categories := []string{}
+	for _, id := range poiCategoryOrder {
+		if matched[id] {
+			categories = append(categories, id)
+		}
+	}
+	return categories
+}
+
+// HasPoiCategory - True if any of the place categories is in the filter (An empty filter matches everything)
+func HasPoiCategory(categories []string, filter []string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	for _, category := range categories {
+		for _, wanted := range filter {
+			if category == wanted {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,378 @@
+package services
+
+/* POI Reviews
+
+- Users rate (1 - 5) and optionally review OpenTripMap POIs by xid, once per POI (Editing replaces their review)
+- The average of the visible reviews is added to the POI and sights responses next to OpenTripMap's own 'rate'
+- Users can report (flag) other users' reviews, admins hide, flag, unflag or delete them (Hidden reviews only show to their author)
+*/
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/MCantyDev/city-explorer-server/internal/database"
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+)
+
+const (
+	MaxReviewLength      = 2000
+	MaxFlagReasonLength  = 255
+	DefaultReviewsPage   = 20
+	MaxReviewsPage       = 50
+	MaxReviewsPageNumber = 10000 // Keeps the offset well within range
+	MaxUserReviewsPerDay = 50    // New reviews per user in 24 hours
+)
+
+var (
+	ErrReviewNotFound    = errors.New("review not found")
+	ErrReviewExists      = errors.New("you have already reviewed this place, edit your review instead")
+	ErrReviewPoiNotFound = errors.New("unknown POI, it must be viewed before it can be reviewed")
+	ErrReviewLimit       = fmt.Errorf("at most %d reviews can be written a day", MaxUserReviewsPerDay)
+	ErrInvalidReview     = errors.New("invalid review")
+)
+
+// ParseReviewsPage - Validates the page and page size (Empty uses the defaults)
+func ParseReviewsPage(page string, pageSize string) (int, int, error) {
+	number, size := 1, DefaultReviewsPage
+	if page != "" {
+		value, err := strconv.Atoi(page)
+		// Capped so the offset cannot overflow
+		if err != nil || value < 1 || value > MaxReviewsPageNumber {
+			return 0, 0, fmt.Errorf("%w: 'page' must be between 1 and %d", ErrInvalidReview, MaxReviewsPageNumber)
+		}
+		number = value
+	}
+	if pageSize != "" {
+		value, err := strconv.Atoi(pageSize)
+		if err != nil || value < 1 || value > MaxReviewsPage {
+			return 0, 0, fmt.Errorf("%w: 'page-size' must be between 1 and %d", ErrInvalidReview, MaxReviewsPage)
+		}
+		size = value
+	}
+	return number, size, nil
+}
+
+// ListPoiReviews - The visible reviews of a POI (newest first) with the average, the star distribution and the user's own review
+func ListPoiReviews(userId uint, xid string, page int, pageSize int) (*models.PoiReviewsPage, error) {
+	result := &models.PoiReviewsPage{
+		Xid:          xid,
+		Distribution: map[int]int{1: 0, 2: 0, 3: 0, 4: 0, 5: 0},
+		Results:      []models.PoiReviewView{},
+		Page:         page,
+		PageSize:     pageSize,
+	}
+
+	var counts []struct {
+		Rating int
+		Total  int
+	}
+	query := database.NewQueryBuilder("SELECT").Table("poi_reviews").Columns("rating", "COUNT(*) AS total").Where("xid = ?").Where("hidden = FALSE").GroupBy("rating").Build()
+	if _, err := database.Execute(&counts, query, xid); err != nil {
+		return nil, err
+	}
+	sum := 0
+	for _, count := range counts {
+		result.Distribution[count.Rating] = count.Total
+		result.Total += count.Total
+		sum += count.Rating * count.Total
+	}
+	if result.Total > 0 {
+		result.UserRating = &models.PoiRating{Average: roundRating(float64(sum) / float64(result.Total)), Count: result.Total}
+	}
+
+	var reviews []models.PoiReview
+	query = reviewQuery().Where("poi_reviews.xid = ?").Where("poi_reviews.hidden = FALSE").OrderBy("poi_reviews.created_at DESC, poi_reviews.id DESC").Limit().Offset().Build()
+	if _, err := database.Execute(&reviews, query, xid, pageSize, (page-1)*pageSize); err != nil {
+		return nil, err
+	}
+	for _, review := range reviews {
+		result.Results = append(result.Results, poiReviewView(review))
+	}
+
+	var own models.PoiReview
+	query = reviewQuery().Where("poi_reviews.xid = ?").Where("poi_reviews.user_id = ?").Build()
+	if _, err := database.Execute(&own, query, xid, userId); err != nil {
+		return nil, err
+	}
+	if own.Id > 0 {
+		view := poiReviewView(own)
+		result.Own = &view
+	}
+	return result, nil
+}
+
+// CreatePoiReview - Saves the user's rating and review of a POI (One per user and POI)
+func CreatePoiReview(userId uint, xid string, rating int, review string) (*models.PoiReviewView, error) {
+	review = strings.TrimSpace(review)
+	if err := validateReview(rating, review); err != nil {
+		return nil, err
+	}
+	if GetCachedPoi(xid) == nil {
+		return nil, ErrReviewPoiNotFound
+	}
+
+	var existing models.PoiReview
+	query := database.NewQueryBuilder("SELECT").Table("poi_reviews").Columns("id").Where("xid = ?").Where("user_id = ?").Build()
+	if _, err := database.Execute(&existing, query, xid, userId); err != nil {
+		return nil, err
+	}
+	if existing.Id > 0 {
+		return nil, ErrReviewExists
+	}
+
+	var recent struct{ Total int }
+	query = database.NewQueryBuilder("SELECT").Table("poi_reviews").Columns("COUNT(*) AS total").Where("user_id = ?").Where("created_at > NOW() - INTERVAL 1 DAY").Build()
+	if _, err := database.Execute(&recent, query, userId); err != nil {
+		return nil, err
+	}
+	if recent.Total >= MaxUserReviewsPerDay {
+		return nil, ErrReviewLimit
+	}
+
+	saved := models.PoiReview{
+		Xid:    xid,
+		UserId: userId,
+		Rating: rating,
+		Review: nullableText(review),
+	}
+	if _, err := database.Execute(&saved, "INSERT"); err != nil {
+		// Another request saved a review for the same POI since the check above
+		if database.IsDuplicateKey(err) {
+			return nil, ErrReviewExists
+		}
+		return nil, err
+	}
+	return GetPoiReview(userId, saved.Id)
+}
+
+// GetPoiReview - One of the user's own reviews
+func GetPoiReview(userId uint, id uint) (*models.PoiReviewView, error) {
+	review, err := loadOwnReview(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	view := poiReviewView(*review)
+	return &view, nil
+}
+
+// UpdatePoiReview - Changes the user's rating and / or review (nil values are kept)
+func UpdatePoiReview(userId uint, id uint, rating *int, text *string) (*models.PoiReviewView, error) {
+	review, err := loadOwnReview(userId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if rating != nil {
+		review.Rating = *rating
+	}
+	if text != nil {
+		review.Review = nullableText(strings.TrimSpace(*text))
+	}
+	if err := validateReview(review.Rating, deref(review.Review)); err != nil {
+		return nil, err
+	}
+
+	query := database.NewQueryBuilder("UPDATE").Table("poi_reviews").Columns("rating", "review").Where("id = ?").Build()
+	if _, err := database.Execute(nil, query, review.Rating, review.Review, review.Id); err != nil {
+		return nil, err
+	}
+	return GetPoiReview(userId, review.Id)
+}
+
+// DeletePoiReview - Removes one of the user's own reviews
+func DeletePoiReview(userId uint, id uint) error {
+	review, err := loadOwnReview(userId, id)
+	if err != nil {
+		return err
+	}
+
+	query := database.NewQueryBuilder("DELETE").Table("poi_reviews").Where("id = ?").Build()
+	_, err = database.Execute(nil, query, review.Id)
+	return err
+}
+
+// ReportPoiReview - Flags another user's visible review for the admins (The latest reason is kept)
+func ReportPoiReview(userId uint, id uint, reason string) error {
+	review, err := loadReview(id)
+	if err != nil {
+		return err
+	}
+	if review.Hidden {
+		return ErrReviewNotFound
+	}
+	if review.UserId == userId {
+		return fmt.Errorf("%w: you cannot report your own review", ErrInvalidReview)
+	}
+
+	return SetPoiReviewFlag(review.Id, true, reason)
+}
+
+// PoiRatings - Average of the visible reviews for each xid (xids without reviews are left out)
+func PoiRatings(xids []string) (map[string]models.PoiRating, error) {
+	ratings := map[string]models.PoiRating{}
+	if len(xids) == 0 {
+		return ratings, nil
+	}
+
+	var rows []struct {
+		Xid     string
+		Average float64
+		Total   int
+	}
+	query := database.NewQueryBuilder("SELECT").Table("poi_reviews").Columns("xid", "AVG(rating) AS average", "COUNT(*) AS total").Where("xid IN ?").Where("hidden = FALSE").GroupBy("xid").Build()
+	if _, err := database.Execute(&rows, query, xids); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		ratings[row.Xid] = models.PoiRating{Average: roundRating(row.Average), Count: row.Total}
+	}
+	return ratings, nil
+}
+
+// AddSightRatings - Sets the user rating of every feature on a sights page
+func AddSightRatings(page *models.SightsPage) error {
+	xids := make([]string, 0, len(page.Features))
+	for _, feature := range page.Features {
+		xids = append(xids, feature.Properties.Xid)
+	}
+	ratings, err := PoiRatings(xids)
+	if err != nil {
+		return err
+	}
+
+	for i := range page.Features {
+		if rating, ok := ratings[page.Features[i].Properties.Xid]; ok {
+			page.Features[i].Properties.UserRating = &rating
+		}
+	}
+	return nil
+}
+
+// ListModerationReviews - Every review for the admin dashboard, newest first (Optionally only flagged or hidden ones, or one POI)
+func ListModerationReviews(flagged bool, hidden bool, xid string) ([]models.PoiReview, error) {
+	builder := reviewQuery()
+	args := []any{}
+	if flagged {
+		builder.Where("poi_reviews.flagged = TRUE")
+	}
+	if hidden {
+		builder.Where("poi_reviews.hidden = TRUE")
+	}
+	if xid != "" {
+		builder.Where("poi_reviews.xid = ?")
+		args = append(args, xid)
+	}
+
+	reviews := []models.PoiReview{}
+	query := builder.OrderBy("poi_reviews.created_at DESC, poi_reviews.id DESC").Build()
+	_, err := database.Execute(&reviews, query, args...)
+	return reviews, err
+}
+
+// HidePoiReview - Hides a review from everyone but its author (or shows it again), hiding clears the flag
+func HidePoiReview(id uint, hidden bool) error {
+	review, err := loadReview(id)
+	if err != nil {
+		return err
+	}
+
+	flagged := review.Flagged && !hidden
+	query := database.NewQueryBuilder("UPDATE").Table("poi_reviews").Columns("hidden", "flagged").Where("id = ?").Build()
+	_, err = database.Execute(nil, query, hidden, flagged, review.Id)
+	return err
+}
+
+// SetPoiReviewFlag - Flags a review for moderation or clears the flag (and its reason)
+func SetPoiReviewFlag(id uint, flagged bool, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if utf8.RuneCountInString(reason) > MaxFlagReasonLength {
+		return fmt.Errorf("%w: 'reason' must be at most %d characters", ErrInvalidReview, MaxFlagReasonLength)
+	}
+	review, err := loadReview(id)
+	if err != nil {
+		return err
+	}
+	if !flagged {
+		reason = ""
+	}
+
+	query := database.NewQueryBuilder("UPDATE").Table("poi_reviews").Columns("flagged", "flag_reason").Where("id = ?").Build()
+	_, err = database.Execute(nil, query, flagged, nullableText(reason), review.Id)
+	return err
+}
+
+// RemovePoiReview - Deletes any review (Admins)
+func RemovePoiReview(id uint) error {
+	query := database.NewQueryBuilder("DELETE").Table("poi_reviews").Where("id = ?").Build()
+	rows, err := database.Execute(nil, query, id)
+	if err != nil {
+		return err
+	}
+	if affected, ok := rows.(int64); ok && affected == 0 {
+		return ErrReviewNotFound
+	}
+	return nil
+}
+
+func loadReview(id uint) (*models.PoiReview, error) {
+	var review models.PoiReview
+	query := reviewQuery().Where("poi_reviews.id = ?").Build()
+	if _, err := database.Execute(&review, query, id); err != nil {
+		return nil, err
+	}
+	if review.Id == 0 {
+		return nil, ErrReviewNotFound
+	}
+	return &review, nil
+}
+
+func loadOwnReview(userId uint, id uint) (*models.PoiReview, error) {
+	var review models.PoiReview
+	query := reviewQuery().Where("poi_reviews.id = ?").Where("poi_reviews.user_id = ?").Build()
+	if _, err := database.Execute(&review, query, id, userId); err != nil {
+		return nil, err
+	}
+	if review.Id == 0 {
+		return nil, ErrReviewNotFound
+	}
+	return &review, nil
+}
+
+func validateReview(rating int, review string) error {
+	if rating < 1 || rating > 5 {
+		return fmt.Errorf("%w: 'rating' must be between 1 and 5", ErrInvalidReview)
+	}
+	if utf8.RuneCountInString(review) > MaxReviewLength {
+		return fmt.Errorf("%w: reviews must be at most %d characters", ErrInvalidReview, MaxReviewLength)
+	}
+	return nil
+}
+
+func reviewQuery() *database.QueryBuilder {
+	return database.NewQueryBuilder("SELECT").Table("poi_reviews").
+		Columns("poi_reviews.*", "users.username").
+		Join("JOIN users ON users.id = poi_reviews.user_id")
+}
+
+func poiReviewView(review models.PoiReview) models.PoiReviewView {
+	return models.PoiReviewView{
+		Id:        review.Id,
+		Xid:       review.Xid,
+		Username:  review.Username,
+		Rating:    review.Rating,
+		Review:    deref(review.Review),
+		Hidden:    review.Hidden,
+		CreatedAt: review.CreatedAt,
+		UpdatedAt: review.UpdatedAt,
+	}
+}
+
+// roundRating - Averages are shown to 1 decimal
+func roundRating(average float64) float64 {
+	return math.Round(average*10) / 10
+}
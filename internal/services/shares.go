@@ -0,0 +1,222 @@
+package services
+
+/* Trip Shares
+
+- Unguessable public links to a read-only copy of a trip (Notes are left out)
+- Tokens are 32 random bytes, only their SHA-256 is stored so the database cannot be used to open a link
+- Optional expiry and password (bcrypt)
+- Only the trip's owner can create, list or revoke links
+*/
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MCantyDev/city-explorer-server/internal/database"
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+)
+
+const (
+	MaxTripShares          = 20
+	MaxShareLifetime       = 365 * 24 * time.Hour
+	MinSharePasswordLength = 4
+	MaxSharePasswordLength = 72 // bcrypt limit (Bytes)
+
+	shareTokenBytes = 32
+	shareHintLength = 6
+)
+
+var (
+	ErrShareNotFound         = errors.New("share link not found")
+	ErrShareExpired          = errors.New("share link has expired")
+	ErrSharePasswordRequired = errors.New("share link requires a password")
+	ErrSharePasswordWrong    = errors.New("incorrect share link password")
+	ErrInvalidShare          = errors.New("invalid share link")
+)
+
+// CreateTripShare - A new link to the trip (The token is only returned here)
+func CreateTripShare(userId uint, tripId uint, expiresAt *time.Time, password string) (*models.TripShareView, error) {
+	trip, err := loadTrip(userId, tripId, models.TripRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	if expiresAt != nil {
+		if !expiresAt.After(time.Now()) {
+			return nil, fmt.Errorf("%w: 'expires_at' must be in the future", ErrInvalidShare)
+		}
+		if time.Until(*expiresAt) > MaxShareLifetime {
+			return nil, fmt.Errorf("%w: 'expires_at' must be within a year", ErrInvalidShare)
+		}
+	}
+
+	var count struct{ Total int }
+	query := database.NewQueryBuilder("SELECT").Table("trip_shares").Columns("COUNT(*) AS total").Where("trip_id = ?").Build()
+	if _, err := database.Execute(&count, query, trip.Id); err != nil {
+		return nil, err
+	}
+	if count.Total >= MaxTripShares {
+		return nil, fmt.Errorf("%w: trips can have at most %d share links", ErrInvalidShare, MaxTripShares)
+	}
+
+	token, err := newShareToken()
+	if err != nil {
+		return nil, err
+	}
+	share := models.TripShare{
+		TripId:    trip.Id,
+		TokenHash: hashShareToken(token),
+		TokenHint: token[:shareHintLength],
+		ExpiresAt: expiresAt,
+	}
+
+	if password != "" {
+		if len(password) < MinSharePasswordLength || len(password) > MaxSharePasswordLength {
+			return nil, fmt.Errorf("%w: passwords must be between %d and %d characters", ErrInvalidShare, MinSharePasswordLength, MaxSharePasswordLength)
+		}
+		hashed, err := HashPassword(password)
+		if err != nil {
+			return nil, err
+		}
+		share.PasswordHash = &hashed
+	}
+
+	if _, err := database.Execute(&share, "INSERT"); err != nil {
+		return nil, err
+	}
+
+	view := tripShareView(share)
+	view.Token = token
+	view.Path = "/shared-trips/" + token
+	return &view, nil
+}
+
+// ListTripShares - The trip's links, newest first (Tokens are not included)
+func ListTripShares(userId uint, tripId uint) ([]models.TripShareView, error) {
+	trip, err := loadTrip(userId, tripId, models.TripRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	var shares []models.TripShare
+	query := database.NewQueryBuilder("SELECT").Table("trip_shares").Where("trip_id = ?").OrderBy("created_at DESC, id DESC").Build()
+	if _, err := database.Execute(&shares, query, trip.Id); err != nil {
+		return nil, err
+	}
+
+	views := make([]models.TripShareView, 0, len(shares))
+	for _, share := range shares {
+		views = append(views, tripShareView(share))
+	}
+	return views, nil
+}
+
+// RevokeTripShare - Deletes a link (It stops working straight away)
+func RevokeTripShare(userId uint, tripId uint, shareId uint) error {
+	trip, err := loadTrip(userId, tripId, models.TripRoleOwner)
+	if err != nil {
+		return err
+	}
+
+	query := database.NewQueryBuilder("DELETE").Table("trip_shares").Where("id = ?").Where("trip_id = ?").Build()
+	rows, err := database.Execute(nil, query, shareId, trip.Id)
+	if err != nil {
+		return err
+	}
+	if affected, ok := rows.(int64); ok && affected == 0 {
+		return ErrShareNotFound
+	}
+	return nil
+}
+
+// GetSharedTrip - The read-only trip behind a link (Checks the expiry and password, then counts the view)
+func GetSharedTrip(token string, password string) (*models.SharedTrip, error) {
+	var share models.TripShare
+	query := database.NewQueryBuilder("SELECT").Table("trip_shares").Where("token_hash = ?").Build()
+	if _, err := database.Execute(&share, query, hashShareToken(token)); err != nil {
+		return nil, err
+	}
+	if share.Id == 0 {
+		return nil, ErrShareNotFound
+	}
+	if share.ExpiresAt != nil && !share.ExpiresAt.After(time.Now()) {
+		return nil, ErrShareExpired
+	}
+	if share.PasswordHash != nil {
+		if password == "" {
+			return nil, ErrSharePasswordRequired
+		}
+		if !CompareHashed(*share.PasswordHash, password) {
+			return nil, ErrSharePasswordWrong
+		}
+	}
+
+	var trip models.Trip
+	query = database.NewQueryBuilder("SELECT").Table("trips").Where("id = ?").Build()
+	if _, err := database.Execute(&trip, query, share.TripId); err != nil {
+		return nil, err
+	}
+	if trip.Id == 0 {
+		return nil, ErrShareNotFound
+	}
+
+	view, err := tripView(&trip)
+	if err != nil {
+		return nil, err
+	}
+
+	// Counted in SQL so views at the same time are not lost
+	query = database.NewQueryBuilder("UPDATE").Table("trip_shares").Columns("last_viewed_at").SetRaw("views = views + 1").Where("id = ?").Build()
+	if _, err := database.Execute(nil, query, time.Now(), share.Id); err != nil {
+		return nil, err
+	}
+
+	// Notes are private to the trip's members
+	for i := range view.Plan {
+		for j := range view.Plan[i].Items {
+			view.Plan[i].Items[j].Notes = ""
+		}
+	}
+
+	return &models.SharedTrip{
+		Name:      view.Name,
+		StartDate: view.StartDate,
+		EndDate:   view.EndDate,
+		Days:      view.Days,
+		Cities:    view.Cities,
+		Plan:      view.Plan,
+		ExpiresAt: share.ExpiresAt,
+	}, nil
+}
+
+func tripShareView(share models.TripShare) models.TripShareView {
+	return models.TripShareView{
+		Id:           share.Id,
+		TokenHint:    share.TokenHint,
+		HasPassword:  share.PasswordHash != nil,
+		ExpiresAt:    share.ExpiresAt,
+		Expired:      share.ExpiresAt != nil && !share.ExpiresAt.After(time.Now()),
+		Views:        share.Views,
+		LastViewedAt: share.LastViewedAt,
+		CreatedAt:    share.CreatedAt,
+	}
+}
+
+// newShareToken - 32 random bytes, URL safe base64 (43 characters)
+func newShareToken() (string, error) {
+	bytes := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+func hashShareToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
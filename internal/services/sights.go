@@ -0,0 +1,211 @@
+package services
+
+/* Sights Search
+
+- Validates client search parameters (radius, kinds, rating) against the OpenTripMap category tree
+- Builds a cache key per search combination
+- Paginates the cached OpenTripMap features
+*/
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/MCantyDev/city-explorer-server/internal/config"
+	"github.com/MCantyDev/city-explorer-server/internal/geo"
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+)
+
+// Search Defaults (Match the original hard-coded OpenTripMap search)
+const (
+	DefaultSightsRadius   = 2000
+	DefaultSightsKinds    = "amusements,accomodations,tourist_facilities"
+	DefaultSightsRate     = "2"
+	MinSightsRadius       = 100
+	MaxSightsRadius       = 20000
+	SightsFetchLimit      = 500 // OpenTripMap maximum, pages are served from the cached result
+	DefaultSightsPageSize = 20
+	MaxSightsPageSize     = 100
+	MaxSightsPage         = SightsFetchLimit // Last page with a page size of 1
+)
+
+// Sort Orders (Default keeps the OpenTripMap order)
+const (
+	SightsSortDefault  = ""
+	SightsSortDistance = "distance"
+	SightsSortRating   = "rating"
+)
+
+var sightsRates = []string{"1", "2", "3", "1h", "2h", "3h"}
+
+// ParseSightsSearch - Validates the search parameters (Empty values use the defaults)
+func ParseSightsSearch(radius string, kinds string, rate string) (models.SightsSearch, error) {
+	search := models.SightsSearch{
+		Radius: DefaultSightsRadius,
+		Rate:   DefaultSightsRate,
+	}
+
+	if radius != "" {
+		parsed, err := strconv.Atoi(radius)
+		if err != nil || parsed < MinSightsRadius || parsed > MaxSightsRadius {
+			return search, fmt.Errorf("'radius' must be between %d and %d metres", MinSightsRadius, MaxSightsRadius)
+		}
+		search.Radius = parsed
+	}
+
+	if kinds == "" {
+		kinds = DefaultSightsKinds
+	}
+	seen := map[string]bool{}
+	for _, kind := range strings.Split(kinds, ",") {
+		kind = strings.TrimSpace(kind)
+		if kind == "" || seen[kind] {
+			continue
+		}
+		if !IsOpenTripKind(kind) {
+			return search, fmt.Errorf("unknown kind '%s'", kind)
+		}
+		seen[kind] = true
+		search.Kinds = append(search.Kinds, kind)
+	}
+	if len(search.Kinds) == 0 {
+		return search, fmt.Errorf("'kinds' must contain at least one kind")
+	}
+	sort.Strings(search.Kinds)
+
+	if rate != "" {
+		valid := false
+		for _, r := range sightsRates {
+			valid = valid || r == rate
+		}
+		if !valid {
+			return search, fmt.Errorf("'rate' must be one of: %s", strings.Join(sightsRates, ", "))
+		}
+		search.Rate = rate
+	}
+
+	return search, nil
+}
+
+// SightsSearchKey - Stable cache key for a search combination
+func SightsSearchKey(search models.SightsSearch) string {
+	return fmt.Sprintf("kinds=%s&radius=%d&rate=%s", strings.Join(search.Kinds, ","), search.Radius, search.Rate)
+}
+
+// FetchSights - Fetches sights from OpenTripMap for a search combination
+func FetchSights(lat string, lon string, search models.SightsSearch) ([]byte, error) {
+	api := config.Cfg.OpenTripAPI
+
+	url := fmt.Sprintf(api.URL, lat, lon, search.Radius, SightsFetchLimit, strings.Join(search.Kinds, ","), search.Rate, api.Key)
+	if !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("'%s' URL is not setup properly", api.Name)
+	}
+
+	return FetchExternalAPI(url)
+}
+
+// ParseSightsPage - Reads 'sort', 'cursor' or 'page' and 'page-size' (A cursor takes priority over a page)
+func ParseSightsPage(sort string, page string, pageSize string, cursor string) (models.SightsPageOptions, error) {
+	options := models.SightsPageOptions{PageSize: DefaultSightsPageSize, Sort: sort}
+
+	if sort != SightsSortDefault && sort != SightsSortDistance && sort != SightsSortRating {
+		return options, fmt.Errorf("'sort' must be '%s' or '%s'", SightsSortDistance, SightsSortRating)
+	}
+
+	if pageSize != "" {
+		parsed, err := strconv.Atoi(pageSize)
+		if err != nil || parsed < 1 || parsed > MaxSightsPageSize {
+			return options, fmt.Errorf("'page-size' must be between 1 and %d", MaxSightsPageSize)
+		}
+		options.PageSize = parsed
+	}
+
+	if cursor != "" {
+		decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil {
+			return options, fmt.Errorf("invalid 'cursor'")
+		}
+		offset, err := strconv.Atoi(strings.TrimPrefix(string(decoded), "offset:"))
+		if err != nil || offset < 0 {
+			return options, fmt.Errorf("invalid 'cursor'")
+		}
+		options.Offset = offset
+		return options, nil
+	}
+
+	if page != "" {
+		parsed, err := strconv.Atoi(page)
+		// Capped so the offset cannot overflow
+		if err != nil || parsed < 1 || parsed > MaxSightsPage {
+			return options, fmt.Errorf("'page' must be between 1 and %d", MaxSightsPage)
+		}
+		options.Offset = (parsed - 1) * options.PageSize
+	}
+
+	return options, nil
+}
+
+// PaginateSights - Adds categories and the leg from the anchor to the cached GeoJSON features, then filters, sorts and slices them into a page
+func PaginateSights(data []byte, options models.SightsPageOptions) (*models.SightsPage, error) {
+	var collection struct {
+		Type     string                `json:"type"`
+		Features []models.SightFeature `json:"features"`
+	}
+	if err := json.Unmarshal(data, &collection); err != nil {
+		return nil, fmt.Errorf("invalid sights data: %s", err)
+	}
+
+	facets := map[string]int{}
+	features := []models.SightFeature{}
+	for _, feature := range collection.Features {
+		feature.Properties.Categories = CategoriesForKinds(feature.Properties.Kinds)
+		if coordinates := feature.Geometry.Coordinates; len(coordinates) >= 2 {
+			feature.Properties.Leg = geo.NewLeg(options.Anchor, geo.Point{Lat: coordinates[1], Lon: coordinates[0]})
+		}
+		for _, category := range feature.Properties.Categories {
+			facets[category]++
+		}
+		if HasPoiCategory(feature.Properties.Categories, options.Categories) {
+			features = append(features, feature)
+		}
+	}
+
+	switch options.Sort {
+	case SightsSortDistance:
+		sort.SliceStable(features, func(i, j int) bool {
+			return features[i].Properties.Distance < features[j].Properties.Distance
+		})
+	case SightsSortRating:
+		// Highest rated first, closest first within a rating
+		sort.SliceStable(features, func(i, j int) bool {
+			a, b := features[i].Properties, features[j].Properties
+			if a.Rate != b.Rate {
+				return a.Rate > b.Rate
+			}
+			return a.Distance < b.Distance
+		})
+	}
+
+	total := len(features)
+	start := min(options.Offset, total)
+	end := min(start+options.PageSize, total)
+
+	page := &models.SightsPage{
+		Type:       collection.Type,
+		Features:   features[start:end],
+		Facets:     facets,
+		Page:       start/options.PageSize + 1,
+		PageSize:   options.PageSize,
+		Total:      total,
+		TotalPages: (total + options.PageSize - 1) / options.PageSize,
+	}
+	if end < total {
+		page.NextCursor = base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("offset:%d", end)))
+	}
+
+	return page, nil
+}
@@ -0,0 +1,264 @@
+package services
+
+/* Trip Members
+
+- The owner (trips.user_id) invites other users by username or email as editors or viewers
+- Invitations stay pending until the invited user accepts (Member) or declines (Deleted)
+- Owners change roles and remove members, members can leave a trip themselves
+*/
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/MCantyDev/city-explorer-server/internal/database"
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+)
+
+const MaxTripMembers = 20 // Members and pending invitations, the owner not included
+
+var (
+	ErrTripMemberNotFound     = errors.New("trip member not found")
+	ErrTripMemberExists       = errors.New("user is already a member of this trip or has been invited")
+	ErrTripUserNotFound       = errors.New("no user with that username or email")
+	ErrTripInvitationNotFound = errors.New("trip invitation not found")
+	ErrInvalidTripMember      = errors.New("invalid trip member")
+)
+
+// ListTripMembers - The owner followed by the members and pending invitations (Any member can see them)
+func ListTripMembers(userId uint, tripId uint) ([]models.TripMemberView, error) {
+	trip, err := loadTrip(userId, tripId, models.TripRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	var owner models.User
+	query := database.NewQueryBuilder("SELECT").Table("users").Where("id = ?").Build()
+	if _, err := database.Execute(&owner, query, trip.UserId); err != nil {
+		return nil, err
+	}
+
+	members, err := tripMembers(trip.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]models.TripMemberView, 0, len(members)+1)
+	views = append(views, models.TripMemberView{
+		UserId:    owner.Id,
+		Username:  owner.Username,
+		FirstName: owner.FirstName,
+		LastName:  owner.LastName,
+		Role:      models.TripRoleOwner,
+		Status:    models.TripMemberAccepted,
+		CreatedAt: trip.CreatedAt,
+	})
+	for _, member := range members {
+		views = append(views, tripMemberView(member))
+	}
+	return views, nil
+}
+
+// InviteTripMember - Invites a user (by username or email) to the trip (Owners only)
+func InviteTripMember(userId uint, tripId uint, identifier string, role string) (*models.TripMemberView, error) {
+	trip, err := loadTrip(userId, tripId, models.TripRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	identifier = strings.TrimSpace(identifier)
+	var user models.User
+	query := database.NewQueryBuilder("SELECT").Table("users").Where("username = ? OR email = ?").Build()
+	if _, err := database.Execute(&user, query, identifier, identifier); err != nil {
+		return nil, err
+	}
+	if user.Id == 0 {
+		return nil, ErrTripUserNotFound
+	}
+	if user.Id == trip.UserId {
+		return nil, fmt.Errorf("%w: the owner is already on the trip", ErrInvalidTripMember)
+	}
+
+	members, err := tripMembers(trip.Id)
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range members {
+		if member.UserId == user.Id {
+			return nil, ErrTripMemberExists
+		}
+	}
+	if len(members) >= MaxTripMembers {
+		return nil, fmt.Errorf("%w: trips can have at most %d members", ErrInvalidTripMember, MaxTripMembers)
+	}
+
+	member := models.TripMember{
+		TripId:    trip.Id,
+		UserId:    user.Id,
+		Role:      role,
+		Status:    models.TripMemberPending,
+		InvitedBy: &userId,
+	}
+	if _, err := database.Execute(&member, "INSERT"); err != nil {
+		// Another request invited the same user since the check above
+		if database.IsDuplicateKey(err) {
+			return nil, ErrTripMemberExists
+		}
+		return nil, err
+	}
+
+	member.Username, member.FirstName, member.LastName = user.Username, user.FirstName, user.LastName
+	view := tripMemberView(member)
+	return &view, nil
+}
+
+// UpdateTripMember - Changes the role of a member or pending invitation (Owners only)
+func UpdateTripMember(userId uint, tripId uint, memberId uint, role string) (*models.TripMemberView, error) {
+	trip, err := loadTrip(userId, tripId, models.TripRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+	member, err := loadTripMember(trip.Id, memberId)
+	if err != nil {
+		return nil, err
+	}
+
+	query := database.NewQueryBuilder("UPDATE").Table("trip_members").Columns("role").Where("id = ?").Build()
+	if _, err := database.Execute(nil, query, role, member.Id); err != nil {
+		return nil, err
+	}
+
+	member.Role = role
+	view := tripMemberView(*member)
+	return &view, nil
+}
+
+// RemoveTripMember - Owners remove members or cancel invitations, members can remove themselves (Leave the trip)
+func RemoveTripMember(userId uint, tripId uint, memberId uint) error {
+	role := models.TripRoleOwner
+	if memberId == userId {
+		role = models.TripRoleViewer
+	}
+	trip, err := loadTrip(userId, tripId, role)
+	if err != nil {
+		return err
+	}
+	if memberId == trip.UserId {
+		return fmt.Errorf("%w: owners cannot leave their own trip, delete it instead", ErrInvalidTripMember)
+	}
+	member, err := loadTripMember(trip.Id, memberId)
+	if err != nil {
+		return err
+	}
+
+	query := database.NewQueryBuilder("DELETE").Table("trip_members").Where("id = ?").Build()
+	_, err = database.Execute(nil, query, member.Id)
+	return err
+}
+
+// ListTripInvitations - The user's pending invitations, newest first
+func ListTripInvitations(userId uint) ([]models.TripInvitationView, error) {
+	var rows []models.TripMember
+	query := database.NewQueryBuilder("SELECT").Table("trip_members").
+		Columns("trip_members.*", "COALESCE(users.username, '') AS username").
+		Join("LEFT JOIN users ON users.id = trip_members.invited_by").
+		Where("trip_members.user_id = ?").Where("trip_members.status = ?").
+		OrderBy("trip_members.created_at DESC, trip_members.id DESC").Build()
+	if _, err := database.Execute(&rows, query, userId, models.TripMemberPending); err != nil {
+		return nil, err
+	}
+
+	views := make([]models.TripInvitationView, 0, len(rows))
+	if len(rows) == 0 {
+		return views, nil
+	}
+
+	ids := make([]uint, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.TripId)
+	}
+	var trips []models.Trip
+	query = database.NewQueryBuilder("SELECT").Table("trips").Where("id IN ?").Build()
+	if _, err := database.Execute(&trips, query, ids); err != nil {
+		return nil, err
+	}
+	byId := map[uint]models.Trip{}
+	for _, trip := range trips {
+		byId[trip.Id] = trip
+	}
+
+	for _, row := range rows {
+		trip := byId[row.TripId]
+		views = append(views, models.TripInvitationView{
+			TripId:    row.TripId,
+			TripName:  trip.Name,
+			StartDate: trip.StartDate.Format("2006-01-02"),
+			EndDate:   trip.EndDate.Format("2006-01-02"),
+			Role:      row.Role,
+			InvitedBy: row.Username,
+			CreatedAt: row.CreatedAt,
+		})
+	}
+	return views, nil
+}
+
+// RespondTripInvitation - Accepts (The user becomes a member) or declines (The invitation is deleted) a pending invitation
+func RespondTripInvitation(userId uint, tripId uint, accept bool) error {
+	var member models.TripMember
+	query := database.NewQueryBuilder("SELECT").Table("trip_members").Where("trip_id = ?").Where("user_id = ?").Where("status = ?").Build()
+	if _, err := database.Execute(&member, query, tripId, userId, models.TripMemberPending); err != nil {
+		return err
+	}
+	if member.Id == 0 {
+		return ErrTripInvitationNotFound
+	}
+
+	if !accept {
+		query = database.NewQueryBuilder("DELETE").Table("trip_members").Where("id = ?").Build()
+		_, err := database.Execute(nil, query, member.Id)
+		return err
+	}
+	query = database.NewQueryBuilder("UPDATE").Table("trip_members").Columns("status").Where("id = ?").Build()
+	_, err := database.Execute(nil, query, models.TripMemberAccepted, member.Id)
+	return err
+}
+
+// tripMembers - Members and pending invitations of a trip with their user details (Accepted first)
+func tripMembers(tripId uint) ([]models.TripMember, error) {
+	var members []models.TripMember
+	query := tripMemberQuery().Where("trip_members.trip_id = ?").OrderBy("trip_members.status DESC, trip_members.created_at, trip_members.id").Build()
+	_, err := database.Execute(&members, query, tripId)
+	return members, err
+}
+
+// loadTripMember - A member or pending invitation of the trip, by user id
+func loadTripMember(tripId uint, userId uint) (*models.TripMember, error) {
+	var member models.TripMember
+	query := tripMemberQuery().Where("trip_members.trip_id = ?").Where("trip_members.user_id = ?").Build()
+	if _, err := database.Execute(&member, query, tripId, userId); err != nil {
+		return nil, err
+	}
+	if member.Id == 0 {
+		return nil, ErrTripMemberNotFound
+	}
+	return &member, nil
+}
+
+func tripMemberQuery() *database.QueryBuilder {
+	return database.NewQueryBuilder("SELECT").Table("trip_members").
+		Columns("trip_members.*", "users.username", "users.first_name", "users.last_name").
+		Join("JOIN users ON users.id = trip_members.user_id")
+}
+
+func tripMemberView(member models.TripMember) models.TripMemberView {
+	return models.TripMemberView{
+		UserId:    member.UserId,
+		Username:  member.Username,
+		FirstName: member.FirstName,
+		LastName:  member.LastName,
+		Role:      member.Role,
+		Status:    member.Status,
+		CreatedAt: member.CreatedAt,
+	}
+}
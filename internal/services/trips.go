@@ -0,0 +1,761 @@
+package services
+
+/* Trips
+
+- Trips cover a date range and a list of cities, items are POIs (xid) or custom places on a day of the trip
+- Items are ordered by position within each day and can be moved between days
+- Each day of the plan carries the daily forecast from the weather cache (When the date is inside the cached forecast)
+- Members are owners, editors or viewers (See trip_members.go), other users' trips are not found
+- Every change bumps the trip version first, so a change based on an old version (If-Match) or racing another change fails instead of overwriting it
+*/
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/MCantyDev/city-explorer-server/internal/database"
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+	"gorm.io/gorm"
+)
+
+const (
+	MaxTripDays  = 60
+	MaxTripItems = 500
+	MaxTripName  = 255
+	MaxTripNotes = 5000
+)
+
+var (
+	ErrTripNotFound        = errors.New("trip not found")
+	ErrTripItemNotFound    = errors.New("trip item not found")
+	ErrInvalidTrip         = errors.New("invalid trip")
+	ErrTripForbidden       = errors.New("your role on this trip does not allow this")
+	ErrTripConflict        = errors.New("the trip was changed by someone else, reload it and try again")
+	ErrTripVersionRequired = errors.New("an 'If-Match' header with the trip version is required")
+)
+
+// Ranks of the trip roles (Higher roles can do everything lower roles can)
+var tripRoles = map[string]int{models.TripRoleViewer: 1, models.TripRoleEditor: 2, models.TripRoleOwner: 3}
+
+var timeSlots = []string{models.TimeSlotAny, models.TimeSlotMorning, models.TimeSlotAfternoon, models.TimeSlotEvening, models.TimeSlotNight}
+
+// ParseTripDates - Parses YYYY-MM-DD start and end dates (End on or after start, at most MaxTripDays)
+func ParseTripDates(start string, end string) (time.Time, time.Time, error) {
+	startDate, err := time.ParseInLocation("2006-01-02", start, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: 'start_date' must be in the format YYYY-MM-DD", ErrInvalidTrip)
+	}
+	endDate, err := time.ParseInLocation("2006-01-02", end, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: 'end_date' must be in the format YYYY-MM-DD", ErrInvalidTrip)
+	}
+
+	days := daysBetween(startDate, endDate) + 1
+	if days < 1 {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: 'end_date' must not be before 'start_date'", ErrInvalidTrip)
+	}
+	if days > MaxTripDays {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: trips can be at most %d days", ErrInvalidTrip, MaxTripDays)
+	}
+	return startDate, endDate, nil
+}
+
+// ParseTimeSlot - Validates a time slot (Defaults to 'any')
+func ParseTimeSlot(slot string) (string, error) {
+	if slot == "" {
+		return models.TimeSlotAny, nil
+	}
+	for _, valid := range timeSlots {
+		if slot == valid {
+			return slot, nil
+		}
+	}
+	return "", fmt.Errorf("%w: 'time_slot' must be one of: %s", ErrInvalidTrip, strings.Join(timeSlots, ", "))
+}
+
+// ValidateTripText - Checks the name and notes lengths
+func ValidateTripText(name string, notes string) error {
+	if name == "" || utf8.RuneCountInString(name) > MaxTripName {
+		return fmt.Errorf("%w: name must be between 1 and %d characters", ErrInvalidTrip, MaxTripName)
+	}
+	if utf8.RuneCountInString(notes) > MaxTripNotes {
+		return fmt.Errorf("%w: notes must be at most %d characters", ErrInvalidTrip, MaxTripNotes)
+	}
+	return nil
+}
+
+// TripLength - Number of days in a trip (Start and end dates included)
+func TripLength(trip *models.Trip) int {
+	return daysBetween(trip.StartDate, trip.EndDate) + 1
+}
+
+// ListTrips - The trips the user owns or is a member of, latest start date first
+func ListTrips(userId uint) ([]models.TripSummary, error) {
+	var trips []models.Trip
+	query := tripQuery().OrderBy("trips.start_date DESC, trips.id DESC").Build()
+	_, err := database.Execute(&trips, query, userId, userId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]models.TripSummary, 0, len(trips))
+	if len(trips) == 0 {
+		return summaries, nil
+	}
+
+	ids := make([]uint, 0, len(trips))
+	for _, trip := range trips {
+		ids = append(ids, trip.Id)
+	}
+	cities, err := tripCities(ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range trips {
+		summaries = append(summaries, tripSummary(&trips[i], cities[trips[i].Id]))
+	}
+	return summaries, nil
+}
+
+// GetTrip - A trip with its cities and the day by day plan
+func GetTrip(userId uint, id uint) (*models.TripView, error) {
+	trip, err := loadTrip(userId, id, models.TripRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	return tripView(trip)
+}
+
+// tripView - The trip with its cities, items and forecasts (No access check)
+func tripView(trip *models.Trip) (*models.TripView, error) {
+	cities, err := tripCities(trip.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []models.TripItem
+	query := database.NewQueryBuilder("SELECT").Table("trip_items").Where("trip_id = ?").OrderBy("day, position, id").Build()
+	_, err = database.Execute(&items, query, trip.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	forecasts, err := tripForecasts(cities[trip.Id])
+	if err != nil {
+		return nil, err
+	}
+
+	view := &models.TripView{
+		TripSummary: tripSummary(trip, cities[trip.Id]),
+		Plan:        tripPlan(trip, cities[trip.Id], items, forecasts),
+	}
+	if trip.Notes != nil {
+		view.Notes = *trip.Notes
+	}
+	return view, nil
+}
+
+// CreateTrip - Saves a new trip (cityIds in visiting order)
+func CreateTrip(userId uint, name string, start time.Time, end time.Time, notes string, cityIds []uint) (*models.TripView, error) {
+	trip := models.Trip{
+		UserId:    userId,
+		Name:      name,
+		StartDate: start,
+		EndDate:   end,
+		Notes:     nullableText(notes),
+		Version:   1,
+	}
+	// The trip is only saved with its cities
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := database.ExecuteIn(tx, &trip, "INSERT"); err != nil {
+			return err
+		}
+		return setTripCities(tx, trip.Id, cityIds)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return GetTrip(userId, trip.Id)
+}
+
+// UpdateTrip - Changes the trip details (nil values are kept, cityIds replaces the city list)
+func UpdateTrip(userId uint, id uint, version *int, name *string, start *string, end *string, notes *string, cityIds *[]uint) (*models.TripView, error) {
+	trip, err := loadTrip(userId, id, models.TripRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	// Missing fields are merged with the loaded trip, the version claim fails if it changed since
+	if name != nil {
+		trip.Name = strings.TrimSpace(*name)
+	}
+	if notes != nil {
+		trip.Notes = nullableText(strings.TrimSpace(*notes))
+	}
+	if err := ValidateTripText(trip.Name, deref(trip.Notes)); err != nil {
+		return nil, err
+	}
+
+	datesChanged := start != nil || end != nil
+	if datesChanged {
+		startDate, endDate := trip.StartDate.Format("2006-01-02"), trip.EndDate.Format("2006-01-02")
+		if start != nil {
+			startDate = *start
+		}
+		if end != nil {
+			endDate = *end
+		}
+		trip.StartDate, trip.EndDate, err = ParseTripDates(startDate, endDate)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The version is claimed first, so items added at the same time wait for the day check
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := claimTripVersionIn(tx, trip, version); err != nil {
+			return err
+		}
+
+		// Items must still fit inside the trip
+		if datesChanged {
+			var lastDay struct{ Day int }
+			query := database.NewQueryBuilder("SELECT").Table("trip_items").Columns("COALESCE(MAX(day), 0) AS day").Where("trip_id = ?").Build()
+			if _, err := database.ExecuteIn(tx, &lastDay, query, trip.Id); err != nil {
+				return err
+			}
+			if lastDay.Day > TripLength(trip) {
+				return fmt.Errorf("%w: items are planned on day %d, move them before shortening the trip", ErrInvalidTrip, lastDay.Day)
+			}
+		}
+
+		query := database.NewQueryBuilder("UPDATE").Table("trips").Columns("name", "start_date", "end_date", "notes").Where("id = ?").Build()
+		_, err := database.ExecuteIn(tx, nil, query, trip.Name, trip.StartDate.Format("2006-01-02"), trip.EndDate.Format("2006-01-02"), trip.Notes, trip.Id)
+		if err != nil {
+			return err
+		}
+
+		if cityIds != nil {
+			return setTripCities(tx, trip.Id, *cityIds)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return GetTrip(userId, trip.Id)
+}
+
+// DeleteTrip - Removes a trip and its items (Owners only)
+func DeleteTrip(userId uint, id uint, version *int) error {
+	trip, err := loadTrip(userId, id, models.TripRoleOwner)
+	if err != nil {
+		return err
+	}
+	if err := claimTripVersion(trip, version); err != nil {
+		return err
+	}
+
+	query := database.NewQueryBuilder("DELETE").Table("trips").Where("id = ?").Build()
+	_, err = database.Execute(nil, query, trip.Id)
+	return err
+}
+
+// AddTripItem - Adds an item to the end of its day (POI names, coordinates and cities are filled in from the POI cache)
+func AddTripItem(userId uint, tripId uint, version *int, item models.TripItem) (*models.TripView, error) {
+	trip, err := loadTrip(userId, tripId, models.TripRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := prepareTripItem(trip, &item); err != nil {
+		return nil, err
+	}
+	if err := claimTripVersion(trip, version); err != nil {
+		return nil, err
+	}
+	if err := insertTripItem(trip, &item); err != nil {
+		return nil, err
+	}
+	return GetTrip(userId, trip.Id)
+}
+
+// prepareTripItem - Fills in a new item from the POI cache and validates it (Including the item limit)
+func prepareTripItem(trip *models.Trip, item *models.TripItem) error {
+	var count struct{ Total int }
+	query := database.NewQueryBuilder("SELECT").Table("trip_items").Columns("COUNT(*) AS total").Where("trip_id = ?").Build()
+	if _, err := database.Execute(&count, query, trip.Id); err != nil {
+		return err
+	}
+	if count.Total >= MaxTripItems {
+		return fmt.Errorf("%w: trips can have at most %d items", ErrInvalidTrip, MaxTripItems)
+	}
+
+	item.TripId = trip.Id
+	if item.Kind == models.TripItemPoi {
+		if poi := GetCachedPoi(*item.Xid); poi != nil {
+			fillFromPoi(item, poi)
+		}
+	}
+	return validateTripItem(trip, item)
+}
+
+// insertTripItem - Saves a prepared item at the end of its day (Sets the item id)
+func insertTripItem(trip *models.Trip, item *models.TripItem) error {
+	var last struct{ Position int }
+	query := database.NewQueryBuilder("SELECT").Table("trip_items").Columns("COALESCE(MAX(position), 0) AS position").Where("trip_id = ?").Where("day = ?").Build()
+	if _, err := database.Execute(&last, query, trip.Id, item.Day); err != nil {
+		return err
+	}
+
+	item.Id = 0
+	item.Position = last.Position + 1
+	_, err := database.Execute(item, "INSERT")
+	return err
+}
+
+// UpdateTripItem - Changes an item (Moving it to another day puts it at the end of that day)
+func UpdateTripItem(userId uint, tripId uint, version *int, itemId uint, update models.TripItemUpdate) (*models.TripView, error) {
+	trip, err := loadTrip(userId, tripId, models.TripRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	item, err := loadTripItem(trip.Id, itemId)
+	if err != nil {
+		return nil, err
+	}
+
+	day := item.Day
+	if update.Day != nil {
+		item.Day = *update.Day
+	}
+	if update.TimeSlot != nil {
+		item.TimeSlot = *update.TimeSlot
+	}
+	if update.CityId != nil {
+		item.CityId = update.CityId
+	}
+	if update.Name != nil {
+		item.Name = strings.TrimSpace(*update.Name)
+	}
+	if update.Lat != nil || update.Lon != nil {
+		item.Lat, item.Lon = update.Lat, update.Lon
+	}
+	if update.Notes != nil {
+		item.Notes = nullableText(strings.TrimSpace(*update.Notes))
+	}
+	if err := validateTripItem(trip, item); err != nil {
+		return nil, err
+	}
+	if err := claimTripVersion(trip, version); err != nil {
+		return nil, err
+	}
+
+	if item.Day != day {
+		var last struct{ Position int }
+		query := database.NewQueryBuilder("SELECT").Table("trip_items").Columns("COALESCE(MAX(position), 0) AS position").Where("trip_id = ?").Where("day = ?").Build()
+		if _, err := database.Execute(&last, query, trip.Id, item.Day); err != nil {
+			return nil, err
+		}
+		item.Position = last.Position + 1
+	}
+
+	query := database.NewQueryBuilder("UPDATE").Table("trip_items").Columns("day", "time_slot", "position", "city_id", "name", "lat", "lon", "notes").Where("id = ?").Build()
+	_, err = database.Execute(nil, query, item.Day, item.TimeSlot, item.Position, item.CityId, item.Name, item.Lat, item.Lon, item.Notes, item.Id)
+	if err != nil {
+		return nil, err
+	}
+	return GetTrip(userId, trip.Id)
+}
+
+// DeleteTripItem - Removes an item from a trip
+func DeleteTripItem(userId uint, tripId uint, version *int, itemId uint) (*models.TripView, error) {
+	trip, err := loadTrip(userId, tripId, models.TripRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := loadTripItem(trip.Id, itemId); err != nil {
+		return nil, err
+	}
+	if err := claimTripVersion(trip, version); err != nil {
+		return nil, err
+	}
+
+	query := database.NewQueryBuilder("DELETE").Table("trip_items").Where("id = ?").Build()
+	if _, err := database.Execute(nil, query, itemId); err != nil {
+		return nil, err
+	}
+	return GetTrip(userId, trip.Id)
+}
+
+// ReorderTripItems - Sets the day and order of every item (The list must contain each item of the trip exactly once)
+func ReorderTripItems(userId uint, tripId uint, version *int, order []models.TripOrderItem) (*models.TripView, error) {
+	// The order replaces the whole plan, so it must be based on the latest version
+	if version == nil {
+		return nil, ErrTripVersionRequired
+	}
+	trip, err := loadTrip(userId, tripId, models.TripRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []models.TripItem
+	query := database.NewQueryBuilder("SELECT").Table("trip_items").Columns("id").Where("trip_id = ?").Build()
+	if _, err := database.Execute(&items, query, trip.Id); err != nil {
+		return nil, err
+	}
+
+	remaining := map[uint]bool{}
+	for _, item := range items {
+		remaining[item.Id] = true
+	}
+	if len(order) != len(remaining) {
+		return nil, fmt.Errorf("%w: the order must list all %d items of the trip", ErrInvalidTrip, len(remaining))
+	}
+
+	days := TripLength(trip)
+	for _, item := range order {
+		if !remaining[item.Id] {
+			return nil, fmt.Errorf("%w: item %d is not part of the trip or is listed twice", ErrInvalidTrip, item.Id)
+		}
+		if item.Day > days {
+			return nil, fmt.Errorf("%w: 'day' must be between 1 and %d", ErrInvalidTrip, days)
+		}
+		delete(remaining, item.Id)
+	}
+	// The whole order is applied or none of it (The version included)
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := claimTripVersionIn(tx, trip, version); err != nil {
+			return err
+		}
+
+		// Positions restart at 1 on every day
+		positions := map[int]int{}
+		query := database.NewQueryBuilder("UPDATE").Table("trip_items").Columns("day", "position").Where("id = ?").Build()
+		for _, item := range order {
+			positions[item.Day]++
+			if _, err := database.ExecuteIn(tx, nil, query, item.Day, positions[item.Day], item.Id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return GetTrip(userId, trip.Id)
+}
+
+// loadTrip - A trip the user owns or is a member of, with at least the given role
+func loadTrip(userId uint, id uint, role string) (*models.Trip, error) {
+	var trip models.Trip
+	query := tripQuery().Where("trips.id = ?").Build()
+	_, err := database.Execute(&trip, query, userId, userId, userId, id)
+	if err != nil {
+		return nil, err
+	}
+	if trip.Id == 0 {
+		return nil, ErrTripNotFound
+	}
+	if tripRoles[trip.Role] < tripRoles[role] {
+		return nil, ErrTripForbidden
+	}
+	return &trip, nil
+}
+
+// tripQuery - Trips the user can access, with their role (Takes the user id three times)
+func tripQuery() *database.QueryBuilder {
+	return database.NewQueryBuilder("SELECT").Table("trips").
+		Columns("trips.*", "CASE WHEN trips.user_id = ? THEN 'owner' ELSE trip_members.role END AS role").
+		Join("LEFT JOIN trip_members ON trip_members.trip_id = trips.id AND trip_members.user_id = ? AND trip_members.status = 'accepted'").
+		Where("(trips.user_id = ? OR trip_members.id IS NOT NULL)")
+}
+
+// claimTripVersion - Bumps the version before a change (Fails when the client's version is old or another change got there first)
+func claimTripVersion(trip *models.Trip, version *int) error {
+	return claimTripVersionIn(database.DB, trip, version)
+}
+
+// claimTripVersionIn - claimTripVersion on a specific connection (Rolled back with the transaction)
+func claimTripVersionIn(db *gorm.DB, trip *models.Trip, version *int) error {
+	if version != nil && *version != trip.Version {
+		return fmt.Errorf("%w (current version %d)", ErrTripConflict, trip.Version)
+	}
+
+	query := database.NewQueryBuilder("UPDATE").Table("trips").Columns("version").Where("id = ?").Where("version = ?").Build()
+	rows, err := database.ExecuteIn(db, nil, query, trip.Version+1, trip.Id, trip.Version)
+	if err != nil {
+		return err
+	}
+	if affected, ok := rows.(int64); ok && affected == 0 {
+		return ErrTripConflict
+	}
+	trip.Version++
+	return nil
+}
+
+func loadTripItem(tripId uint, id uint) (*models.TripItem, error) {
+	var item models.TripItem
+	query := database.NewQueryBuilder("SELECT").Table("trip_items").Where("id = ?").Where("trip_id = ?").Build()
+	_, err := database.Execute(&item, query, id, tripId)
+	if err != nil {
+		return nil, err
+	}
+	if item.Id == 0 {
+		return nil, ErrTripItemNotFound
+	}
+	return &item, nil
+}
+
+// validateTripItem - Checks the day, time slot, city and text of an item
+func validateTripItem(trip *models.Trip, item *models.TripItem) error {
+	if days := TripLength(trip); item.Day < 1 || item.Day > days {
+		return fmt.Errorf("%w: 'day' must be between 1 and %d", ErrInvalidTrip, days)
+	}
+
+	slot, err := ParseTimeSlot(item.TimeSlot)
+	if err != nil {
+		return err
+	}
+	item.TimeSlot = slot
+
+	if item.Name == "" || utf8.RuneCountInString(item.Name) > MaxTripName {
+		return fmt.Errorf("%w: items need a name of at most %d characters (POIs are named from the cache once viewed)", ErrInvalidTrip, MaxTripName)
+	}
+	if item.Notes != nil && utf8.RuneCountInString(*item.Notes) > MaxTripNotes {
+		return fmt.Errorf("%w: notes must be at most %d characters", ErrInvalidTrip, MaxTripNotes)
+	}
+	if (item.Lat == nil) != (item.Lon == nil) {
+		return fmt.Errorf("%w: 'lat' and 'lon' must be set together", ErrInvalidTrip)
+	}
+	if item.Lat != nil && (*item.Lat < -90 || *item.Lat > 90 || *item.Lon < -180 || *item.Lon > 180) {
+		return fmt.Errorf("%w: 'lat' or 'lon' is out of range", ErrInvalidTrip)
+	}
+
+	if item.CityId != nil {
+		cities, err := tripCities(trip.Id)
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, city := range cities[trip.Id] {
+			found = found || city.CityId == *item.CityId
+		}
+		if !found {
+			return fmt.Errorf("%w: 'city_id' must be one of the trip's cities", ErrInvalidTrip)
+		}
+	}
+	return nil
+}
+
+// fillFromPoi - Name, coordinates and city from the cached POI (Values given by the client are kept)
+func fillFromPoi(item *models.TripItem, poi *models.CityPoi) {
+	var place models.OpenTripPlaceRequest
+	if err := json.Unmarshal(poi.Data, &place); err != nil {
+		return
+	}
+
+	if item.Name == "" {
+		item.Name = place.Name
+	}
+	if item.Lat == nil && item.Lon == nil && (place.Point.Lat != 0 || place.Point.Lon != 0) {
+		item.Lat, item.Lon = &place.Point.Lat, &place.Point.Lon
+	}
+	if item.CityId == nil {
+		cities, err := tripCities(item.TripId)
+		if err == nil {
+			for _, city := range cities[item.TripId] {
+				if city.CityId == poi.CityId {
+					item.CityId = &poi.CityId
+				}
+			}
+		}
+	}
+}
+
+// setTripCities - Replaces the trip's cities (In visiting order, duplicates ignored)
+func setTripCities(db *gorm.DB, tripId uint, cityIds []uint) error {
+	query := database.NewQueryBuilder("DELETE").Table("trip_cities").Where("trip_id = ?").Build()
+	if _, err := database.ExecuteIn(db, nil, query, tripId); err != nil {
+		return err
+	}
+
+	seen := map[uint]bool{}
+	query = database.NewQueryBuilder("INSERT").Table("trip_cities").Columns("trip_id", "city_id", "position").Values(3).Build()
+	for _, cityId := range cityIds {
+		if seen[cityId] {
+			continue
+		}
+		seen[cityId] = true
+		if _, err := database.ExecuteIn(db, nil, query, tripId, cityId, len(seen)); err != nil {
+			return err
+		}
+	}
+
+	// Items whose city was removed keep their place but lose the city
+	query = database.NewQueryBuilder("UPDATE").Table("trip_items").Columns("city_id").Where("trip_id = ?").Where("city_id NOT IN (SELECT city_id FROM trip_cities WHERE trip_id = ?)").Build()
+	_, err := database.ExecuteIn(db, nil, query, nil, tripId, tripId)
+	return err
+}
+
+// tripCities - Cities of each trip in visiting order (Keyed by trip id)
+func tripCities(tripIds ...uint) (map[uint][]models.TripCity, error) {
+	var cities []models.TripCity
+	query := database.NewQueryBuilder("SELECT").Table("trip_cities").
+		Columns("trip_cities.*", "cities.name", "cities.country_code", "cities.lat", "cities.lon", "cities.timezone").
+		Join("JOIN cities ON cities.id = trip_cities.city_id").
+		Where("trip_cities.trip_id IN ?").
+		OrderBy("trip_cities.trip_id, trip_cities.position").Build()
+	_, err := database.Execute(&cities, query, tripIds)
+	if err != nil {
+		return nil, err
+	}
+
+	byTrip := map[uint][]models.TripCity{}
+	for _, city := range cities {
+		byTrip[city.TripId] = append(byTrip[city.TripId], city)
+	}
+	return byTrip, nil
+}
+
+// tripForecasts - Latest cached weather for each city (Cities without cached weather are left out)
+func tripForecasts(cities []models.TripCity) (map[uint]*models.Weather, error) {
+	ids := make([]uint, 0, len(cities))
+	for _, city := range cities {
+		ids = append(ids, city.CityId)
+	}
+	return cachedForecasts(ids...)
+}
+
+// cachedForecasts - Latest cached weather for each city id (Cities without cached weather are left out)
+func cachedForecasts(cityIds ...uint) (map[uint]*models.Weather, error) {
+	forecasts := map[uint]*models.Weather{}
+	if len(cityIds) == 0 {
+		return forecasts, nil
+	}
+
+	var cached []models.CityWeather
+	query := database.NewQueryBuilder("SELECT").Table("city_weather").Where("city_id IN ?").OrderBy("updated_at DESC").Build()
+	_, err := database.Execute(&cached, query, cityIds)
+	if err != nil {
+		return nil, err
+	}
+
+	options := models.WeatherOptions{SchemaVersion: models.WeatherSchemaVersion, Units: models.UnitsMetric, Lang: "en"}
+	for _, row := range cached {
+		if _, ok := forecasts[row.CityId]; ok {
+			continue
+		}
+		if weather, err := NormaliseWeather(row.Data, options); err == nil {
+			forecasts[row.CityId] = weather
+		}
+	}
+	return forecasts, nil
+}
+
+// tripPlan - One entry per day with its items and forecast (The day's city is the first item's city, otherwise the previous day's)
+func tripPlan(trip *models.Trip, cities []models.TripCity, items []models.TripItem, forecasts map[uint]*models.Weather) []models.TripDay {
+	days := TripLength(trip)
+	plan := make([]models.TripDay, days)
+
+	var cityId *uint
+	if len(cities) > 0 {
+		cityId = &cities[0].CityId
+	}
+
+	for i := range plan {
+		date := trip.StartDate.AddDate(0, 0, i).Format("2006-01-02")
+		plan[i] = models.TripDay{Day: i + 1, Date: date, Items: []models.TripItemView{}}
+	}
+
+	for _, item := range items {
+		if item.Day < 1 || item.Day > days {
+			continue
+		}
+		plan[item.Day-1].Items = append(plan[item.Day-1].Items, tripItemView(item))
+	}
+
+	for i := range plan {
+		for _, item := range plan[i].Items {
+			if item.CityId != nil {
+				cityId = item.CityId
+				break
+			}
+		}
+		plan[i].CityId = cityId
+
+		if cityId != nil {
+			if weather, ok := forecasts[*cityId]; ok {
+				plan[i].Forecast = dailyForecast(weather, plan[i].Date)
+			}
+		}
+	}
+	return plan
+}
+
+// dailyForecast - The forecast for a local date (nil if the date is outside the forecast)
+func dailyForecast(weather *models.Weather, date string) *models.WeatherDaily {
+	for i, day := range weather.Daily {
+		local := time.Unix(day.Date+int64(weather.TimezoneOffset), 0).UTC().Format("2006-01-02")
+		if local == date {
+			return &weather.Daily[i]
+		}
+	}
+	return nil
+}
+
+func tripSummary(trip *models.Trip, cities []models.TripCity) models.TripSummary {
+	summary := models.TripSummary{
+		Id:        trip.Id,
+		Name:      trip.Name,
+		StartDate: trip.StartDate.Format("2006-01-02"),
+		EndDate:   trip.EndDate.Format("2006-01-02"),
+		Days:      TripLength(trip),
+		Cities:    []models.TripCityView{},
+		Role:      trip.Role,
+		Version:   trip.Version,
+		CreatedAt: trip.CreatedAt,
+		UpdatedAt: trip.UpdatedAt,
+	}
+	for _, city := range cities {
+		summary.Cities = append(summary.Cities, models.TripCityView{
+			Id:          city.CityId,
+			Name:        city.Name,
+			CountryCode: city.CountryCode,
+			Lat:         city.Lat,
+			Lon:         city.Lon,
+		})
+	}
+	return summary
+}
+
+func tripItemView(item models.TripItem) models.TripItemView {
+	view := models.TripItemView{
+		Id:       item.Id,
+		Day:      item.Day,
+		TimeSlot: item.TimeSlot,
+		Position: item.Position,
+		Kind:     item.Kind,
+		Xid:      item.Xid,
+		CityId:   item.CityId,
+		Name:     item.Name,
+		Lat:      item.Lat,
+		Lon:      item.Lon,
+	}
+	if item.Notes != nil {
+		view.Notes = *item.Notes
+	}
+	return view
+}
+
+// daysBetween - Whole calendar days from one date to another
+func daysBetween(from time.Time, to time.Time) int {
+	fromDate := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	toDate := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(toDate.Sub(fromDate).Hours() / 24)
+}
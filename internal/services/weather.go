@@ -0,0 +1,262 @@
+package services
+
+/* Weather Normalisation
+
+- Maps the cached OpenWeather One Call shaped data into models.Weather
+- Cached data is always metric, imperial conversion happens on the way out
+*/
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+)
+
+// NormaliseWeather - Parses provider data (OpenWeather One Call shape) into the normalised Weather model
+func NormaliseWeather(data []byte, options models.WeatherOptions) (*models.Weather, error) {
+	raw, err := parseWeather(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var current models.OpenWeatherCurrent
+	if err := unmarshalSection(raw.Current, &current, "current"); err != nil {
+		return nil, err
+	}
+
+	var daily []models.OpenWeatherDaily
+	if err := unmarshalSection(raw.Daily, &daily, "daily"); err != nil {
+		return nil, err
+	}
+
+	imperial := options.Units == models.UnitsImperial
+
+	weather := &models.Weather{
+		WeatherMeta: weatherMeta(raw, options),
+		Current: models.WeatherCurrent{
+			Time:        current.Dt,
+			Sunrise:     current.Sunrise,
+			Sunset:      current.Sunset,
+			Temperature: convertTemperature(current.Temp, imperial),
+			FeelsLike:   convertTemperature(current.FeelsLike, imperial),
+			Humidity:    current.Humidity,
+			Pressure:    current.Pressure,
+			CloudCover:  current.Clouds,
+			UvIndex:     current.Uvi,
+			Wind: models.WeatherWind{
+				Speed:     convertSpeed(current.WindSpeed, imperial),
+				Gust:      convertSpeed(current.WindGust, imperial),
+				Direction: current.WindDeg,
+			},
+			Condition: firstCondition(current.Weather, options.Lang),
+		},
+		Daily: make([]models.WeatherDaily, 0, len(daily)),
+	}
+
+	for _, day := range daily {
+		weather.Daily = append(weather.Daily, models.WeatherDaily{
+			Date:                     day.Dt,
+			Sunrise:                  day.Sunrise,
+			Sunset:                   day.Sunset,
+			TemperatureMin:           convertTemperature(day.Temp.Min, imperial),
+			TemperatureMax:           convertTemperature(day.Temp.Max, imperial),
+			TemperatureDay:           convertTemperature(day.Temp.Day, imperial),
+			FeelsLike:                convertTemperature(day.FeelsLike.Day, imperial),
+			PrecipitationProbability: math.Round(day.Pop * 100),
+			Precipitation:            convertPrecipitation(day.Rain, imperial),
+			UvIndex:                  day.Uvi,
+			Wind: models.WeatherWind{
+				Speed:     convertSpeed(day.WindSpeed, imperial),
+				Gust:      convertSpeed(day.WindGust, imperial),
+				Direction: day.WindDeg,
+			},
+			Condition: firstCondition(day.Weather, options.Lang),
+		})
+	}
+
+	return weather, nil
+}
+
+// NormaliseHourly - Parses provider hourly data into the normalised WeatherHourly model
+func NormaliseHourly(data []byte, options models.WeatherOptions) (*models.WeatherHourly, error) {
+	raw, err := parseWeather(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var hourly []models.OpenWeatherHourly
+	if err := unmarshalSection(raw.Hourly, &hourly, "hourly"); err != nil {
+		return nil, err
+	}
+
+	imperial := options.Units == models.UnitsImperial
+
+	weather := &models.WeatherHourly{
+		WeatherMeta: weatherMeta(raw, options),
+		Hourly:      make([]models.WeatherHour, 0, len(hourly)),
+	}
+
+	for _, hour := range hourly {
+		weather.Hourly = append(weather.Hourly, models.WeatherHour{
+			Time:                     hour.Dt,
+			Temperature:              convertTemperature(hour.Temp, imperial),
+			FeelsLike:                convertTemperature(hour.FeelsLike, imperial),
+			Humidity:                 hour.Humidity,
+			Pressure:                 hour.Pressure,
+			CloudCover:               hour.Clouds,
+			UvIndex:                  hour.Uvi,
+			PrecipitationProbability: math.Round(hour.Pop * 100),
+			Precipitation:            convertPrecipitation(hour.Rain.OneHour+hour.Snow.OneHour, imperial),
+			Wind: models.WeatherWind{
+				Speed:     convertSpeed(hour.WindSpeed, imperial),
+				Gust:      convertSpeed(hour.WindGust, imperial),
+				Direction: hour.WindDeg,
+			},
+			Condition: firstCondition(hour.Weather, options.Lang),
+		})
+	}
+
+	return weather, nil
+}
+
+// NormaliseMinutely - Parses provider minutely data into the normalised WeatherMinutely model
+func NormaliseMinutely(data []byte, options models.WeatherOptions) (*models.WeatherMinutely, error) {
+	raw, err := parseWeather(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var minutely []models.OpenWeatherMinutely
+	if err := unmarshalSection(raw.Minutely, &minutely, "minutely"); err != nil {
+		return nil, err
+	}
+
+	imperial := options.Units == models.UnitsImperial
+
+	weather := &models.WeatherMinutely{
+		WeatherMeta:     weatherMeta(raw, options),
+		IntervalMinutes: 1,
+		Minutely:        make([]models.WeatherMinute, 0, len(minutely)),
+	}
+
+	// Interval is taken from the data (OpenWeather is per minute, Open-Meteo is per 15 minutes)
+	if len(minutely) > 1 {
+		weather.IntervalMinutes = int((minutely[1].Dt - minutely[0].Dt) / 60)
+	}
+
+	for _, minute := range minutely {
+		weather.Minutely = append(weather.Minutely, models.WeatherMinute{
+			Time:          minute.Dt,
+			Precipitation: convertPrecipitation(minute.Precipitation, imperial),
+		})
+	}
+
+	return weather, nil
+}
+
+// NormaliseAlerts - Parses provider alerts into the normalised WeatherAlerts model (Alert text is not translated)
+func NormaliseAlerts(data []byte, options models.WeatherOptions) (*models.WeatherAlerts, error) {
+	raw, err := parseWeather(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// OpenWeather leaves 'alerts' out entirely when there are no active alerts
+	var alerts []models.OpenWeatherAlert
+	if err := unmarshalSection(raw.Alerts, &alerts, "alerts"); err != nil {
+		return nil, err
+	}
+
+	weather := &models.WeatherAlerts{
+		WeatherMeta: weatherMeta(raw, options),
+		Alerts:      make([]models.WeatherAlert, 0, len(alerts)),
+	}
+
+	for _, alert := range alerts {
+		weather.Alerts = append(weather.Alerts, models.WeatherAlert{
+			Sender:      alert.SenderName,
+			Event:       alert.Event,
+			Start:       alert.Start,
+			End:         alert.End,
+			Description: alert.Description,
+			Tags:        alert.Tags,
+		})
+	}
+
+	return weather, nil
+}
+
+func parseWeather(data []byte) (models.OpenWeatherRequest, error) {
+	var raw models.OpenWeatherRequest
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return raw, fmt.Errorf("invalid weather data: %s", err)
+	}
+	return raw, nil
+}
+
+// unmarshalSection - Unmarshals an optional section of the provider data (Missing sections are left empty)
+func unmarshalSection(section json.RawMessage, target any, name string) error {
+	if len(section) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(section, target); err != nil {
+		return fmt.Errorf("invalid %s weather data: %s", name, err)
+	}
+	return nil
+}
+
+func weatherMeta(raw models.OpenWeatherRequest, options models.WeatherOptions) models.WeatherMeta {
+	return models.WeatherMeta{
+		SchemaVersion:  models.WeatherSchemaNormalised,
+		Units:          options.Units,
+		UnitLabels:     unitLabels(options.Units == models.UnitsImperial),
+		Lang:           options.Lang,
+		Lat:            raw.Lat,
+		Lon:            raw.Long,
+		Timezone:       raw.Timezone,
+		TimezoneOffset: raw.Timezone_Offset,
+	}
+}
+
+func firstCondition(conditions []models.OpenWeatherCondition, lang string) models.WeatherCondition {
+	if len(conditions) == 0 {
+		return NewWeatherCondition(UnknownConditionId, "", lang)
+	}
+	return NewWeatherCondition(conditions[0].Id, conditions[0].Icon, lang)
+}
+
+func unitLabels(imperial bool) models.WeatherUnits {
+	if imperial {
+		return models.WeatherUnits{Temperature: "°F", WindSpeed: "mph", Precipitation: "in", Pressure: "hPa"}
+	}
+	return models.WeatherUnits{Temperature: "°C", WindSpeed: "m/s", Precipitation: "mm", Pressure: "hPa"}
+}
+
+// Conversions (Metric -> Imperial) - Rounded to 1 decimal place
+
+func convertTemperature(celsius float64, imperial bool) float64 {
+	if imperial {
+		return round1(celsius*9/5 + 32)
+	}
+	return round1(celsius)
+}
+
+func convertSpeed(metresPerSecond float64, imperial bool) float64 {
+	if imperial {
+		return round1(metresPerSecond * 2.236936)
+	}
+	return round1(metresPerSecond)
+}
+
+func convertPrecipitation(millimetres float64, imperial bool) float64 {
+	if imperial {
+		return math.Round(millimetres/25.4*100) / 100
+	}
+	return round1(millimetres)
+}
+
+func round1(value float64) float64 {
+	return math.Round(value*10) / 10
+}
@@ -0,0 +1,124 @@
+package services
+
+import "github.com/MCantyDev/city-explorer-server/internal/models"
+
+// Condition id used when the provider reports no condition (OpenWeather ids start at 200)
+const UnknownConditionId = 0
+
+// Languages with translated condition text (Falls back to English)
+var WeatherLanguages = []string{"en", "fr", "de", "es", "it"}
+
+// Condition Keys mapped to their translations (Ordered the same as WeatherLanguages)
+var conditionText = map[string][]string{
+	"thunderstorm":     {"Thunderstorm", "Orage", "Gewitter", "Tormenta", "Temporale"},
+	"drizzle":          {"Drizzle", "Bruine", "Nieselregen", "Llovizna", "Pioggerella"},
+	"light_rain":       {"Light rain", "Pluie légère", "Leichter Regen", "Lluvia ligera", "Pioggia leggera"},
+	"rain":             {"Rain", "Pluie", "Regen", "Lluvia", "Pioggia"},
+	"heavy_rain":       {"Heavy rain", "Forte pluie", "Starker Regen", "Lluvia intensa", "Pioggia forte"},
+	"freezing_rain":    {"Freezing rain", "Pluie verglaçante", "Gefrierender Regen", "Lluvia helada", "Pioggia gelata"},
+	"showers":          {"Showers", "Averses", "Schauer", "Chubascos", "Rovesci"},
+	"light_snow":       {"Light snow", "Neige légère", "Leichter Schneefall", "Nevada ligera", "Neve leggera"},
+	"snow":             {"Snow", "Neige", "Schnee", "Nieve", "Neve"},
+	"heavy_snow":       {"Heavy snow", "Fortes chutes de neige", "Starker Schneefall", "Nevada intensa", "Neve forte"},
+	"sleet":            {"Sleet", "Neige fondue", "Schneeregen", "Aguanieve", "Nevischio"},
+	"mist":             {"Mist", "Brume", "Dunst", "Neblina", "Foschia"},
+	"smoke":            {"Smoke", "Fumée", "Rauch", "Humo", "Fumo"},
+	"haze":             {"Haze", "Brume sèche", "Dunstschleier", "Calima", "Caligine"},
+	"dust":             {"Dust", "Poussière", "Staub", "Polvo", "Polvere"},
+	"fog":              {"Fog", "Brouillard", "Nebel", "Niebla", "Nebbia"},
+	"squall":           {"Squalls", "Bourrasques", "Böen", "Turbonadas", "Burrasche"},
+	"tornado":          {"Tornado", "Tornade", "Tornado", "Tornado", "Tornado"},
+	"clear":            {"Clear sky", "Ciel dégagé", "Klarer Himmel", "Cielo despejado", "Cielo sereno"},
+	"few_clouds":       {"Few clouds", "Quelques nuages", "Leicht bewölkt", "Pocas nubes", "Poco nuvoloso"},
+	"scattered_clouds": {"Scattered clouds", "Nuages épars", "Aufgelockert bewölkt", "Nubes dispersas", "Nubi sparse"},
+	"broken_clouds":    {"Broken clouds", "Nuageux", "Überwiegend bewölkt", "Nubes fragmentadas", "Nuvoloso"},
+	"overcast":         {"Overcast", "Couvert", "Bedeckt", "Cubierto", "Coperto"},
+	"unknown":          {"Unknown", "Inconnu", "Unbekannt", "Desconocido", "Sconosciuto"},
+}
+
+// IsWeatherLanguage - Checks if condition text can be localised into a language
+func IsWeatherLanguage(lang string) bool {
+	return languageIndex(lang) >= 0
+}
+
+// NewWeatherCondition - Builds a normalised condition from an OpenWeather condition id
+func NewWeatherCondition(id int, icon string, lang string) models.WeatherCondition {
+	code, group := conditionCode(id)
+
+	index := languageIndex(lang)
+	if index < 0 {
+		index = 0
+	}
+
+	return models.WeatherCondition{
+		Id:    id,
+		Code:  code,
+		Group: group,
+		Text:  conditionText[code][index],
+		Icon:  icon,
+	}
+}
+
+// conditionCode - Maps an OpenWeather condition id to a condition key and group
+// (See https://openweathermap.org/weather-conditions)
+func conditionCode(id int) (string, string) {
+	switch {
+	case id >= 200 && id < 300:
+		return "thunderstorm", "thunderstorm"
+	case id >= 300 && id < 400:
+		return "drizzle", "drizzle"
+	case id == 500:
+		return "light_rain", "rain"
+	case id == 501:
+		return "rain", "rain"
+	case id >= 502 && id <= 504:
+		return "heavy_rain", "rain"
+	case id == 511:
+		return "freezing_rain", "rain"
+	case id >= 520 && id < 600:
+		return "showers", "rain"
+	case id == 600 || id == 620:
+		return "light_snow", "snow"
+	case id == 601 || id == 621:
+		return "snow", "snow"
+	case id == 602 || id == 622:
+		return "heavy_snow", "snow"
+	case id >= 611 && id <= 616:
+		return "sleet", "snow"
+	case id == 701:
+		return "mist", "atmosphere"
+	case id == 711:
+		return "smoke", "atmosphere"
+	case id == 721:
+		return "haze", "atmosphere"
+	case id == 731 || id == 751 || id == 761 || id == 762:
+		return "dust", "atmosphere"
+	case id == 741:
+		return "fog", "atmosphere"
+	case id == 771:
+		return "squall", "atmosphere"
+	case id == 781:
+		return "tornado", "atmosphere"
+	case id == 801:
+		return "few_clouds", "clouds"
+	case id == 802:
+		return "scattered_clouds", "clouds"
+	case id == 803:
+		return "broken_clouds", "clouds"
+	case id == 804:
+		return "overcast", "clouds"
+	case id == 800:
+		return "clear", "clear"
+	default:
+		return "unknown", "unknown"
+	}
+}
+
+func languageIndex(lang string) int {
+	for i, l := range WeatherLanguages {
+		if l == lang {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,361 @@
+package services
+
+/* Weather Providers
+
+- OpenWeather One Call 3.0 (Requires OPENWEATHER_KEY)
+- Open-Meteo (Keyless)
+- Current/Daily, Hourly, Minutely and Alerts forecasts (Open-Meteo has no Alerts)
+- Every provider returns data in the OpenWeather One Call shape (models.OpenWeatherRequest)
+- The configured provider is tried first, the other provider is used as a fallback
+*/
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/MCantyDev/city-explorer-server/internal/config"
+	"github.com/MCantyDev/city-explorer-server/internal/models"
+)
+
+// Forecast Kinds (Each is fetched and cached separately)
+const (
+	ForecastDaily    = "daily" // Current + Daily
+	ForecastHourly   = "hourly"
+	ForecastMinutely = "minutely"
+	ForecastAlerts   = "alerts"
+)
+
+type WeatherProvider interface {
+	Name() string
+	Available() bool                                                       // False if the provider is missing config (e.g. API Key)
+	Supports(forecast string) bool                                         // False if the provider has no data for a forecast kind
+	FetchForecast(forecast string, lat string, lon string) ([]byte, error) // Returns OpenWeather One Call shaped JSON
+}
+
+type openWeatherProvider struct{}
+type openMeteoProvider struct{}
+
+var weatherProviders = map[string]WeatherProvider{
+	config.WeatherProviderOpenWeather: openWeatherProvider{},
+	config.WeatherProviderOpenMeteo:   openMeteoProvider{},
+}
+
+// FetchWeather - Fetches current and daily weather
+func FetchWeather(lat string, lon string) ([]byte, error) {
+	return FetchForecast(ForecastDaily, lat, lon)
+}
+
+// FetchForecast - Fetches a forecast kind from the configured provider, falling back to the other provider on failure
+func FetchForecast(forecast string, lat string, lon string) ([]byte, error) {
+	var failures []string
+
+	for _, provider := range weatherProviderOrder() {
+		if !provider.Available() || !provider.Supports(forecast) {
+			continue
+		}
+
+		data, err := provider.FetchForecast(forecast, lat, lon)
+		if err == nil {
+			return data, nil
+		}
+
+		fmt.Printf("Weather provider '%s' failed: %s\n", provider.Name(), err)
+		failures = append(failures, fmt.Sprintf("%s: %s", provider.Name(), err))
+	}
+
+	if len(failures) == 0 {
+		return nil, fmt.Errorf("no weather provider is available for '%s' forecasts", forecast)
+	}
+	return nil, fmt.Errorf("all weather providers failed (%s)", strings.Join(failures, ", "))
+}
+
+// weatherProviderOrder - Primary provider first, followed by the fallback
+func weatherProviderOrder() []WeatherProvider {
+	primary := config.Cfg.Weather.Provider
+	order := []WeatherProvider{weatherProviders[primary]}
+
+	for name, provider := range weatherProviders {
+		if name != primary {
+			order = append(order, provider)
+		}
+	}
+	return order
+}
+
+// OpenWeather
+
+func (openWeatherProvider) Name() string {
+	return config.Cfg.OpenWeatherAPI.Name
+}
+
+func (openWeatherProvider) Available() bool {
+	return config.Cfg.OpenWeatherAPI.Key != ""
+}
+
+func (openWeatherProvider) Supports(forecast string) bool {
+	_, ok := openWeatherAPI(forecast)
+	return ok
+}
+
+func (openWeatherProvider) FetchForecast(forecast string, lat string, lon string) ([]byte, error) {
+	api, _ := openWeatherAPI(forecast)
+
+	url := fmt.Sprintf(api.URL, lat, lon, api.Key)
+	if !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("'%s' URL is not set up properly", api.Name)
+	}
+
+	return FetchExternalAPI(url)
+}
+
+func openWeatherAPI(forecast string) (config.SecureExternalAPI, bool) {
+	switch forecast {
+	case ForecastDaily:
+		return config.Cfg.OpenWeatherAPI, true
+	case ForecastHourly:
+		return config.Cfg.OpenWeatherHourlyAPI, true
+	case ForecastMinutely:
+		return config.Cfg.OpenWeatherMinutelyAPI, true
+	case ForecastAlerts:
+		return config.Cfg.OpenWeatherAlertsAPI, true
+	}
+	return config.SecureExternalAPI{}, false
+}
+
+// Open-Meteo (No Alerts)
+
+func (openMeteoProvider) Name() string {
+	return config.Cfg.OpenMeteoAPI.Name
+}
+
+func (openMeteoProvider) Available() bool {
+	return true
+}
+
+func (openMeteoProvider) Supports(forecast string) bool {
+	return forecast == ForecastDaily || forecast == ForecastHourly || forecast == ForecastMinutely
+}
+
+func (openMeteoProvider) FetchForecast(forecast string, lat string, lon string) ([]byte, error) {
+	var api config.ExternalAPI
+	switch forecast {
+	case ForecastDaily:
+		api = config.Cfg.OpenMeteoAPI
+	case ForecastHourly:
+		api = config.Cfg.OpenMeteoHourlyAPI
+	case ForecastMinutely:
+		api = config.Cfg.OpenMeteoMinutelyAPI
+	default:
+		return nil, fmt.Errorf("'%s' does not support '%s' forecasts", config.Cfg.OpenMeteoAPI.Name, forecast)
+	}
+
+	url := fmt.Sprintf(api.URL, lat, lon)
+	if !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("'%s' URL is not set up properly", api.Name)
+	}
+
+	data, err := FetchExternalAPI(url)
+	if err != nil {
+		return nil, err
+	}
+
+	var mapped models.OpenWeatherRequest
+	switch forecast {
+	case ForecastHourly:
+		var meteo models.OpenMeteoHourlyRequest
+		err = json.Unmarshal(data, &meteo)
+		mapped = mapOpenMeteoHourly(meteo)
+	case ForecastMinutely:
+		var meteo models.OpenMeteoMinutelyRequest
+		err = json.Unmarshal(data, &meteo)
+		mapped = mapOpenMeteoMinutely(meteo)
+	default:
+		var meteo models.OpenMeteoRequest
+		err = json.Unmarshal(data, &meteo)
+		mapped = mapOpenMeteo(meteo)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON from %s: %s", api.Name, err)
+	}
+
+	return json.Marshal(mapped)
+}
+
+// mapOpenMeteo - Maps an Open-Meteo forecast into the OpenWeather One Call shape
+func mapOpenMeteo(meteo models.OpenMeteoRequest) models.OpenWeatherRequest {
+	daily := make([]models.OpenWeatherDaily, len(meteo.Daily.Time))
+	for i := range daily {
+		day := &daily[i]
+		day.Dt = meteo.Daily.Time[i]
+		day.Sunrise = valueAt(meteo.Daily.Sunrise, i)
+		day.Sunset = valueAt(meteo.Daily.Sunset, i)
+		day.Temp.Min = valueAt(meteo.Daily.TemperatureMin, i)
+		day.Temp.Max = valueAt(meteo.Daily.TemperatureMax, i)
+		day.Temp.Day = (day.Temp.Min + day.Temp.Max) / 2
+		day.FeelsLike.Day = valueAt(meteo.Daily.ApparentTemperatureMax, i)
+		day.WindSpeed = valueAt(meteo.Daily.WindSpeedMax, i)
+		day.WindGust = valueAt(meteo.Daily.WindGustsMax, i)
+		day.WindDeg = valueAt(meteo.Daily.WindDirectionDominant, i)
+		day.Pop = valueAt(meteo.Daily.PrecipitationProbabilityMax, i) / 100 // Open-Meteo uses %, OpenWeather uses 0-1
+		day.Rain = valueAt(meteo.Daily.PrecipitationSum, i)
+		day.Uvi = valueAt(meteo.Daily.UvIndexMax, i)
+		day.Weather = []models.OpenWeatherCondition{wmoToOpenWeather(valueAt(meteo.Daily.WeatherCode, i), true)}
+	}
+
+	current := models.OpenWeatherCurrent{
+		Dt:        meteo.Current.Time,
+		Temp:      meteo.Current.Temperature,
+		FeelsLike: meteo.Current.ApparentTemperature,
+		Pressure:  meteo.Current.PressureMsl,
+		Humidity:  meteo.Current.RelativeHumidity,
+		Uvi:       meteo.Current.UvIndex,
+		Clouds:    meteo.Current.CloudCover,
+		WindSpeed: meteo.Current.WindSpeed,
+		WindDeg:   meteo.Current.WindDirection,
+		WindGust:  meteo.Current.WindGusts,
+		Weather:   []models.OpenWeatherCondition{wmoToOpenWeather(meteo.Current.WeatherCode, meteo.Current.IsDay == 1)},
+	}
+	if len(daily) > 0 {
+		current.Sunrise = daily[0].Sunrise
+		current.Sunset = daily[0].Sunset
+	}
+
+	currentJSON, _ := json.Marshal(current)
+	dailyJSON, _ := json.Marshal(daily)
+
+	return models.OpenWeatherRequest{
+		Lat:             meteo.Latitude,
+		Long:            meteo.Longitude,
+		Timezone:        meteo.Timezone,
+		Timezone_Offset: meteo.UtcOffsetSeconds,
+		Current:         currentJSON,
+		Daily:           dailyJSON,
+	}
+}
+
+// mapOpenMeteoHourly - Maps an Open-Meteo hourly forecast into the OpenWeather One Call shape
+func mapOpenMeteoHourly(meteo models.OpenMeteoHourlyRequest) models.OpenWeatherRequest {
+	hourly := make([]models.OpenWeatherHourly, len(meteo.Hourly.Time))
+	for i := range hourly {
+		hour := &hourly[i]
+		hour.Dt = meteo.Hourly.Time[i]
+		hour.Temp = valueAt(meteo.Hourly.Temperature, i)
+		hour.FeelsLike = valueAt(meteo.Hourly.ApparentTemperature, i)
+		hour.Pressure = valueAt(meteo.Hourly.PressureMsl, i)
+		hour.Humidity = valueAt(meteo.Hourly.RelativeHumidity, i)
+		hour.Uvi = valueAt(meteo.Hourly.UvIndex, i)
+		hour.Clouds = valueAt(meteo.Hourly.CloudCover, i)
+		hour.WindSpeed = valueAt(meteo.Hourly.WindSpeed, i)
+		hour.WindDeg = valueAt(meteo.Hourly.WindDirection, i)
+		hour.WindGust = valueAt(meteo.Hourly.WindGusts, i)
+		hour.Pop = valueAt(meteo.Hourly.PrecipitationProbability, i) / 100
+		hour.Rain.OneHour = valueAt(meteo.Hourly.Precipitation, i)
+		hour.Weather = []models.OpenWeatherCondition{wmoToOpenWeather(valueAt(meteo.Hourly.WeatherCode, i), valueAt(meteo.Hourly.IsDay, i) == 1)}
+	}
+
+	hourlyJSON, _ := json.Marshal(hourly)
+
+	return models.OpenWeatherRequest{
+		Lat:             meteo.Latitude,
+		Long:            meteo.Longitude,
+		Timezone:        meteo.Timezone,
+		Timezone_Offset: meteo.UtcOffsetSeconds,
+		Hourly:          hourlyJSON,
+	}
+}
+
+// mapOpenMeteoMinutely - Maps Open-Meteo 15 minute precipitation into the OpenWeather One Call shape
+func mapOpenMeteoMinutely(meteo models.OpenMeteoMinutelyRequest) models.OpenWeatherRequest {
+	minutely := make([]models.OpenWeatherMinutely, len(meteo.Minutely15.Time))
+	for i := range minutely {
+		minutely[i].Dt = meteo.Minutely15.Time[i]
+		minutely[i].Precipitation = valueAt(meteo.Minutely15.Precipitation, i) * 4 // mm per 15 minutes -> mm/h (OpenWeather)
+	}
+
+	minutelyJSON, _ := json.Marshal(minutely)
+
+	return models.OpenWeatherRequest{
+		Lat:             meteo.Latitude,
+		Long:            meteo.Longitude,
+		Timezone:        meteo.Timezone,
+		Timezone_Offset: meteo.UtcOffsetSeconds,
+		Minutely:        minutelyJSON,
+	}
+}
+
+// wmoToOpenWeather - Maps a WMO weather interpretation code (Open-Meteo) to an OpenWeather condition (Missing and unmapped codes are unknown)
+func wmoToOpenWeather(wmoCode *int, isDay bool) models.OpenWeatherCondition {
+	if wmoCode == nil {
+		return unknownCondition
+	}
+
+	var condition models.OpenWeatherCondition
+	switch *wmoCode {
+	case 0:
+		condition = models.OpenWeatherCondition{Id: 800, Main: "Clear", Description: "clear sky", Icon: "01"}
+	case 1:
+		condition = models.OpenWeatherCondition{Id: 801, Main: "Clouds", Description: "few clouds", Icon: "02"}
+	case 2:
+		condition = models.OpenWeatherCondition{Id: 802, Main: "Clouds", Description: "scattered clouds", Icon: "03"}
+	case 3:
+		condition = models.OpenWeatherCondition{Id: 804, Main: "Clouds", Description: "overcast clouds", Icon: "04"}
+	case 45, 48:
+		condition = models.OpenWeatherCondition{Id: 741, Main: "Fog", Description: "fog", Icon: "50"}
+	case 51:
+		condition = models.OpenWeatherCondition{Id: 300, Main: "Drizzle", Description: "light intensity drizzle", Icon: "09"}
+	case 53:
+		condition = models.OpenWeatherCondition{Id: 301, Main: "Drizzle", Description: "drizzle", Icon: "09"}
+	case 55:
+		condition = models.OpenWeatherCondition{Id: 302, Main: "Drizzle", Description: "heavy intensity drizzle", Icon: "09"}
+	case 56, 57, 66, 67:
+		condition = models.OpenWeatherCondition{Id: 511, Main: "Rain", Description: "freezing rain", Icon: "13"}
+	case 61:
+		condition = models.OpenWeatherCondition{Id: 500, Main: "Rain", Description: "light rain", Icon: "10"}
+	case 63:
+		condition = models.OpenWeatherCondition{Id: 501, Main: "Rain", Description: "moderate rain", Icon: "10"}
+	case 65:
+		condition = models.OpenWeatherCondition{Id: 502, Main: "Rain", Description: "heavy intensity rain", Icon: "10"}
+	case 71, 77:
+		condition = models.OpenWeatherCondition{Id: 600, Main: "Snow", Description: "light snow", Icon: "13"}
+	case 73:
+		condition = models.OpenWeatherCondition{Id: 601, Main: "Snow", Description: "snow", Icon: "13"}
+	case 75:
+		condition = models.OpenWeatherCondition{Id: 602, Main: "Snow", Description: "heavy snow", Icon: "13"}
+	case 80:
+		condition = models.OpenWeatherCondition{Id: 520, Main: "Rain", Description: "light intensity shower rain", Icon: "09"}
+	case 81:
+		condition = models.OpenWeatherCondition{Id: 521, Main: "Rain", Description: "shower rain", Icon: "09"}
+	case 82:
+		condition = models.OpenWeatherCondition{Id: 522, Main: "Rain", Description: "heavy intensity shower rain", Icon: "09"}
+	case 85:
+		condition = models.OpenWeatherCondition{Id: 620, Main: "Snow", Description: "light shower snow", Icon: "13"}
+	case 86:
+		condition = models.OpenWeatherCondition{Id: 622, Main: "Snow", Description: "heavy shower snow", Icon: "13"}
+	case 95:
+		condition = models.OpenWeatherCondition{Id: 211, Main: "Thunderstorm", Description: "thunderstorm", Icon: "11"}
+	case 96, 99:
+		condition = models.OpenWeatherCondition{Id: 202, Main: "Thunderstorm", Description: "thunderstorm with heavy rain", Icon: "11"}
+	default:
+		return unknownCondition
+	}
+
+	if isDay {
+		condition.Icon += "d"
+	} else {
+		condition.Icon += "n"
+	}
+	return condition
+}
+
+// No OpenWeather id or icon matches an unknown condition
+var unknownCondition = models.OpenWeatherCondition{Id: UnknownConditionId, Main: "Unknown", Description: "unknown"}
+
+// valueAt - Safely reads from Open-Meteo arrays (Arrays can be shorter than 'time' if a variable is unavailable)
+func valueAt[T any](values []T, i int) T {
+	var zero T
+	if i < len(values) {
+		return values[i]
+	}
+	return zero
+}